@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/supermicah/go-framework-admin/internal/bootstrap"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/internal/wirex"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// RBACCmd The function defines a CLI command to export the RBAC configuration (menus, roles and role bindings of the
+// users) to a JSON or YAML bundle file, to import it into another database, and to validate the menu resources against
+// the API routes.
+func RBACCmd() *cli.Command {
+	configFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:        "workdir",
+			Aliases:     []string{"d"},
+			Usage:       "Working directory",
+			DefaultText: "configs",
+			Value:       "configs",
+		},
+		&cli.StringFlag{
+			Name:        "config",
+			Aliases:     []string{"c"},
+			Usage:       "Runtime configuration files or directory (relative to workdir, multiple separated by commas)",
+			DefaultText: "dev",
+			Value:       "dev",
+		},
+	}
+	flags := append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     "file",
+			Aliases:  []string{"f"},
+			Usage:    "Bundle file (YAML for .yaml and .yml files, else JSON)",
+			Required: true,
+		},
+		&cli.Int64Flag{
+			Name:  "tenant",
+			Usage: "Tenant of the roles and users",
+		},
+	}, configFlags...)
+
+	run := func(c *cli.Context, fn func(ctx context.Context, injector *wirex.Injector) error) error {
+		ctx := util.NewTenantID(context.Background(), c.Int64("tenant"))
+		return bootstrap.RunCommand(ctx, bootstrap.RunConfig{
+			WorkDir: c.String("workdir"),
+			Configs: c.String("config"),
+		}, fn)
+	}
+
+	return &cli.Command{
+		Name:  "rbac",
+		Usage: "Export or import the RBAC configuration",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "export",
+				Usage: "Export the menus, roles and optionally the role bindings of the users to a bundle file",
+				Flags: append(flags, &cli.BoolFlag{
+					Name:  "users",
+					Usage: "Include the role bindings of the users",
+				}),
+				Action: func(c *cli.Context) error {
+					file := c.String("file")
+					return run(c, func(ctx context.Context, injector *wirex.Injector) error {
+						bundle, err := injector.M.RBAC.BundleAPI.BundleBIZ.Export(ctx, schema.BundleExportParam{
+							Users: c.Bool("users"),
+						})
+						if err != nil {
+							return err
+						}
+						data, err := bundle.Marshal(schema.BundleFormatOf(file))
+						if err != nil {
+							return err
+						} else if err := os.WriteFile(file, data, 0644); err != nil {
+							return err
+						}
+						fmt.Printf("exported %d roles and %d users to %s \n", len(bundle.Roles), len(bundle.Users), file)
+						return nil
+					})
+				},
+			},
+			{
+				Name:  "import",
+				Usage: "Import a bundle file, creating or updating the menus and roles by code",
+				Flags: flags,
+				Action: func(c *cli.Context) error {
+					file := c.String("file")
+					data, err := os.ReadFile(file)
+					if err != nil {
+						return err
+					}
+					bundle, err := schema.ParseBundle(data, schema.BundleFormatOf(file))
+					if err != nil {
+						return err
+					}
+					return run(c, func(ctx context.Context, injector *wirex.Injector) error {
+						result, err := injector.M.RBAC.BundleAPI.BundleBIZ.Import(ctx, bundle)
+						if err != nil {
+							return err
+						}
+						fmt.Println(result.Menus.String())
+						fmt.Printf("created roles: %v \n", result.CreatedRoles)
+						fmt.Printf("updated roles: %v \n", result.UpdatedRoles)
+						fmt.Printf("bound users: %v \n", result.BoundUsers)
+						fmt.Printf("skipped users: %v \n", result.SkippedUsers)
+						return nil
+					})
+				},
+			},
+			{
+				Name:  "routes",
+				Usage: "Validate the menu resources against the API routes, failing if a resource is stale or a route is uncovered",
+				Flags: configFlags,
+				Action: func(c *cli.Context) error {
+					return run(c, func(ctx context.Context, injector *wirex.Injector) error {
+						if err := bootstrap.LoadRoutes(ctx, injector); err != nil {
+							return err
+						}
+						report, err := injector.M.RBAC.RouteAPI.RouteBIZ.Report(ctx)
+						if err != nil {
+							return err
+						}
+						fmt.Println(report.String())
+						if !report.Valid() {
+							return cli.Exit("the menu resources do not match the routes", 1)
+						}
+						return nil
+					})
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/wirex"
+	"github.com/supermicah/go-framework-admin/pkg/logging"
+)
+
+// RunCommand initializes the modules with the configuration and logging like the service, runs the command function
+// with the injector instead of the HTTP server, and releases the modules.
+func RunCommand(ctx context.Context, runCfg RunConfig, fn func(ctx context.Context, injector *wirex.Injector) error) error {
+	defer func() {
+		if err := zap.L().Sync(); err != nil {
+			fmt.Printf("failed to sync zap logger: %s \n", err.Error())
+		}
+	}()
+
+	// Load configuration.
+	config.MustLoad(runCfg.WorkDir, strings.Split(runCfg.Configs, ",")...)
+	config.C.General.WorkDir = runCfg.WorkDir
+	config.C.PreLoad()
+
+	// Initialize logger.
+	cleanLoggerFn, err := logging.InitWithConfig(ctx, &config.C.Logger, initLoggerHook)
+	if err != nil {
+		return err
+	}
+	if cleanLoggerFn != nil {
+		defer cleanLoggerFn()
+	}
+	ctx = logging.NewTag(ctx, logging.TagKeyMain)
+
+	// Build injector.
+	injector, cleanInjectorFn, err := wirex.BuildInjector(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanInjectorFn()
+
+	if err := injector.M.Init(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := injector.M.Release(ctx); err != nil {
+			logging.Context(ctx).Error("failed to release injector", zap.Error(err))
+		}
+	}()
+
+	return fn(ctx, injector)
+}
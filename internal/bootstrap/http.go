@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/casbin/casbin/v2"
@@ -34,6 +35,9 @@ func startHTTPServer(ctx context.Context, injector *wirex.Injector) (func(), err
 	e.GET("/health", func(c *gin.Context) {
 		util.ResOK(c)
 	})
+	e.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		util.ResJSON(c, http.StatusOK, injector.Auth.GetJWKS())
+	})
 	e.Use(middleware.RecoveryWithConfig(middleware.RecoveryConfig{
 		Skip: config.C.Middleware.Recovery.Skip,
 	}))
@@ -55,6 +59,7 @@ func startHTTPServer(ctx context.Context, injector *wirex.Injector) (func(), err
 	if err := injector.M.RegisterRouters(ctx, e); err != nil {
 		return nil, err
 	}
+	injector.M.RBAC.RouteAPI.RouteBIZ.SetRoutes(e.Routes(), allowedPrefixes)
 
 	// Register swagger
 	if !config.C.General.DisableSwagger {
@@ -104,6 +109,17 @@ func startHTTPServer(ctx context.Context, injector *wirex.Injector) (func(), err
 	}, nil
 }
 
+// LoadRoutes Register the routers of the modules in a bare engine to collect the routes without serving them.
+func LoadRoutes(ctx context.Context, injector *wirex.Injector) error {
+	gin.SetMode(gin.ReleaseMode)
+	e := gin.New()
+	if err := injector.M.RegisterRouters(ctx, e); err != nil {
+		return err
+	}
+	injector.M.RBAC.RouteAPI.RouteBIZ.SetRoutes(e.Routes(), injector.M.RouterPrefixes())
+	return nil
+}
+
 func useHTTPMiddlewares(_ context.Context, e *gin.Engine, injector *wirex.Injector, allowedPrefixes []string) error {
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		Enable:                 config.C.Middleware.CORS.Enable,
@@ -140,6 +156,15 @@ func useHTTPMiddlewares(_ context.Context, e *gin.Engine, injector *wirex.Inject
 		MaxContentLen:       config.C.Middleware.CopyBody.MaxContentLen,
 	}))
 
+	e.Use(middleware.TenantWithConfig(middleware.TenantConfig{
+		Enable:              config.C.Middleware.Tenant.Enable,
+		AllowedPathPrefixes: allowedPrefixes,
+		SkippedPathPrefixes: config.C.Middleware.Tenant.SkippedPathPrefixes,
+		HeaderKey:           config.C.Middleware.Tenant.HeaderKey,
+		Domain:              config.C.Middleware.Tenant.Domain,
+		GetTenantID:         injector.M.RBAC.TenantAPI.TenantBIZ.GetTenantID,
+	}))
+
 	e.Use(middleware.AuthWithConfig(middleware.AuthConfig{
 		AllowedPathPrefixes: allowedPrefixes,
 		SkippedPathPrefixes: config.C.Middleware.Auth.SkippedPathPrefixes,
@@ -177,12 +202,16 @@ func useHTTPMiddlewares(_ context.Context, e *gin.Engine, injector *wirex.Inject
 			}
 			return false
 		},
-		GetEnforcer: func(c *gin.Context) *casbin.Enforcer {
+		GetEnforcer: func(c *gin.Context) *casbin.SyncedEnforcer {
 			return injector.M.RBAC.Casbinx.GetEnforcer()
 		},
 		GetSubjects: func(c *gin.Context) []string {
 			return util.FromUserCache(c.Request.Context()).ToRoleIDsStr()
 		},
+		GetDomain: func(c *gin.Context) string {
+			tenantID, _ := util.FromTenantID(c.Request.Context())
+			return strconv.FormatInt(tenantID, 10)
+		},
 	}))
 
 	if config.C.Util.Prometheus.Enable {
@@ -23,9 +23,12 @@ type General struct {
 	PprofAddr          string
 	DisableSwagger     bool
 	DisablePrintConfig bool
-	DefaultLoginPwd    string `default:"6351623c8cef86fefabfa7da046fc619"` // MD5(abc-123)
-	WorkDir            string // From command arguments
-	MenuFile           string // From schema.Menus (JSON/YAML)
+	DefaultLoginPwd    string            `default:"6351623c8cef86fefabfa7da046fc619"` // MD5(abc-123)
+	WorkDir            string            // From command arguments
+	MenuFile           string            // From schema.Menus (JSON/YAML)
+	MenuSyncMode       string            `default:"insert"` // insert (only create the missing menus), reconcile (apply the full diff with the file), dry-run (log the diff)
+	MenuLocaleFiles    map[string]string // Translations of the menu names by locale (like en-US = "menu_en.json"), merged by code path
+	DefaultLocale      string            `default:"zh-CN"` // Locale of the names of the menu file, served without a matching translation
 	DenyDeleteMenu     bool
 	HTTP               struct {
 		Addr            string `default:":8040"`
@@ -36,6 +39,17 @@ type General struct {
 		CertFile        string
 		KeyFile         string
 	}
+	PasswordPolicy struct {
+		MinLength     int      // minimum length of the plaintext password (0 means no limit)
+		RequireUpper  bool     // require an uppercase letter
+		RequireLower  bool     // require a lowercase letter
+		RequireDigit  bool     // require a digit
+		RequireSymbol bool     // require a symbol
+		DenyCommon    bool     // reject the built-in list of common passwords
+		DenyList      []string // additional passwords to reject (case-insensitive)
+		HistorySize   int      // reject the reuse of the last N passwords (0 means no history)
+		MaxAge        int      // days, login requires changing older passwords (0 means never expires)
+	}
 	Root struct {
 		ID       int64  `default:"1"`
 		Username string `default:"admin"`
@@ -82,6 +96,16 @@ type Storage struct {
 }
 
 type Util struct {
+	Bus struct {
+		Type  string `default:"memory"` // memory/redis
+		Redis struct {
+			Addr          string
+			Username      string
+			Password      string
+			DB            int
+			ChannelPrefix string `default:"bus:"`
+		}
+	}
 	Captcha struct {
 		Length    int    `default:"4"`
 		Width     int    `default:"400"`
@@ -95,6 +119,63 @@ type Util struct {
 			KeyPrefix string `default:"captcha:"`
 		}
 	}
+	Lockout struct {
+		Disable         bool
+		MaxAttempts     int `default:"5"`     // failed attempts per username before locking
+		MaxIPAttempts   int `default:"20"`    // failed attempts per client IP before locking
+		Window          int `default:"900"`   // seconds, failed attempts older than this are forgotten
+		LockDuration    int `default:"300"`   // seconds, doubled on each further lockout
+		MaxLockDuration int `default:"86400"` // seconds
+	}
+	TwoFactor struct {
+		Issuer           string // Issuer shown in authenticator apps (default General.AppName)
+		SecretKey        string // AES key to encrypt TOTP secrets (16/24/32 bytes, default built-in key)
+		ChallengeExpired int    `default:"300"` // seconds
+		MaxAttempts      int    `default:"5"`   // failed codes allowed per challenge
+		RecoveryCodes    int    `default:"10"`  // number of one-time recovery codes
+		Skew             int    `default:"1"`   // periods of clock drift accepted
+	}
+	Mail struct {
+		SmtpHost string
+		Port     int `default:"25"`
+		FromName string
+		FromMail string
+		UserName string
+		AuthCode string
+	}
+	PasswordReset struct {
+		Expired      int    `default:"1800"` // seconds
+		URL          string // Page to reset the password, the token is appended as the query parameter "token"
+		Subject      string `default:"Reset your password"`
+		TemplateFile string // HTML template of the email (default built-in template)
+	}
+	OIDC struct {
+		StateExpired int `default:"600"` // seconds, time allowed to sign in at the provider
+		Providers    []OIDCProvider
+	}
+	LDAP struct {
+		Enable             bool   // Authenticate users who fail the local login against the directory
+		URL                string // ldap://host:389 or ldaps://host:636
+		StartTLS           bool   // Upgrade the ldap:// connection with StartTLS
+		InsecureSkipVerify bool
+		CAFile             string // PEM certificates trusted for the directory, if empty then use the system pool
+		Timeout            int    `default:"10"` // seconds
+		BindDN             string // Service account searching the users and groups
+		BindPassword       string
+		BaseDN             string            // Base of the user search
+		UserFilter         string            `default:"(uid=%s)"` // %s is replaced by the escaped username, e.g. (sAMAccountName=%s) for Active Directory
+		UsernameAttribute  string            `default:"uid"`      // e.g. sAMAccountName for Active Directory
+		NameAttribute      string            `default:"cn"`
+		EmailAttribute     string            `default:"mail"`
+		SubjectAttribute   string            // Stable ID of the user (e.g. entryUUID, objectGUID), if empty then use the DN
+		GroupAttribute     string            `default:"memberOf"` // Attribute of the user listing the DNs of its groups
+		GroupBaseDN        string            // Base of the group search, if empty then the groups are read from GroupAttribute
+		GroupFilter        string            `default:"(member=%s)"` // %s is replaced by the escaped DN of the user
+		RoleMapping        map[string]string // Group (DN or CN) => role code, if empty then group CNs are used as role codes
+		DefaultRoles       []string          // Role codes given to provisioned users
+		AutoCreate         bool              // Provision unknown users on first successful bind
+		SyncRoles          bool              // Replace the roles of the user with the mapped groups on each login
+	}
 	Prometheus struct {
 		Enable         bool
 		Port           int    `default:"9100"`
@@ -106,8 +187,27 @@ type Util struct {
 	}
 }
 
+// OIDCProvider OpenID Connect provider for single sign-on
+type OIDCProvider struct {
+	Name          string // Used in the path /api/v1/login/oidc/:provider
+	Issuer        string // Issuer URL, the endpoints are discovered from it
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string            // Callback registered at the provider, it passes code and state to /api/v1/login/oidc/:provider/callback
+	Scopes        []string          // Default openid, profile and email
+	UsernameClaim string            // Default preferred_username, falls back to email and sub
+	NameClaim     string            // Default name
+	EmailClaim    string            // Default email
+	GroupsClaim   string            // Default groups, nested claims are separated by dots (e.g. realm_access.roles)
+	RoleMapping   map[string]string // Group => role code, if empty then group names are used as role codes
+	DefaultRoles  []string          // Role codes given to provisioned users
+	AutoCreate    bool              // Provision unknown users on first login
+	SyncRoles     bool              // Replace the roles of the user with the mapped groups on each login
+}
+
 type Dictionary struct {
-	UserCacheExp int `default:"4"` // hours
+	UserCacheExp          int `default:"4"`  // hours
+	UserRoleSweepInterval int `default:"60"` // seconds, purge the expired role assignments of the users (0 means disabled)
 }
 
 func (c *Config) IsDebug() bool {
@@ -138,6 +238,12 @@ func (c *Config) PreLoad() {
 			c.Middleware.RateLimiter.Store.Redis.Username = username
 			c.Middleware.RateLimiter.Store.Redis.Password = password
 		}
+		if c.Util.Bus.Type == "redis" &&
+			c.Util.Bus.Redis.Addr == "" {
+			c.Util.Bus.Redis.Addr = addr
+			c.Util.Bus.Redis.Username = username
+			c.Util.Bus.Redis.Password = password
+		}
 		if c.Middleware.Auth.Store.Type == "redis" &&
 			c.Middleware.Auth.Store.Redis.Addr == "" {
 			c.Middleware.Auth.Store.Redis.Addr = addr
@@ -1,16 +1,33 @@
 package config
 
 const (
-	CacheNSForUser = "user"
-	CacheNSForRole = "role"
+	CacheNSForUser      = "user"
+	CacheNSForRole      = "role"
+	CacheNSForSession   = "session"
+	CacheNSForTwoFactor = "2fa"
+	CacheNSForLockout   = "lockout"
+	CacheNSForPwdReset  = "pwdreset"
+	CacheNSForOIDC      = "oidc"
+	CacheNSForTenant    = "tenant"
+	CacheNSForDataScope = "datascope"
 )
 
 const (
-	CacheKeyForSyncToCasbin = "sync:casbin"
+	CacheKeyForSyncToCasbin  = "sync:casbin"
+	CacheKeyForUserRoleSweep = "sweep:user_role"
 )
 
 const (
 	ErrInvalidTokenID            = "com.invalid.token"
 	ErrInvalidCaptchaID          = "com.invalid.captcha"
 	ErrInvalidUsernameOrPassword = "com.invalid.username-or-password"
+	ErrInvalidTwoFactorCode      = "com.invalid.two-factor-code"
+	ErrLoginLockedID             = "com.login.locked"
+	ErrInvalidPasswordID         = "com.invalid.password"
+	ErrPasswordExpiredID         = "com.password.expired"
+	ErrInvalidResetTokenID       = "com.invalid.reset-token"
+	ErrInvalidSSOStateID         = "com.invalid.sso-state"
+	ErrSSOLoginFailedID          = "com.sso.login-failed"
+	ErrLDAPLoginFailedID         = "com.ldap.login-failed"
+	ErrInvalidTenantID           = "com.invalid.tenant"
 )
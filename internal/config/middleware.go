@@ -35,11 +35,19 @@ type Middleware struct {
 	Auth struct {
 		Disable             bool
 		SkippedPathPrefixes []string
-		SigningMethod       string `default:"HS512"`    // HS256/HS384/HS512
-		SigningKey          string `default:"XnEsT0S@"` // secret key
-		OldSigningKey       string // old secret key (for migration)
-		Expired             int    `default:"86400"` // seconds
-		Store               struct {
+		SigningMethod       string `default:"HS512"`    // HS256/HS384/HS512/RS256/RS384/RS512/ES256/ES384/ES512/EdDSA
+		SigningKey          string `default:"XnEsT0S@"` // secret key (for HS* methods when Keys is empty)
+		OldSigningKey       string // old secret key (for migration, only verifies tokens when Keys is empty)
+		Keys                []struct {
+			ID             string // key ID (derived from the key if empty)
+			Method         string // signing method, same as SigningMethod
+			Secret         string // secret key (for HS* methods)
+			PrivateKeyFile string // PEM private key file (relative to workdir)
+			PublicKeyFile  string // PEM public key file (relative to workdir, for verification-only keys)
+		} // key ring, the first key signs new tokens and the others are only used to verify tokens (for key rotation)
+		Expired        int `default:"86400"`  // seconds
+		RefreshExpired int `default:"604800"` // seconds
+		Store          struct {
 			Type      string `default:"memory"` // memory/badger/redis
 			Delimiter string `default:":"`      // delimiter for key
 			Memory    struct {
@@ -76,13 +84,18 @@ type Middleware struct {
 			}
 		}
 	}
+	Tenant struct {
+		Enable              bool
+		SkippedPathPrefixes []string
+		HeaderKey           string `default:"X-Tenant"` // request header carrying the tenant code
+		Domain              string // base domain, the tenant code is resolved from its subdomain (e.g. acme.admin.example.com)
+	}
 	Casbin struct {
 		Disable             bool
 		SkippedPathPrefixes []string
-		LoadThread          int    `default:"2"`
-		AutoLoadInterval    int    `default:"3"` // seconds
+		AutoLoadInterval    int    `default:"60"` // seconds, polling for changes is a fallback for lost events
 		ModelFile           string `default:"rbac_model.conf"`
-		GenPolicyFile       string `default:"gen_rbac_policy.csv"`
+		GenPolicyFile       string // Export the policies to the file for debugging
 	}
 	Static struct {
 		Dir string // Static files directory (From command arguments)
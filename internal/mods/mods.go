@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 
+	"github.com/supermicah/go-framework-admin/internal/mods/org"
 	"github.com/supermicah/go-framework-admin/internal/mods/rbac"
 	"github.com/supermicah/go-framework-admin/internal/mods/sys"
 )
@@ -19,11 +20,13 @@ var Set = wire.NewSet(
 	wire.Struct(new(Mods), "*"),
 	rbac.Set,
 	sys.Set,
+	org.Set,
 )
 
 type Mods struct {
 	RBAC *rbac.RBAC
 	SYS  *sys.SYS
+	ORG  *org.ORG
 }
 
 func (a *Mods) Init(ctx context.Context) error {
@@ -33,6 +36,9 @@ func (a *Mods) Init(ctx context.Context) error {
 	if err := a.SYS.Init(ctx); err != nil {
 		return err
 	}
+	if err := a.ORG.Init(ctx); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -53,6 +59,9 @@ func (a *Mods) RegisterRouters(ctx context.Context, e *gin.Engine) error {
 	if err := a.SYS.RegisterV1Routers(ctx, v1); err != nil {
 		return err
 	}
+	if err := a.ORG.RegisterV1Routers(ctx, v1); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -64,5 +73,8 @@ func (a *Mods) Release(ctx context.Context) error {
 	if err := a.SYS.Release(ctx); err != nil {
 		return err
 	}
+	if err := a.ORG.Release(ctx); err != nil {
+		return err
+	}
 	return nil
 }
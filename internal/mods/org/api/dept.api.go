@@ -0,0 +1,244 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/org/biz"
+	"github.com/supermicah/go-framework-admin/internal/mods/org/schema"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// Dept management for the organization
+type Dept struct {
+	DeptBIZ *biz.Dept
+}
+
+// Query
+// @Tags DeptAPI
+// @Security ApiKeyAuth
+// @Summary Query department tree data
+// @Param name query string false "Display name of department"
+// @Param status query string false "Status of department (disabled, enabled)"
+// @Param root query int false "Only the tree of the department"
+// @Success 200 {object} util.ResponseResult{data=[]schema.Dept}
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/depts [get]
+func (a *Dept) Query(c *gin.Context) {
+	ctx := c.Request.Context()
+	var params schema.DeptQueryParam
+	if err := util.ParseQuery(c, &params); err != nil {
+		util.ResError(c, err)
+		return
+	}
+	result, err := a.DeptBIZ.Query(ctx, params)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, result.Data)
+}
+
+// Get
+// @Tags DeptAPI
+// @Security ApiKeyAuth
+// @Summary Get department record by ID
+// @Param id path string true "unique id"
+// @Success 200 {object} util.ResponseResult{data=schema.Dept}
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/depts/{id} [get]
+func (a *Dept) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+	item, err := a.DeptBIZ.Get(ctx, util.GetInt64Param(c, "id"))
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, item)
+}
+
+// Create
+// @Tags DeptAPI
+// @Security ApiKeyAuth
+// @Summary Create department record
+// @Param body body schema.DeptForm true "Request body"
+// @Success 200 {object} util.ResponseResult{data=schema.Dept}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/depts [post]
+func (a *Dept) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.DeptForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	} else if err := item.Validate(); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	result, err := a.DeptBIZ.Create(ctx, item)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, result)
+}
+
+// Update
+// @Tags DeptAPI
+// @Security ApiKeyAuth
+// @Summary Update department record by ID
+// @Param id path string true "unique id"
+// @Param body body schema.DeptForm true "Request body"
+// @Success 200 {object} util.ResponseResult
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/depts/{id} [put]
+func (a *Dept) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.DeptForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	} else if err := item.Validate(); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	err := a.DeptBIZ.Update(ctx, util.GetInt64Param(c, "id"), item)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
+
+// Move
+// @Tags DeptAPI
+// @Security ApiKeyAuth
+// @Summary Move department and its children under a new parent
+// @Param id path string true "unique id"
+// @Param body body schema.DeptMoveForm true "Request body"
+// @Success 200 {object} util.ResponseResult
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/depts/{id}/move [put]
+func (a *Dept) Move(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.DeptMoveForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	err := a.DeptBIZ.Move(ctx, util.GetInt64Param(c, "id"), item)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
+
+// Delete
+// @Tags DeptAPI
+// @Security ApiKeyAuth
+// @Summary Delete department record by ID
+// @Param id path string true "unique id"
+// @Success 200 {object} util.ResponseResult
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/depts/{id} [delete]
+func (a *Dept) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.DeptBIZ.Delete(ctx, util.GetInt64Param(c, "id"))
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
+
+// QueryUsers
+// @Tags DeptAPI
+// @Security ApiKeyAuth
+// @Summary Query members of department
+// @Param id path string true "unique id"
+// @Param current query int true "pagination index" default(1)
+// @Param pageSize query int true "pagination size" default(10)
+// @Param username query string false "Username of user"
+// @Param leader query bool false "Only the leaders (true) or the other members (false)"
+// @Success 200 {object} util.ResponseResult{data=[]schema.DeptUser}
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/depts/{id}/users [get]
+func (a *Dept) QueryUsers(c *gin.Context) {
+	ctx := c.Request.Context()
+	var params schema.DeptUserQueryParam
+	if err := util.ParseQuery(c, &params); err != nil {
+		util.ResError(c, err)
+		return
+	}
+	result, err := a.DeptBIZ.QueryUsers(ctx, util.GetInt64Param(c, "id"), params)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResPage(c, result.Data, result.PageResult)
+}
+
+// SaveUser
+// @Tags DeptAPI
+// @Security ApiKeyAuth
+// @Summary Add user to department or update its membership
+// @Param id path string true "unique id"
+// @Param body body schema.DeptUserForm true "Request body"
+// @Success 200 {object} util.ResponseResult{data=schema.DeptUser}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/depts/{id}/users [post]
+func (a *Dept) SaveUser(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.DeptUserForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	} else if err := item.Validate(); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	result, err := a.DeptBIZ.SaveUser(ctx, util.GetInt64Param(c, "id"), item)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, result)
+}
+
+// RemoveUser
+// @Tags DeptAPI
+// @Security ApiKeyAuth
+// @Summary Remove user from department
+// @Param id path string true "unique id"
+// @Param userID path string true "user id"
+// @Success 200 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 404 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/depts/{id}/users/{userID} [delete]
+func (a *Dept) RemoveUser(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.DeptBIZ.RemoveUser(ctx, util.GetInt64Param(c, "id"), util.GetInt64Param(c, "userID"))
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
@@ -0,0 +1,322 @@
+package biz
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/org/dal"
+	"github.com/supermicah/go-framework-admin/internal/mods/org/schema"
+	rbacBIZ "github.com/supermicah/go-framework-admin/internal/mods/rbac/biz"
+	rbacDAL "github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// Dept management for the organization, the primary department of a member is kept on the user for the data scopes.
+type Dept struct {
+	Trans       *util.Trans
+	DeptDAL     *dal.Dept
+	DeptUserDAL *dal.DeptUser
+	UserDAL     *rbacDAL.User
+	EventBIZ    *rbacBIZ.Event
+}
+
+// Query the department tree, the tree of the specified root if given.
+func (a *Dept) Query(ctx context.Context, params schema.DeptQueryParam) (*schema.DeptQueryResult, error) {
+	params.Pagination = false
+
+	var root *schema.Dept
+	if params.RootID > 0 {
+		var err error
+		root, err = a.DeptDAL.Get(ctx, params.RootID)
+		if err != nil {
+			return nil, err
+		} else if root == nil {
+			return nil, errors.NotFound("", "Department not found")
+		}
+		params.ParentPathPrefix = root.ChildPath()
+	}
+
+	result, err := a.DeptDAL.Query(ctx, params, schema.DeptQueryOptions{
+		QueryOptions: util.QueryOptions{
+			OrderFields: schema.DeptsOrderParams,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if root != nil && (params.LikeName == "" || strings.Contains(root.Name, params.LikeName)) &&
+		(params.Status == "" || params.Status == root.Status) {
+		result.Data = append(schema.Depts{root}, result.Data...)
+	}
+	result.Data = result.Data.ToTree()
+	return result, nil
+}
+
+// Get the specified department with its leaders.
+func (a *Dept) Get(ctx context.Context, id int64) (*schema.Dept, error) {
+	dept, err := a.DeptDAL.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if dept == nil {
+		return nil, errors.NotFound("", "Department not found")
+	}
+
+	isLeader := true
+	leaderResult, err := a.DeptUserDAL.Query(ctx, schema.DeptUserQueryParam{
+		DeptID:   id,
+		IsLeader: &isLeader,
+	})
+	if err != nil {
+		return nil, err
+	}
+	dept.Leaders = leaderResult.Data
+
+	return dept, nil
+}
+
+// Create a new department.
+func (a *Dept) Create(ctx context.Context, formItem *schema.DeptForm) (*schema.Dept, error) {
+	dept := &schema.Dept{
+		ParentID:  formItem.ParentID,
+		CreatedAt: time.Now(),
+	}
+
+	if parentID := formItem.ParentID; parentID > 0 {
+		parent, err := a.DeptDAL.Get(ctx, parentID)
+		if err != nil {
+			return nil, err
+		} else if parent == nil {
+			return nil, errors.NotFound("", "Parent not found")
+		}
+		dept.ParentPath = parent.ChildPath()
+	}
+
+	if exists, err := a.DeptDAL.ExistsCodeByParentID(ctx, formItem.Code, formItem.ParentID); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, errors.BadRequest("", "Department code already exists at the same level")
+	}
+
+	if err := formItem.FillTo(dept); err != nil {
+		return nil, err
+	}
+
+	if err := a.DeptDAL.Create(ctx, dept); err != nil {
+		return nil, err
+	}
+	return dept, nil
+}
+
+// Update the specified department, the parent is changed by Move.
+func (a *Dept) Update(ctx context.Context, id int64, formItem *schema.DeptForm) error {
+	dept, err := a.DeptDAL.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if dept == nil {
+		return errors.NotFound("", "Department not found")
+	} else if dept.Code != formItem.Code {
+		if exists, err := a.DeptDAL.ExistsCodeByParentID(ctx, formItem.Code, dept.ParentID); err != nil {
+			return err
+		} else if exists {
+			return errors.BadRequest("", "Department code already exists at the same level")
+		}
+	}
+
+	if err := formItem.FillTo(dept); err != nil {
+		return err
+	}
+	dept.UpdatedAt = time.Now()
+
+	return a.DeptDAL.Update(ctx, dept)
+}
+
+// Move the specified department and its children under a new parent.
+func (a *Dept) Move(ctx context.Context, id int64, formItem *schema.DeptMoveForm) error {
+	dept, err := a.DeptDAL.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if dept == nil {
+		return errors.NotFound("", "Department not found")
+	} else if dept.ParentID == formItem.ParentID {
+		return nil
+	}
+
+	oldPath := dept.ChildPath()
+	dept.ParentID = formItem.ParentID
+	dept.ParentPath = ""
+	if parentID := formItem.ParentID; parentID > 0 {
+		parent, err := a.DeptDAL.Get(ctx, parentID)
+		if err != nil {
+			return err
+		} else if parent == nil {
+			return errors.NotFound("", "Parent not found")
+		} else if parent.ID == id || strings.HasPrefix(parent.ParentPath, oldPath) {
+			return errors.BadRequest("", "Department cannot be moved under itself or its children")
+		}
+		dept.ParentPath = parent.ChildPath()
+	}
+
+	if exists, err := a.DeptDAL.ExistsCodeByParentID(ctx, dept.Code, dept.ParentID); err != nil {
+		return err
+	} else if exists {
+		return errors.BadRequest("", "Department code already exists at the same level")
+	}
+
+	childResult, err := a.DeptDAL.Query(ctx, schema.DeptQueryParam{
+		ParentPathPrefix: oldPath,
+	}, schema.DeptQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "parent_path"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	dept.UpdatedAt = time.Now()
+
+	return a.Trans.Exec(ctx, func(ctx context.Context) error {
+		newPath := dept.ChildPath()
+		for _, child := range childResult.Data {
+			err := a.DeptDAL.UpdateParentPath(ctx, child.ID, strings.Replace(child.ParentPath, oldPath, newPath, 1))
+			if err != nil {
+				return err
+			}
+		}
+		return a.DeptDAL.Update(ctx, dept)
+	})
+}
+
+// Delete the specified department, only the departments without children and members can be deleted.
+func (a *Dept) Delete(ctx context.Context, id int64) error {
+	exists, err := a.DeptDAL.Exists(ctx, id)
+	if err != nil {
+		return err
+	} else if !exists {
+		return errors.NotFound("", "Department not found")
+	}
+
+	if exists, err := a.DeptDAL.ExistsChildren(ctx, id); err != nil {
+		return err
+	} else if exists {
+		return errors.BadRequest("", "Department still has child departments")
+	}
+	if exists, err := a.DeptUserDAL.ExistsByDeptID(ctx, id); err != nil {
+		return err
+	} else if exists {
+		return errors.BadRequest("", "Department still has members")
+	}
+
+	return a.DeptDAL.Delete(ctx, id)
+}
+
+// QueryUsers Query the members of the specified department.
+func (a *Dept) QueryUsers(ctx context.Context, id int64, params schema.DeptUserQueryParam) (*schema.DeptUserQueryResult, error) {
+	if exists, err := a.DeptDAL.Exists(ctx, id); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, errors.NotFound("", "Department not found")
+	}
+
+	params.Pagination = true
+	params.DeptID = id
+	return a.DeptUserDAL.Query(ctx, params, schema.DeptUserQueryOptions{
+		QueryOptions: util.QueryOptions{
+			OrderFields: []util.OrderByParam{
+				{Field: "a.is_leader", Direction: util.DESC},
+				{Field: "a.created_at", Direction: util.DESC},
+			},
+		},
+	})
+}
+
+// SaveUser Add the user to the department or update its membership, the primary department is kept on the user.
+func (a *Dept) SaveUser(ctx context.Context, id int64, formItem *schema.DeptUserForm) (*schema.DeptUser, error) {
+	if exists, err := a.DeptDAL.Exists(ctx, id); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, errors.NotFound("", "Department not found")
+	}
+	if exists, err := a.UserDAL.Exists(ctx, formItem.UserID); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, errors.NotFound("", "User not found")
+	}
+
+	deptUser, err := a.DeptUserDAL.Get(ctx, id, formItem.UserID)
+	if err != nil {
+		return nil, err
+	}
+	isNew := deptUser == nil
+	wasPrimary := !isNew && deptUser.IsPrimary
+	if isNew {
+		deptUser = &schema.DeptUser{
+			DeptID:    id,
+			CreatedAt: time.Now(),
+		}
+	}
+	if err := formItem.FillTo(deptUser); err != nil {
+		return nil, err
+	}
+	deptUser.UpdatedAt = time.Now()
+
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
+		if deptUser.IsPrimary && !wasPrimary {
+			if err := a.DeptUserDAL.ClearPrimary(ctx, deptUser.UserID); err != nil {
+				return err
+			}
+			if err := a.UserDAL.UpdateDeptID(ctx, deptUser.UserID, id); err != nil {
+				return err
+			}
+		} else if !deptUser.IsPrimary && wasPrimary {
+			if err := a.UserDAL.UpdateDeptID(ctx, deptUser.UserID, 0); err != nil {
+				return err
+			}
+		}
+
+		if isNew {
+			return a.DeptUserDAL.Create(ctx, deptUser)
+		}
+		return a.DeptUserDAL.Update(ctx, deptUser)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if deptUser.IsPrimary != wasPrimary {
+		if err := a.EventBIZ.UserChanged(ctx, deptUser.UserID); err != nil {
+			return nil, err
+		}
+	}
+	return deptUser, nil
+}
+
+// RemoveUser Remove the user from the department, the user has no primary department if it was the primary one.
+func (a *Dept) RemoveUser(ctx context.Context, id, userID int64) error {
+	deptUser, err := a.DeptUserDAL.Get(ctx, id, userID)
+	if err != nil {
+		return err
+	} else if deptUser == nil {
+		return errors.NotFound("", "User is not a member of the department")
+	}
+
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
+		if err := a.DeptUserDAL.Delete(ctx, id, userID); err != nil {
+			return err
+		}
+		if deptUser.IsPrimary {
+			return a.UserDAL.UpdateDeptID(ctx, userID, 0)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if deptUser.IsPrimary {
+		return a.EventBIZ.UserChanged(ctx, userID)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package dal
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/org/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// GetDeptDB Get department storage instance
+func GetDeptDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDB(ctx, defDB).Model(new(schema.Dept))
+}
+
+// Dept management for the organization
+type Dept struct {
+	DB *gorm.DB
+}
+
+// Query departments from the database based on the provided parameters and options.
+func (a *Dept) Query(ctx context.Context, params schema.DeptQueryParam, opts ...schema.DeptQueryOptions) (*schema.DeptQueryResult, error) {
+	var opt schema.DeptQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	db := GetDeptDB(ctx, a.DB)
+	if v := params.InIDs; len(v) > 0 {
+		db = db.Where("id IN ?", v)
+	}
+	if v := params.LikeName; len(v) > 0 {
+		db = db.Where("name LIKE ?", "%"+v+"%")
+	}
+	if v := params.Status; len(v) > 0 {
+		db = db.Where("status = ?", v)
+	}
+	if v := params.ParentID; v > 0 {
+		db = db.Where("parent_id = ?", v)
+	}
+	if v := params.ParentPathPrefix; len(v) > 0 {
+		db = db.Where("parent_path LIKE ?", v+"%")
+	}
+
+	var list schema.Depts
+	pageResult, err := util.WrapPageQuery(ctx, db, params.PaginationParam, opt.QueryOptions, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	queryResult := &schema.DeptQueryResult{
+		PageResult: pageResult,
+		Data:       list,
+	}
+	return queryResult, nil
+}
+
+// Get the specified department from the database.
+func (a *Dept) Get(ctx context.Context, id int64, opts ...schema.DeptQueryOptions) (*schema.Dept, error) {
+	var opt schema.DeptQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	item := new(schema.Dept)
+	ok, err := util.FindOne(ctx, GetDeptDB(ctx, a.DB).Where("id=?", id), opt.QueryOptions, item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+	return item, nil
+}
+
+// Exists Checks if the specified department exists in the database.
+func (a *Dept) Exists(ctx context.Context, id int64) (bool, error) {
+	ok, err := util.Exists(ctx, GetDeptDB(ctx, a.DB).Where("id=?", id))
+	return ok, errors.WithStack(err)
+}
+
+// ExistsCodeByParentID Checks if a department with the specified `code` exists under the specified `parentID`.
+func (a *Dept) ExistsCodeByParentID(ctx context.Context, code string, parentID int64) (bool, error) {
+	ok, err := util.Exists(ctx, GetDeptDB(ctx, a.DB).Where("code=? AND parent_id=?", code, parentID))
+	return ok, errors.WithStack(err)
+}
+
+// ExistsChildren Checks if the department has child departments.
+func (a *Dept) ExistsChildren(ctx context.Context, id int64) (bool, error) {
+	ok, err := util.Exists(ctx, GetDeptDB(ctx, a.DB).Where("parent_id=?", id))
+	return ok, errors.WithStack(err)
+}
+
+// Create a new department.
+func (a *Dept) Create(ctx context.Context, item *schema.Dept) error {
+	result := GetDeptDB(ctx, a.DB).Create(item)
+	return errors.WithStack(result.Error)
+}
+
+// Update the specified department in the database.
+func (a *Dept) Update(ctx context.Context, item *schema.Dept) error {
+	result := GetDeptDB(ctx, a.DB).Where("id=?", item.ID).Select("*").Omit("created_at").Updates(item)
+	return errors.WithStack(result.Error)
+}
+
+// Delete the specified department from the database.
+func (a *Dept) Delete(ctx context.Context, id int64) error {
+	result := GetDeptDB(ctx, a.DB).Where("id=?", id).Delete(new(schema.Dept))
+	return errors.WithStack(result.Error)
+}
+
+// UpdateParentPath Updates the parent path of the specified department.
+func (a *Dept) UpdateParentPath(ctx context.Context, id int64, parentPath string) error {
+	result := GetDeptDB(ctx, a.DB).Where("id=?", id).Update("parent_path", parentPath)
+	return errors.WithStack(result.Error)
+}
@@ -0,0 +1,118 @@
+package dal
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/org/schema"
+	rbacSchema "github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// GetDeptUserDB Get department user storage instance
+func GetDeptUserDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDB(ctx, defDB).Model(new(schema.DeptUser))
+}
+
+// DeptUser Membership of the users in the departments
+type DeptUser struct {
+	DB *gorm.DB
+}
+
+// Query the members of the departments from the database, the users and the departments are joined.
+func (a *DeptUser) Query(ctx context.Context, params schema.DeptUserQueryParam, opts ...schema.DeptUserQueryOptions) (*schema.DeptUserQueryResult, error) {
+	var opt schema.DeptUserQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	db := util.GetDB(ctx, a.DB).Table(fmt.Sprintf("%s AS a", new(schema.DeptUser).TableName()))
+	db = db.Joins(fmt.Sprintf("inner join %s b on a.user_id=b.id", new(rbacSchema.User).TableName()))
+	db = db.Joins(fmt.Sprintf("inner join %s c on a.dept_id=c.id", new(schema.Dept).TableName()))
+	db = db.Select("a.*,b.username,b.name,c.name as dept_name")
+
+	if v := params.DeptID; v > 0 {
+		db = db.Where("a.dept_id = ?", v)
+	}
+	if v := params.InDeptIDs; len(v) > 0 {
+		db = db.Where("a.dept_id IN (?)", v)
+	}
+	if v := params.UserID; v > 0 {
+		db = db.Where("a.user_id = ?", v)
+	}
+	if v := params.IsLeader; v != nil {
+		db = db.Where("a.is_leader = ?", *v)
+	}
+	if v := params.LikeUsername; len(v) > 0 {
+		db = db.Where("b.username LIKE ?", "%"+v+"%")
+	}
+
+	var list schema.DeptUsers
+	pageResult, err := util.WrapPageQuery(ctx, db, params.PaginationParam, opt.QueryOptions, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	queryResult := &schema.DeptUserQueryResult{
+		PageResult: pageResult,
+		Data:       list,
+	}
+	return queryResult, nil
+}
+
+// Get the membership of the user in the department from the database.
+func (a *DeptUser) Get(ctx context.Context, deptID, userID int64, opts ...schema.DeptUserQueryOptions) (*schema.DeptUser, error) {
+	var opt schema.DeptUserQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	item := new(schema.DeptUser)
+	db := GetDeptUserDB(ctx, a.DB).Where("dept_id=? AND user_id=?", deptID, userID)
+	ok, err := util.FindOne(ctx, db, opt.QueryOptions, item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+	return item, nil
+}
+
+// ExistsByDeptID Checks if the department has members.
+func (a *DeptUser) ExistsByDeptID(ctx context.Context, deptID int64) (bool, error) {
+	ok, err := util.Exists(ctx, GetDeptUserDB(ctx, a.DB).Where("dept_id=?", deptID))
+	return ok, errors.WithStack(err)
+}
+
+// Create a new membership.
+func (a *DeptUser) Create(ctx context.Context, item *schema.DeptUser) error {
+	result := GetDeptUserDB(ctx, a.DB).Create(item)
+	return errors.WithStack(result.Error)
+}
+
+// Update the specified membership in the database.
+func (a *DeptUser) Update(ctx context.Context, item *schema.DeptUser) error {
+	result := GetDeptUserDB(ctx, a.DB).Where("id=?", item.ID).Select("*").Omit("created_at").Updates(item)
+	return errors.WithStack(result.Error)
+}
+
+// Delete the membership of the user in the department from the database.
+func (a *DeptUser) Delete(ctx context.Context, deptID, userID int64) error {
+	result := GetDeptUserDB(ctx, a.DB).Where("dept_id=? AND user_id=?", deptID, userID).Delete(new(schema.DeptUser))
+	return errors.WithStack(result.Error)
+}
+
+// DeleteByUserID Deletes the memberships of the user from the database.
+func (a *DeptUser) DeleteByUserID(ctx context.Context, userID int64) error {
+	result := GetDeptUserDB(ctx, a.DB).Where("user_id=?", userID).Delete(new(schema.DeptUser))
+	return errors.WithStack(result.Error)
+}
+
+// ClearPrimary Unset the primary department of the user.
+func (a *DeptUser) ClearPrimary(ctx context.Context, userID int64) error {
+	result := GetDeptUserDB(ctx, a.DB).Where("user_id=? AND is_primary=?", userID, true).Update("is_primary", false)
+	return errors.WithStack(result.Error)
+}
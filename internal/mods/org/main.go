@@ -0,0 +1,53 @@
+package org
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/org/api"
+	"github.com/supermicah/go-framework-admin/internal/mods/org/schema"
+)
+
+type ORG struct {
+	DB      *gorm.DB
+	DeptAPI *api.Dept
+}
+
+func (a *ORG) AutoMigrate(ctx context.Context) error {
+	return a.DB.AutoMigrate(
+		new(schema.Dept),
+		new(schema.DeptUser),
+	)
+}
+
+func (a *ORG) Init(ctx context.Context) error {
+	if config.C.Storage.DB.AutoMigrate {
+		if err := a.AutoMigrate(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *ORG) RegisterV1Routers(ctx context.Context, v1 *gin.RouterGroup) error {
+	dept := v1.Group("depts")
+	{
+		dept.GET("", a.DeptAPI.Query)
+		dept.GET(":id", a.DeptAPI.Get)
+		dept.POST("", a.DeptAPI.Create)
+		dept.PUT(":id", a.DeptAPI.Update)
+		dept.PUT(":id/move", a.DeptAPI.Move)
+		dept.DELETE(":id", a.DeptAPI.Delete)
+		dept.GET(":id/users", a.DeptAPI.QueryUsers)
+		dept.POST(":id/users", a.DeptAPI.SaveUser)
+		dept.DELETE(":id/users/:userID", a.DeptAPI.RemoveUser)
+	}
+	return nil
+}
+
+func (a *ORG) Release(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,139 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+const (
+	DeptStatusDisabled = "disabled"
+	DeptStatusEnabled  = "enabled"
+)
+
+var (
+	DeptsOrderParams = []util.OrderByParam{
+		{Field: "sequence", Direction: util.DESC},
+		{Field: "created_at", Direction: util.DESC},
+	}
+)
+
+// Dept Department of the organization, the departments are organized in a tree
+type Dept struct {
+	ID          int64     `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
+	TenantID    int64     `json:"tenant_id" gorm:"size:64;index"`              // From Tenant.ID
+	Code        string    `json:"code" gorm:"size:32;index;"`                  // Code of department (unique for each level)
+	Name        string    `json:"name" gorm:"size:128;index"`                  // Display name of department
+	Description string    `json:"description" gorm:"size:1024"`                // Details about department
+	Sequence    int       `json:"sequence" gorm:"index;"`                      // Sequence for sorting (Order by desc)
+	Status      string    `json:"status" gorm:"size:20;index"`                 // Status of department (enabled, disabled)
+	ParentID    int64     `json:"parent_id" gorm:"size:64;index;"`             // Parent ID (From Dept.ID)
+	ParentPath  string    `json:"parent_path" gorm:"size:255;index;"`          // Parent path (split by .)
+	Children    *Depts    `json:"children" gorm:"-"`                           // Child departments
+	Leaders     DeptUsers `json:"leaders" gorm:"-"`                            // Leaders of department
+	CreatedAt   time.Time `json:"created_at" gorm:"index;"`                    // Create time
+	UpdatedAt   time.Time `json:"updated_at" gorm:"index;"`                    // Update time
+}
+
+func (a *Dept) TableName() string {
+	return config.C.FormatTableName("dept")
+}
+
+// ChildPath Parent path of the children of the department
+func (a *Dept) ChildPath() string {
+	return fmt.Sprintf("%s%d%s", a.ParentPath, a.ID, util.TreePathDelimiter)
+}
+
+// DeptQueryParam Defining the query parameters for the `Dept` struct.
+type DeptQueryParam struct {
+	util.PaginationParam
+	LikeName         string  `form:"name"`                                       // Display name of department
+	Status           string  `form:"status" binding:"oneof=disabled enabled ''"` // Status of department (disabled, enabled)
+	RootID           int64   `form:"root"`                                       // Only the tree of the department (From Dept.ID)
+	InIDs            []int64 `form:"-"`                                          // Include department IDs
+	ParentID         int64   `form:"-"`                                          // Parent ID (From Dept.ID)
+	ParentPathPrefix string  `form:"-"`                                          // Parent path (split by .)
+}
+
+// DeptQueryOptions Defining the query options for the `Dept` struct.
+type DeptQueryOptions struct {
+	util.QueryOptions
+}
+
+// DeptQueryResult Defining the query result for the `Dept` struct.
+type DeptQueryResult struct {
+	Data       Depts
+	PageResult *util.PaginationResult
+}
+
+// Depts Defining the slice of `Dept` struct.
+type Depts []*Dept
+
+func (a Depts) ToMap() map[int64]*Dept {
+	m := make(map[int64]*Dept)
+	for _, item := range a {
+		m[item.ID] = item
+	}
+	return m
+}
+
+func (a Depts) ToIDs() []int64 {
+	ids := make([]int64, 0, len(a))
+	for _, item := range a {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+// ToTree Nest the departments under their parents, the departments without a parent in the list are the roots.
+func (a Depts) ToTree() Depts {
+	var list Depts
+	m := a.ToMap()
+	for _, item := range a {
+		if parent, ok := m[item.ParentID]; ok {
+			if parent.Children == nil {
+				children := Depts{item}
+				parent.Children = &children
+				continue
+			}
+			*parent.Children = append(*parent.Children, item)
+			continue
+		}
+		list = append(list, item)
+	}
+	return list
+}
+
+// DeptForm Defining the data structure for creating a `Dept` struct.
+type DeptForm struct {
+	Code        string `json:"code" binding:"required,max=32"`                   // Code of department (unique for each level)
+	Name        string `json:"name" binding:"required,max=128"`                  // Display name of department
+	Description string `json:"description"`                                      // Details about department
+	Sequence    int    `json:"sequence"`                                         // Sequence for sorting (Order by desc)
+	Status      string `json:"status" binding:"required,oneof=disabled enabled"` // Status of department (disabled, enabled)
+	ParentID    int64  `json:"parent_id"`                                        // Parent ID (From Dept.ID), only used on creation, the departments are moved by the move API
+}
+
+// Validate A validation function for the `DeptForm` struct.
+func (a *DeptForm) Validate() error {
+	a.Code = strings.TrimSpace(a.Code)
+	a.Name = strings.TrimSpace(a.Name)
+	return nil
+}
+
+func (a *DeptForm) FillTo(dept *Dept) error {
+	dept.Code = a.Code
+	dept.Name = a.Name
+	dept.Description = a.Description
+	dept.Sequence = a.Sequence
+	dept.Status = a.Status
+	return nil
+}
+
+// DeptMoveForm Defining the data structure for moving a `Dept` struct.
+type DeptMoveForm struct {
+	ParentID int64 `json:"parent_id"` // New parent ID (From Dept.ID), zero to move to the root
+}
@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// DeptUser Membership of a user in a department, a user has at most one primary department
+type DeptUser struct {
+	ID        int64     `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
+	TenantID  int64     `json:"tenant_id" gorm:"size:64;index"`              // From Tenant.ID
+	DeptID    int64     `json:"dept_id" gorm:"size:64;index"`                // From Dept.ID
+	UserID    int64     `json:"user_id" gorm:"size:64;index"`                // From User.ID
+	IsPrimary bool      `json:"is_primary" gorm:"index"`                     // Primary department of the user (User.DeptID)
+	IsLeader  bool      `json:"is_leader" gorm:"index"`                      // Leader of the department
+	CreatedAt time.Time `json:"created_at" gorm:"index;"`                    // Create time
+	UpdatedAt time.Time `json:"updated_at" gorm:"index;"`                    // Update time
+	Username  string    `json:"username" gorm:"<-:false;-:migration;"`       // From User.Username
+	Name      string    `json:"name" gorm:"<-:false;-:migration;"`           // From User.Name
+	DeptName  string    `json:"dept_name" gorm:"<-:false;-:migration;"`      // From Dept.Name
+}
+
+func (a *DeptUser) TableName() string {
+	return config.C.FormatTableName("dept_user")
+}
+
+// DeptUserQueryParam Defining the query parameters for the `DeptUser` struct.
+type DeptUserQueryParam struct {
+	util.PaginationParam
+	LikeUsername string  `form:"username"` // Username of user
+	IsLeader     *bool   `form:"leader"`   // Only the leaders (or the other members) of the department
+	DeptID       int64   `form:"-"`        // From Dept.ID
+	InDeptIDs    []int64 `form:"-"`        // From Dept.ID
+	UserID       int64   `form:"-"`        // From User.ID
+}
+
+// DeptUserQueryOptions Defining the query options for the `DeptUser` struct.
+type DeptUserQueryOptions struct {
+	util.QueryOptions
+}
+
+// DeptUserQueryResult Defining the query result for the `DeptUser` struct.
+type DeptUserQueryResult struct {
+	Data       DeptUsers
+	PageResult *util.PaginationResult
+}
+
+// DeptUsers Defining the slice of `DeptUser` struct.
+type DeptUsers []*DeptUser
+
+// DeptUserForm Defining the data structure for adding a user to a `Dept`.
+type DeptUserForm struct {
+	UserID    int64 `json:"user_id" binding:"required"` // From User.ID
+	IsPrimary bool  `json:"is_primary"`                 // Set the department as the primary department of the user
+	IsLeader  bool  `json:"is_leader"`                  // Leader of the department
+}
+
+// Validate A validation function for the `DeptUserForm` struct.
+func (a *DeptUserForm) Validate() error {
+	return nil
+}
+
+func (a *DeptUserForm) FillTo(deptUser *DeptUser) error {
+	deptUser.UserID = a.UserID
+	deptUser.IsPrimary = a.IsPrimary
+	deptUser.IsLeader = a.IsLeader
+	return nil
+}
@@ -0,0 +1,17 @@
+package org
+
+import (
+	"github.com/google/wire"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/org/api"
+	"github.com/supermicah/go-framework-admin/internal/mods/org/biz"
+	"github.com/supermicah/go-framework-admin/internal/mods/org/dal"
+)
+
+var Set = wire.NewSet(
+	wire.Struct(new(ORG), "*"),
+	wire.Struct(new(dal.Dept), "*"),
+	wire.Struct(new(dal.DeptUser), "*"),
+	wire.Struct(new(biz.Dept), "*"),
+	wire.Struct(new(api.Dept), "*"),
+)
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/biz"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// APIKey Personal access tokens of the current user
+type APIKey struct {
+	APIKeyBIZ *biz.APIKey
+}
+
+// Query
+// @Tags APIKeyAPI
+// @Security ApiKeyAuth
+// @Summary Query API keys of the current user
+// @Param current query int true "pagination index" default(1)
+// @Param pageSize query int true "pagination size" default(10)
+// @Param name query string false "Display name of API key"
+// @Success 200 {object} util.ResponseResult{data=[]schema.APIKey}
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/current/api-keys [get]
+func (a *APIKey) Query(c *gin.Context) {
+	ctx := c.Request.Context()
+	var params schema.APIKeyQueryParam
+	if err := util.ParseQuery(c, &params); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	result, err := a.APIKeyBIZ.Query(ctx, params)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResPage(c, result.Data, result.PageResult)
+}
+
+// Get
+// @Tags APIKeyAPI
+// @Security ApiKeyAuth
+// @Summary Get API key record by ID
+// @Param id path string true "unique id"
+// @Success 200 {object} util.ResponseResult{data=schema.APIKey}
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/current/api-keys/{id} [get]
+func (a *APIKey) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+	item, err := a.APIKeyBIZ.Get(ctx, util.GetInt64Param(c, "id"))
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, item)
+}
+
+// Create
+// @Tags APIKeyAPI
+// @Security ApiKeyAuth
+// @Summary Create API key record (the key is only returned once)
+// @Param body body schema.APIKeyForm true "Request body"
+// @Success 200 {object} util.ResponseResult{data=schema.APIKey}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/current/api-keys [post]
+func (a *APIKey) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.APIKeyForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	} else if err := item.Validate(); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	result, err := a.APIKeyBIZ.Create(ctx, item)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, result)
+}
+
+// Update
+// @Tags APIKeyAPI
+// @Security ApiKeyAuth
+// @Summary Update API key record by ID
+// @Param id path string true "unique id"
+// @Param body body schema.APIKeyForm true "Request body"
+// @Success 200 {object} util.ResponseResult
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/current/api-keys/{id} [put]
+func (a *APIKey) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.APIKeyForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	} else if err := item.Validate(); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	err := a.APIKeyBIZ.Update(ctx, util.GetInt64Param(c, "id"), item)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
+
+// Delete
+// @Tags APIKeyAPI
+// @Security ApiKeyAuth
+// @Summary Delete API key record by ID
+// @Param id path string true "unique id"
+// @Success 200 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/current/api-keys/{id} [delete]
+func (a *APIKey) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.APIKeyBIZ.Delete(ctx, util.GetInt64Param(c, "id"))
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/biz"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// Bundle Exporting and importing the RBAC configuration as JSON/YAML bundles
+type Bundle struct {
+	BundleBIZ *biz.Bundle
+}
+
+// Export
+// @Tags BundleAPI
+// @Security ApiKeyAuth
+// @Summary Export the menus, roles and optionally the role bindings of the users as a JSON or YAML bundle
+// @Param users query bool false "Include the role bindings of the users"
+// @Param format query string false "Format of the bundle (json, yaml)"
+// @Produce json,application/yaml
+// @Success 200 {object} schema.Bundle
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/bundles/export [get]
+func (a *Bundle) Export(c *gin.Context) {
+	ctx := c.Request.Context()
+	var params schema.BundleExportParam
+	if err := util.ParseQuery(c, &params); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	format, contentType := schema.BundleFormatJSON, "application/json; charset=utf-8"
+	if params.Format == schema.BundleFormatYAML {
+		format, contentType = schema.BundleFormatYAML, "application/yaml; charset=utf-8"
+	} else if params.Format != "" && params.Format != schema.BundleFormatJSON {
+		util.ResError(c, errors.BadRequest("", "Unsupported bundle format '%s'", params.Format))
+		return
+	}
+
+	bundle, err := a.BundleBIZ.Export(ctx, params)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	data, err := bundle.Marshal(format)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=rbac-bundle.%s", format))
+	c.Data(http.StatusOK, contentType, data)
+	c.Abort()
+}
+
+// Import
+// @Tags BundleAPI
+// @Security ApiKeyAuth
+// @Summary Import a JSON or YAML bundle, creating or updating the menus and roles by code (YAML if the content type is YAML)
+// @Accept json,application/yaml
+// @Param body body schema.Bundle true "Request body"
+// @Param format query string false "Format of the bundle (json, yaml), default from the content type"
+// @Success 200 {object} util.ResponseResult{data=schema.BundleImportResult}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/bundles/import [post]
+func (a *Bundle) Import(c *gin.Context) {
+	ctx := c.Request.Context()
+	format := c.Query("format")
+	if format == "" {
+		format = schema.BundleFormatJSON
+		if strings.Contains(c.ContentType(), "yaml") {
+			format = schema.BundleFormatYAML
+		}
+	}
+
+	data, err := c.GetRawData()
+	if err != nil {
+		util.ResError(c, errors.BadRequest("", "Failed to read bundle: %s", err.Error()))
+		return
+	}
+	bundle, err := schema.ParseBundle(data, format)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	result, err := a.BundleBIZ.Import(ctx, bundle)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, result)
+}
@@ -53,7 +53,8 @@ func (a *Login) ResponseCaptcha(c *gin.Context) {
 // @Failure 500 {object} util.ResponseResult
 // @Router /api/v1/login [post]
 func (a *Login) Login(c *gin.Context) {
-	ctx := c.Request.Context()
+	ctx := util.NewClientIP(c.Request.Context(), c.ClientIP())
+	ctx = util.NewUserAgent(ctx, c.Request.UserAgent())
 	item := new(schema.LoginForm)
 	if err := util.ParseJSON(c, item); err != nil {
 		util.ResError(c, err)
@@ -68,6 +69,57 @@ func (a *Login) Login(c *gin.Context) {
 	util.ResSuccess(c, data)
 }
 
+// LoginTwoFactor
+// @Tags LoginAPI
+// @Summary Exchange the challenge token of login and a TOTP or recovery code for the access token
+// @Param body body schema.TwoFactorChallengeForm true "Request body"
+// @Success 200 {object} util.ResponseResult{data=schema.LoginToken}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/login/2fa [post]
+func (a *Login) LoginTwoFactor(c *gin.Context) {
+	ctx := util.NewClientIP(c.Request.Context(), c.ClientIP())
+	ctx = util.NewUserAgent(ctx, c.Request.UserAgent())
+	item := new(schema.TwoFactorChallengeForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	data, err := a.LoginBIZ.LoginTwoFactor(ctx, item.Trim())
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, data)
+}
+
+// SetupLoginTwoFactor
+// @Tags LoginAPI
+// @Summary Set up two-factor with the challenge token of login (when required by roles but not enabled)
+// @Param body body schema.TwoFactorChallengeForm true "Request body"
+// @Success 200 {object} util.ResponseResult{data=schema.TwoFactorSetup}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/login/2fa/setup [post]
+func (a *Login) SetupLoginTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.TwoFactorChallengeForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	data, err := a.LoginBIZ.SetupLoginTwoFactor(ctx, item.Trim())
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, data)
+}
+
 // Logout
 // @Tags LoginAPI
 // @Security ApiKeyAuth
@@ -87,15 +139,22 @@ func (a *Login) Logout(c *gin.Context) {
 
 // RefreshToken
 // @Tags LoginAPI
-// @Security ApiKeyAuth
-// @Summary Refresh current access token
+// @Summary Exchange a refresh token for a new token pair
+// @Param body body schema.RefreshTokenForm true "Request body"
 // @Success 200 {object} util.ResponseResult{data=schema.LoginToken}
+// @Failure 400 {object} util.ResponseResult
 // @Failure 401 {object} util.ResponseResult
 // @Failure 500 {object} util.ResponseResult
 // @Router /api/v1/current/refresh-token [post]
 func (a *Login) RefreshToken(c *gin.Context) {
 	ctx := c.Request.Context()
-	data, err := a.LoginBIZ.RefreshToken(ctx)
+	item := new(schema.RefreshTokenForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	data, err := a.LoginBIZ.RefreshToken(ctx, item)
 	if err != nil {
 		util.ResError(c, err)
 		return
@@ -151,12 +210,13 @@ func (a *Login) UpdatePassword(c *gin.Context) {
 // @Tags LoginAPI
 // @Security ApiKeyAuth
 // @Summary Query current user menus based on the current user role
+// @Param Accept-Language header string false "Locale of the menu names, unless the user has a preferred locale"
 // @Success 200 {object} util.ResponseResult{data=[]schema.Menu}
 // @Failure 401 {object} util.ResponseResult
 // @Failure 500 {object} util.ResponseResult
 // @Router /api/v1/current/menus [get]
 func (a *Login) QueryMenus(c *gin.Context) {
-	ctx := c.Request.Context()
+	ctx := util.NewAcceptLanguage(c.Request.Context(), c.GetHeader("Accept-Language"))
 	data, err := a.LoginBIZ.QueryMenus(ctx)
 	if err != nil {
 		util.ResError(c, err)
@@ -20,12 +20,13 @@ type Menu struct {
 // @Param code query string false "Code path of menu (like xxx.xxx.xxx)"
 // @Param name query string false "Name of menu"
 // @Param includeResources query bool false "Whether to include menu resources"
+// @Param Accept-Language header string false "Locale of the menu names, unless the user has a preferred locale"
 // @Success 200 {object} util.ResponseResult{data=[]schema.Menu}
 // @Failure 401 {object} util.ResponseResult
 // @Failure 500 {object} util.ResponseResult
 // @Router /api/v1/menus [get]
 func (a *Menu) Query(c *gin.Context) {
-	ctx := c.Request.Context()
+	ctx := util.NewAcceptLanguage(c.Request.Context(), c.GetHeader("Accept-Language"))
 	var params schema.MenuQueryParam
 	if err := util.ParseQuery(c, &params); err != nil {
 		util.ResError(c, err)
@@ -118,6 +119,59 @@ func (a *Menu) Update(c *gin.Context) {
 	util.ResOK(c)
 }
 
+// Move
+// @Tags MenuAPI
+// @Security ApiKeyAuth
+// @Summary Move menu and its children under a new parent at a position among the new siblings
+// @Param id path string true "unique id"
+// @Param body body schema.MenuMoveForm true "Request body"
+// @Success 200 {object} util.ResponseResult
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/menus/{id}/move [post]
+func (a *Menu) Move(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.MenuMoveForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	err := a.MenuBIZ.Move(ctx, util.GetInt64Param(c, "id"), item)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
+
+// Reorder
+// @Tags MenuAPI
+// @Security ApiKeyAuth
+// @Summary Reorder all the children of a parent in the order of the IDs
+// @Param body body schema.MenuReorderForm true "Request body"
+// @Success 200 {object} util.ResponseResult
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/menus/reorder [put]
+func (a *Menu) Reorder(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.MenuReorderForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	err := a.MenuBIZ.Reorder(ctx, item)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
+
 // Delete
 // @Tags MenuAPI
 // @Security ApiKeyAuth
@@ -136,3 +190,29 @@ func (a *Menu) Delete(c *gin.Context) {
 	}
 	util.ResOK(c)
 }
+
+// Reconcile
+// @Tags MenuAPI
+// @Security ApiKeyAuth
+// @Summary Reconcile the menus and their resources with the menu file, including the removals
+// @Param dryRun query bool false "Only return the diff without applying it"
+// @Success 200 {object} util.ResponseResult{data=schema.MenuDiff}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/menus/reconcile [post]
+func (a *Menu) Reconcile(c *gin.Context) {
+	ctx := c.Request.Context()
+	var params schema.MenuReconcileParam
+	if err := util.ParseQuery(c, &params); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	result, err := a.MenuBIZ.ReconcileFromFile(ctx, params.DryRun)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, result)
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/biz"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// The state is bound to the browser by the cookie, a callback with the state of another browser is rejected.
+const (
+	oidcStateCookie     = "oidc_state"
+	oidcStateCookiePath = "/api/v1/login/oidc"
+)
+
+// OIDC Single sign-on with OpenID Connect providers
+type OIDC struct {
+	OIDCBIZ *biz.OIDC
+}
+
+// Redirect
+// @Tags OIDCAPI
+// @Summary Redirect to the single sign-on provider
+// @Param provider path string true "Name of the provider"
+// @Success 302 "Redirect to the authorization endpoint of the provider"
+// @Failure 404 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/login/oidc/{provider} [get]
+func (a *OIDC) Redirect(c *gin.Context) {
+	ctx := c.Request.Context()
+	authURL, state, err := a.OIDCBIZ.AuthURL(ctx, c.Param("provider"))
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, config.C.Util.OIDC.StateExpired, oidcStateCookiePath, "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback
+// @Tags OIDCAPI
+// @Summary Complete the single sign-on with the code and state passed back by the provider
+// @Param provider path string true "Name of the provider"
+// @Param code query string false "Authorization code"
+// @Param state query string true "State of the login request"
+// @Param error query string false "Error code of the provider"
+// @Success 200 {object} util.ResponseResult{data=schema.LoginToken}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 403 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/login/oidc/{provider}/callback [get]
+func (a *OIDC) Callback(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.OIDCCallbackForm)
+	if err := util.ParseQuery(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	state, _ := c.Cookie(oidcStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, "", -1, oidcStateCookiePath, "", c.Request.TLS != nil, true)
+
+	data, err := a.OIDCBIZ.Callback(ctx, c.Param("provider"), item, state)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, data)
+}
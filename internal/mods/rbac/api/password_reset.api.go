@@ -0,0 +1,62 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/biz"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// PasswordReset Self-service password reset by email
+type PasswordReset struct {
+	PasswordResetBIZ *biz.PasswordReset
+}
+
+// Forgot
+// @Tags PasswordResetAPI
+// @Summary Send a one-time password reset link to the email (the response does not reveal whether the email exists)
+// @Param body body schema.PasswordForgotForm true "Request body"
+// @Success 200 {object} util.ResponseResult
+// @Failure 400 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/password/forgot [post]
+func (a *PasswordReset) Forgot(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.PasswordForgotForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	err := a.PasswordResetBIZ.Forgot(ctx, item.Trim())
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
+
+// Reset
+// @Tags PasswordResetAPI
+// @Summary Reset the password with the token from the email
+// @Param body body schema.PasswordResetForm true "Request body"
+// @Success 200 {object} util.ResponseResult
+// @Failure 400 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/password/reset [post]
+func (a *PasswordReset) Reset(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.PasswordResetForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	err := a.PasswordResetBIZ.Reset(ctx, item.Trim())
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
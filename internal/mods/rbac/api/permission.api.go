@@ -0,0 +1,65 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/biz"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// Permission Checking and explaining the permissions for RBAC
+type Permission struct {
+	PermissionBIZ *biz.Permission
+}
+
+// Check
+// @Tags PermissionAPI
+// @Security ApiKeyAuth
+// @Summary Check whether a user or roles are allowed to request an API, and explain the deciding policy
+// @Param body body schema.PermissionCheckForm true "Request body"
+// @Success 200 {object} util.ResponseResult{data=schema.PermissionCheck}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 404 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/permissions/check [post]
+func (a *Permission) Check(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.PermissionCheckForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	} else if err := item.Validate(); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	result, err := a.PermissionBIZ.Check(ctx, item)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, result)
+}
+
+// QueryUserPermissions
+// @Tags PermissionAPI
+// @Security ApiKeyAuth
+// @Summary List the effective permissions (method and path) of a user
+// @Param id path string true "unique id"
+// @Success 200 {object} util.ResponseResult{data=[]schema.Permission}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 404 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/users/{id}/permissions [get]
+func (a *Permission) QueryUserPermissions(c *gin.Context) {
+	ctx := c.Request.Context()
+	result, err := a.PermissionBIZ.QueryUserPermissions(ctx, util.GetInt64Param(c, "id"))
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, result)
+}
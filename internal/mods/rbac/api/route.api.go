@@ -0,0 +1,60 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/biz"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// Route Discovering the API routes for the menu resources
+type Route struct {
+	RouteBIZ *biz.Route
+}
+
+// Query
+// @Tags MenuAPI
+// @Security ApiKeyAuth
+// @Summary Query the registered API routes to pick the resources of the menus
+// @Param path query string false "Route path"
+// @Param protected query bool false "Only the routes checked by casbin"
+// @Success 200 {object} util.ResponseResult{data=[]schema.Route}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/menus/resources/routes [get]
+func (a *Route) Query(c *gin.Context) {
+	ctx := c.Request.Context()
+	var params schema.RouteQueryParam
+	if err := util.ParseQuery(c, &params); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	result, err := a.RouteBIZ.Query(ctx, params)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, result)
+}
+
+// Report
+// @Tags MenuAPI
+// @Security ApiKeyAuth
+// @Summary Validate the menu resources against the registered routes, listing the stale resources and the uncovered routes
+// @Success 200 {object} util.ResponseResult{data=schema.RouteReport}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/menus/resources/report [get]
+func (a *Route) Report(c *gin.Context) {
+	ctx := c.Request.Context()
+	result, err := a.RouteBIZ.Report(ctx)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, result)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/biz"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// Session management for RBAC
+type Session struct {
+	SessionBIZ *biz.Session
+}
+
+// Query
+// @Tags SessionAPI
+// @Security ApiKeyAuth
+// @Summary Query active sessions of the user
+// @Param id path string true "unique id of user"
+// @Success 200 {object} util.ResponseResult{data=[]schema.Session}
+// @Failure 401 {object} util.ResponseResult
+// @Failure 404 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/users/{id}/sessions [get]
+func (a *Session) Query(c *gin.Context) {
+	ctx := c.Request.Context()
+	data, err := a.SessionBIZ.Query(ctx, util.GetInt64Param(c, "id"))
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, data)
+}
+
+// Delete
+// @Tags SessionAPI
+// @Security ApiKeyAuth
+// @Summary Revoke a session of the user
+// @Param id path string true "unique id of user"
+// @Param sid path string true "unique id of session"
+// @Success 200 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 404 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/users/{id}/sessions/{sid} [delete]
+func (a *Session) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.SessionBIZ.Delete(ctx, util.GetInt64Param(c, "id"), c.Param("sid"))
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
+
+// QueryCurrent
+// @Tags SessionAPI
+// @Security ApiKeyAuth
+// @Summary Query active sessions of the current user
+// @Success 200 {object} util.ResponseResult{data=[]schema.Session}
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/current/sessions [get]
+func (a *Session) QueryCurrent(c *gin.Context) {
+	ctx := c.Request.Context()
+	data, err := a.SessionBIZ.Query(ctx, util.FromUserID(ctx))
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, data)
+}
+
+// DeleteCurrent
+// @Tags SessionAPI
+// @Security ApiKeyAuth
+// @Summary Revoke all other sessions of the current user (sign out other devices)
+// @Success 200 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/current/sessions [delete]
+func (a *Session) DeleteCurrent(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.SessionBIZ.DeleteAll(ctx, util.FromUserID(ctx), util.FromSessionID(ctx))
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/biz"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// TwoFactor TOTP two-factor authentication of the current user
+type TwoFactor struct {
+	TwoFactorBIZ *biz.TwoFactor
+}
+
+// Setup
+// @Tags TwoFactorAPI
+// @Security ApiKeyAuth
+// @Summary Generate a pending TOTP secret with its otpauth URI and QR code
+// @Success 200 {object} util.ResponseResult{data=schema.TwoFactorSetup}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/current/2fa/setup [post]
+func (a *TwoFactor) Setup(c *gin.Context) {
+	ctx := c.Request.Context()
+	data, err := a.TwoFactorBIZ.Setup(ctx)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, data)
+}
+
+// Enable
+// @Tags TwoFactorAPI
+// @Security ApiKeyAuth
+// @Summary Enable two-factor by verifying the first code of the pending secret (recovery codes are only returned once)
+// @Param body body schema.TwoFactorCodeForm true "Request body"
+// @Success 200 {object} util.ResponseResult{data=schema.TwoFactorRecoveryCodes}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/current/2fa/enable [post]
+func (a *TwoFactor) Enable(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.TwoFactorCodeForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	data, err := a.TwoFactorBIZ.Enable(ctx, item.Trim())
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, data)
+}
+
+// Disable
+// @Tags TwoFactorAPI
+// @Security ApiKeyAuth
+// @Summary Disable two-factor with a TOTP or recovery code
+// @Param body body schema.TwoFactorCodeForm true "Request body"
+// @Success 200 {object} util.ResponseResult
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/current/2fa/disable [post]
+func (a *TwoFactor) Disable(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.TwoFactorCodeForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	err := a.TwoFactorBIZ.Disable(ctx, item.Trim())
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
+
+// RegenerateRecoveryCodes
+// @Tags TwoFactorAPI
+// @Security ApiKeyAuth
+// @Summary Replace the recovery codes (the previous codes become invalid)
+// @Param body body schema.TwoFactorCodeForm true "Request body"
+// @Success 200 {object} util.ResponseResult{data=schema.TwoFactorRecoveryCodes}
+// @Failure 400 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/current/2fa/recovery-codes [post]
+func (a *TwoFactor) RegenerateRecoveryCodes(c *gin.Context) {
+	ctx := c.Request.Context()
+	item := new(schema.TwoFactorCodeForm)
+	if err := util.ParseJSON(c, item); err != nil {
+		util.ResError(c, err)
+		return
+	}
+
+	data, err := a.TwoFactorBIZ.RegenerateRecoveryCodes(ctx, item.Trim())
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResSuccess(c, data)
+}
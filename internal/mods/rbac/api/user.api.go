@@ -142,15 +142,45 @@ func (a *User) Delete(c *gin.Context) {
 // ResetPassword
 // @Tags UserAPI
 // @Security ApiKeyAuth
-// @Summary Reset user password by ID
+// @Summary Reset user password by ID, the user must change it at next login
 // @Param id path string true "unique id"
+// @Param body body schema.ResetPasswordForm false "Request body (optional)"
 // @Success 200 {object} util.ResponseResult
+// @Failure 400 {object} util.ResponseResult
 // @Failure 401 {object} util.ResponseResult
 // @Failure 500 {object} util.ResponseResult
 // @Router /api/v1/users/{id}/reset-pwd [patch]
 func (a *User) ResetPassword(c *gin.Context) {
 	ctx := c.Request.Context()
-	err := a.UserBIZ.ResetPassword(ctx, util.GetInt64Param(c, "id"))
+	item := new(schema.ResetPasswordForm)
+	if c.Request.ContentLength != 0 {
+		if err := util.ParseJSON(c, item); err != nil {
+			util.ResError(c, err)
+			return
+		}
+	}
+
+	err := a.UserBIZ.ResetPassword(ctx, util.GetInt64Param(c, "id"), item)
+	if err != nil {
+		util.ResError(c, err)
+		return
+	}
+	util.ResOK(c)
+}
+
+// Unlock
+// @Tags UserAPI
+// @Security ApiKeyAuth
+// @Summary Unlock user login locked by failed attempts
+// @Param id path string true "unique id"
+// @Success 200 {object} util.ResponseResult
+// @Failure 401 {object} util.ResponseResult
+// @Failure 404 {object} util.ResponseResult
+// @Failure 500 {object} util.ResponseResult
+// @Router /api/v1/users/{id}/unlock [patch]
+func (a *User) Unlock(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.UserBIZ.Unlock(ctx, util.GetInt64Param(c, "id"))
 	if err != nil {
 		util.ResError(c, err)
 		return
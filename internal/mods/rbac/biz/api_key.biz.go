@@ -0,0 +1,179 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/rand"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/logging"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+const (
+	apiKeySecretLength   = 40
+	apiKeyPrefixLength   = 8
+	apiKeyLastUsedWindow = time.Minute
+)
+
+// APIKey Personal access tokens of the current user
+type APIKey struct {
+	APIKeyDAL *dal.APIKey
+	UserBIZ   *User
+}
+
+// Query API keys of the current user.
+func (a *APIKey) Query(ctx context.Context, params schema.APIKeyQueryParam) (*schema.APIKeyQueryResult, error) {
+	params.Pagination = true
+	params.UserID = util.FromUserID(ctx)
+
+	result, err := a.APIKeyDAL.Query(ctx, params, schema.APIKeyQueryOptions{
+		QueryOptions: util.QueryOptions{
+			OrderFields: []util.OrderByParam{
+				{Field: "created_at", Direction: util.DESC},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Get the specified API key of the current user.
+func (a *APIKey) Get(ctx context.Context, id int64) (*schema.APIKey, error) {
+	apiKey, err := a.APIKeyDAL.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if apiKey == nil || apiKey.UserID != util.FromUserID(ctx) {
+		return nil, errors.NotFound("", "API key not found")
+	}
+	return apiKey, nil
+}
+
+// checkRoles The roles of an API key must be a subset of the owner's roles.
+func (a *APIKey) checkRoles(ctx context.Context, roleIDs []int64) error {
+	if len(roleIDs) == 0 {
+		return nil
+	}
+
+	userRoleIDs, err := a.UserBIZ.GetRoleIDs(ctx, util.FromUserID(ctx))
+	if err != nil {
+		return err
+	}
+	if len(intersectRoleIDs(userRoleIDs, roleIDs)) != len(roleIDs) {
+		return errors.BadRequest("", "Roles of API key must be owned by the user")
+	}
+	return nil
+}
+
+// Create a new API key for the current user, the plain key is only returned here.
+func (a *APIKey) Create(ctx context.Context, formItem *schema.APIKeyForm) (*schema.APIKey, error) {
+	if util.FromIsRootUser(ctx) {
+		return nil, errors.BadRequest("", "Root user cannot create API keys")
+	}
+	if err := a.checkRoles(ctx, formItem.RoleIDs); err != nil {
+		return nil, err
+	}
+
+	secret, err := rand.Random(apiKeySecretLength, rand.LdigitAndLetter)
+	if err != nil {
+		return nil, err
+	}
+	key := util.APIKeyPrefix + secret
+
+	apiKey := &schema.APIKey{
+		UserID:     util.FromUserID(ctx),
+		Prefix:     key[:len(util.APIKeyPrefix)+apiKeyPrefixLength],
+		SecretHash: hash.SHA256String(key),
+		CreatedAt:  time.Now(),
+	}
+	if err := formItem.FillTo(apiKey); err != nil {
+		return nil, err
+	}
+
+	if err := a.APIKeyDAL.Create(ctx, apiKey); err != nil {
+		return nil, err
+	}
+	apiKey.Key = key
+
+	return apiKey, nil
+}
+
+// Update the specified API key of the current user.
+func (a *APIKey) Update(ctx context.Context, id int64, formItem *schema.APIKeyForm) error {
+	apiKey, err := a.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := a.checkRoles(ctx, formItem.RoleIDs); err != nil {
+		return err
+	}
+
+	if err := formItem.FillTo(apiKey); err != nil {
+		return err
+	}
+	apiKey.UpdatedAt = time.Now()
+
+	return a.APIKeyDAL.Update(ctx, apiKey)
+}
+
+// Delete the specified API key of the current user.
+func (a *APIKey) Delete(ctx context.Context, id int64) error {
+	if _, err := a.Get(ctx, id); err != nil {
+		return err
+	}
+	return a.APIKeyDAL.Delete(ctx, id)
+}
+
+// Authenticate Find the valid API key by the plain key, the last used time is recorded at most once per minute.
+func (a *APIKey) Authenticate(ctx context.Context, key string) (*schema.APIKey, error) {
+	apiKey, err := a.APIKeyDAL.GetBySecretHash(ctx, hash.SHA256String(key))
+	if err != nil {
+		return nil, err
+	} else if apiKey == nil || apiKey.IsExpired() {
+		return nil, nil
+	}
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyLastUsedWindow {
+		if err := a.APIKeyDAL.UpdateLastUsedAt(ctx, apiKey.ID, now); err != nil {
+			logging.Context(ctx).Error("Failed to update last used time of API key", zap.Error(err))
+		}
+		apiKey.LastUsedAt = &now
+	}
+	return apiKey, nil
+}
+
+// RoleIDs The effective roles of the API key, limited to the roles the owner still has.
+func (a *APIKey) RoleIDs(ctx context.Context, apiKey *schema.APIKey) ([]int64, error) {
+	userRoleIDs, err := a.UserBIZ.GetRoleIDs(ctx, apiKey.UserID)
+	if err != nil {
+		return nil, err
+	} else if len(apiKey.RoleIDs) == 0 {
+		return userRoleIDs, nil
+	}
+	return intersectRoleIDs(userRoleIDs, apiKey.RoleIDs), nil
+}
+
+// intersectRoleIDs The roles of b that are also in a.
+func intersectRoleIDs(a, b []int64) []int64 {
+	set := make(map[int64]struct{}, len(a))
+	for _, id := range a {
+		set[id] = struct{}{}
+	}
+
+	result := make([]int64, 0, len(b))
+	for _, id := range b {
+		if _, ok := set[id]; ok {
+			result = append(result, id)
+			delete(set, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,41 @@
+package biz
+
+import (
+	"context"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// loginUserFields Fields of the user required to complete the login
+var loginUserFields = []string{"id", "tenant_id", "dept_id", "username", "password", "status", "token_version", "two_factor_enabled",
+	"password_changed_at", "password_expired", "created_at", "locale"}
+
+// Authenticator Verifies the credentials of a login. A nil user without error means the credentials are rejected
+// and the next authenticator is consulted.
+type Authenticator interface {
+	Authenticate(ctx context.Context, username, password string) (*schema.User, error)
+}
+
+// LocalAuth Authenticate by the password hash stored for the user
+type LocalAuth struct {
+	UserDAL *dal.User
+}
+
+func (a *LocalAuth) Authenticate(ctx context.Context, username, password string) (*schema.User, error) {
+	user, err := a.UserDAL.GetByUsername(ctx, username, schema.UserQueryOptions{
+		QueryOptions: util.QueryOptions{SelectFields: loginUserFields},
+	})
+	if err != nil {
+		return nil, err
+	} else if user == nil {
+		return nil, nil
+	}
+
+	if err := hash.CompareHashAndPassword(user.Password, password); err != nil {
+		return nil, nil
+	}
+	return user, nil
+}
@@ -0,0 +1,560 @@
+package biz
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	orgDAL "github.com/supermicah/go-framework-admin/internal/mods/org/dal"
+	orgSchema "github.com/supermicah/go-framework-admin/internal/mods/org/schema"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// Bundle Exports the menus, roles and role bindings of the users as a portable bundle, and imports them elsewhere
+// matched by code (path) instead of ID.
+type Bundle struct {
+	Trans           *util.Trans
+	MenuBIZ         *Menu
+	RoleBIZ         *Role
+	UserBIZ         *User
+	MenuDAL         *dal.Menu
+	MenuResourceDAL *dal.MenuResource
+	RoleDAL         *dal.Role
+	RoleMenuDAL     *dal.RoleMenu
+	RoleParentDAL   *dal.RoleParent
+	RoleResourceDAL *dal.RoleResource
+	UserDAL         *dal.User
+	UserRoleDAL     *dal.UserRole
+	DeptDAL         *orgDAL.Dept
+	EventBIZ        *Event
+}
+
+// treeCodePaths Code paths (like xxx.xxx.xxx) of the nodes of a tree by ID, from their codes and parent paths.
+func treeCodePaths(codes map[int64]string, parentPaths map[int64]string) map[int64]string {
+	codePaths := make(map[int64]string, len(codes))
+	for id, code := range codes {
+		var items []string
+		for _, pid := range strings.Split(parentPaths[id], util.TreePathDelimiter) {
+			if parentID, err := strconv.ParseInt(pid, 10, 64); err == nil {
+				items = append(items, codes[parentID])
+			}
+		}
+		codePaths[id] = strings.Join(append(items, code), util.TreePathDelimiter)
+	}
+	return codePaths
+}
+
+// queryDeptCodePaths Code paths of all the departments by ID.
+func (a *Bundle) queryDeptCodePaths(ctx context.Context) (map[int64]string, error) {
+	deptResult, err := a.DeptDAL.Query(ctx, orgSchema.DeptQueryParam{}, orgSchema.DeptQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "code", "parent_path"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make(map[int64]string, len(deptResult.Data))
+	parentPaths := make(map[int64]string, len(deptResult.Data))
+	for _, dept := range deptResult.Data {
+		codes[dept.ID] = dept.Code
+		parentPaths[dept.ID] = dept.ParentPath
+	}
+	return treeCodePaths(codes, parentPaths), nil
+}
+
+// queryRoleIDs IDs of all the roles by code.
+func (a *Bundle) queryRoleIDs(ctx context.Context) (map[string]int64, error) {
+	roleResult, err := a.RoleDAL.Query(ctx, schema.RoleQueryParam{}, schema.RoleQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "code"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roleIDs := make(map[string]int64, len(roleResult.Data))
+	for _, role := range roleResult.Data {
+		roleIDs[role.Code] = role.ID
+	}
+	return roleIDs, nil
+}
+
+// invertCodePaths IDs by code path.
+func invertCodePaths(codePaths map[int64]string) map[string]int64 {
+	ids := make(map[string]int64, len(codePaths))
+	for id, codePath := range codePaths {
+		ids[codePath] = id
+	}
+	return ids
+}
+
+// Export the menu tree with its resources, the roles with their menus, parents and resources, and optionally the role
+// bindings of the users.
+func (a *Bundle) Export(ctx context.Context, params schema.BundleExportParam) (*schema.Bundle, error) {
+	bundle := &schema.Bundle{
+		Version:    schema.BundleVersion,
+		ExportedAt: time.Now(),
+	}
+
+	menus, err := a.exportMenus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bundle.Menus = menus
+
+	roles, roleCodes, err := a.exportRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bundle.Roles = roles
+
+	if params.Users {
+		users, err := a.exportUsers(ctx, roleCodes)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Users = users
+	}
+	return bundle, nil
+}
+
+func (a *Bundle) exportMenus(ctx context.Context) (schema.BundleMenus, error) {
+	menuResult, err := a.MenuDAL.Query(ctx, schema.MenuQueryParam{}, schema.MenuQueryOptions{
+		QueryOptions: util.QueryOptions{
+			OrderFields: schema.MenusOrderParams,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resResult, err := a.MenuResourceDAL.Query(ctx, schema.MenuResourceQueryParam{})
+	if err != nil {
+		return nil, err
+	}
+	resources := make(map[int64]schema.MenuResources)
+	for _, res := range resResult.Data {
+		resources[res.MenuID] = append(resources[res.MenuID], res)
+	}
+
+	var convert func(menus schema.Menus) schema.BundleMenus
+	convert = func(menus schema.Menus) schema.BundleMenus {
+		list := make(schema.BundleMenus, 0, len(menus))
+		for _, menu := range menus {
+			item := &schema.BundleMenu{
+				Code:        menu.Code,
+				Name:        menu.Name,
+				Description: menu.Description,
+				Sequence:    menu.Sequence,
+				Type:        menu.Type,
+				Path:        menu.Path,
+				Properties:  menu.Properties,
+				Status:      menu.Status,
+			}
+			for _, res := range resources[menu.ID] {
+				item.Resources = append(item.Resources, &schema.BundleResource{
+					Method: res.Method,
+					Path:   res.Path,
+					Effect: res.Effect,
+				})
+			}
+			if menu.Children != nil {
+				item.Children = convert(*menu.Children)
+			}
+			list = append(list, item)
+		}
+		return list
+	}
+	return convert(menuResult.Data.ToTree()), nil
+}
+
+// exportRoles The roles of the bundle and the codes of the roles by ID.
+func (a *Bundle) exportRoles(ctx context.Context) (schema.BundleRoles, map[int64]string, error) {
+	roleResult, err := a.RoleDAL.Query(ctx, schema.RoleQueryParam{}, schema.RoleQueryOptions{
+		QueryOptions: util.QueryOptions{
+			OrderFields: []util.OrderByParam{
+				{Field: "sequence", Direction: util.DESC},
+				{Field: "created_at", Direction: util.ASC},
+			},
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roleCodes := make(map[int64]string, len(roleResult.Data))
+	roleIDs := make([]int64, 0, len(roleResult.Data))
+	for _, role := range roleResult.Data {
+		roleCodes[role.ID] = role.Code
+		roleIDs = append(roleIDs, role.ID)
+	}
+	if len(roleIDs) == 0 {
+		return nil, roleCodes, nil
+	}
+
+	menuCodePaths, err := a.MenuBIZ.queryCodePaths(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	deptCodePaths, err := a.queryDeptCodePaths(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roleMenuResult, err := a.RoleMenuDAL.Query(ctx, schema.RoleMenuQueryParam{InRoleIDs: roleIDs})
+	if err != nil {
+		return nil, nil, err
+	}
+	roleMenus := make(map[int64]schema.RoleMenus)
+	for _, item := range roleMenuResult.Data {
+		roleMenus[item.RoleID] = append(roleMenus[item.RoleID], item)
+	}
+
+	roleParentResult, err := a.RoleParentDAL.Query(ctx, schema.RoleParentQueryParam{InRoleIDs: roleIDs})
+	if err != nil {
+		return nil, nil, err
+	}
+	roleParents := make(map[int64][]int64)
+	for _, item := range roleParentResult.Data {
+		roleParents[item.RoleID] = append(roleParents[item.RoleID], item.ParentID)
+	}
+
+	roleResourceResult, err := a.RoleResourceDAL.Query(ctx, schema.RoleResourceQueryParam{InRoleIDs: roleIDs})
+	if err != nil {
+		return nil, nil, err
+	}
+	roleResources := make(map[int64]schema.RoleResources)
+	for _, res := range roleResourceResult.Data {
+		roleResources[res.RoleID] = append(roleResources[res.RoleID], res)
+	}
+
+	roles := make(schema.BundleRoles, 0, len(roleResult.Data))
+	for _, role := range roleResult.Data {
+		item := &schema.BundleRole{
+			Code:             role.Code,
+			Name:             role.Name,
+			Description:      role.Description,
+			Sequence:         role.Sequence,
+			Status:           role.Status,
+			RequireTwoFactor: role.RequireTwoFactor,
+			DataScope:        role.DataScope,
+		}
+		for _, deptID := range role.DataScopeDeptIDs {
+			if codePath, ok := deptCodePaths[deptID]; ok {
+				item.DataScopeDepts = append(item.DataScopeDepts, codePath)
+			}
+		}
+		for _, roleMenu := range roleMenus[role.ID] {
+			if codePath, ok := menuCodePaths[roleMenu.MenuID]; ok {
+				item.Menus = append(item.Menus, codePath)
+			}
+		}
+		for _, parentID := range roleParents[role.ID] {
+			if code, ok := roleCodes[parentID]; ok {
+				item.Parents = append(item.Parents, code)
+			}
+		}
+		for _, res := range roleResources[role.ID] {
+			item.Resources = append(item.Resources, &schema.BundleResource{
+				Method: res.Method,
+				Path:   res.Path,
+				Effect: res.Effect,
+			})
+		}
+		roles = append(roles, item)
+	}
+	return roles, roleCodes, nil
+}
+
+func (a *Bundle) exportUsers(ctx context.Context, roleCodes map[int64]string) (schema.BundleUsers, error) {
+	userResult, err := a.UserDAL.Query(ctx, schema.UserQueryParam{}, schema.UserQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "username"},
+			OrderFields:  []util.OrderByParam{{Field: "username"}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	userRoleResult, err := a.UserRoleDAL.Query(ctx, schema.UserRoleQueryParam{})
+	if err != nil {
+		return nil, err
+	}
+	userRoles := userRoleResult.Data.ToUserIDMap()
+
+	users := make(schema.BundleUsers, 0, len(userResult.Data))
+	for _, user := range userResult.Data {
+		item := &schema.BundleUser{Username: user.Username}
+		for _, userRole := range userRoles[user.ID] {
+			code, ok := roleCodes[userRole.RoleID]
+			if !ok {
+				continue
+			}
+			item.Roles = append(item.Roles, &schema.BundleUserRole{
+				Role:     code,
+				StartAt:  userRole.StartAt,
+				ExpireAt: userRole.ExpireAt,
+			})
+		}
+		if len(item.Roles) > 0 {
+			users = append(users, item)
+		}
+	}
+	return users, nil
+}
+
+// Import the bundle: the menus are created or updated by code path (the other menus are kept), the roles are created
+// or updated by code, and the roles of the users found by username are replaced.
+func (a *Bundle) Import(ctx context.Context, bundle *schema.Bundle) (*schema.BundleImportResult, error) {
+	result := new(schema.BundleImportResult)
+
+	diff, err := a.MenuBIZ.Upsert(ctx, bundle.Menus.ToMenus())
+	if err != nil {
+		return nil, err
+	}
+	result.Menus = diff
+
+	if err := a.importRoles(ctx, bundle.Roles, result); err != nil {
+		return nil, err
+	}
+	if err := a.importUsers(ctx, bundle.Users, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// sortRoles Sort the roles of the bundle so that the parents of the bundle come before their children.
+func sortRoles(roles schema.BundleRoles) (schema.BundleRoles, error) {
+	pending := make(map[string]*schema.BundleRole, len(roles))
+	for _, role := range roles {
+		if role.Code == "" {
+			return nil, errors.BadRequest("", "Role code is required")
+		} else if _, ok := pending[role.Code]; ok {
+			return nil, errors.BadRequest("", "Role code '%s' is duplicated", role.Code)
+		}
+		pending[role.Code] = role
+	}
+
+	list := make(schema.BundleRoles, 0, len(roles))
+	for len(list) < len(roles) {
+		n := len(list)
+		for _, role := range roles {
+			if _, ok := pending[role.Code]; !ok {
+				continue
+			}
+			ready := true
+			for _, parent := range role.Parents {
+				if _, ok := pending[parent]; ok {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				delete(pending, role.Code)
+				list = append(list, role)
+			}
+		}
+		if len(list) == n {
+			return nil, errors.BadRequest("", "Role inheritance cannot be circular")
+		}
+	}
+	return list, nil
+}
+
+func (a *Bundle) importRoles(ctx context.Context, roles schema.BundleRoles, result *schema.BundleImportResult) error {
+	if len(roles) == 0 {
+		return nil
+	}
+	roles, err := sortRoles(roles)
+	if err != nil {
+		return err
+	}
+
+	menuIDs, err := a.MenuBIZ.queryCodePaths(ctx)
+	if err != nil {
+		return err
+	}
+	menuCodePaths := invertCodePaths(menuIDs)
+
+	deptIDs, err := a.queryDeptCodePaths(ctx)
+	if err != nil {
+		return err
+	}
+	deptCodePaths := invertCodePaths(deptIDs)
+
+	roleIDs, err := a.queryRoleIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The references are resolved before any change, the parents of the bundle are created first
+	forms := make([]*schema.RoleForm, 0, len(roles))
+	for _, role := range roles {
+		form := &schema.RoleForm{
+			Code:             role.Code,
+			Name:             role.Name,
+			Description:      role.Description,
+			Sequence:         role.Sequence,
+			Status:           role.Status,
+			RequireTwoFactor: role.RequireTwoFactor,
+			DataScope:        role.DataScope,
+		}
+		for _, codePath := range role.Menus {
+			menuID, ok := menuCodePaths[codePath]
+			if !ok {
+				return errors.BadRequest("", "Menu '%s' of role '%s' not found", codePath, role.Code)
+			}
+			form.Menus = append(form.Menus, &schema.RoleMenu{MenuID: menuID})
+		}
+		for _, codePath := range role.DataScopeDepts {
+			deptID, ok := deptCodePaths[codePath]
+			if !ok {
+				return errors.BadRequest("", "Department '%s' of role '%s' not found", codePath, role.Code)
+			}
+			form.DataScopeDeptIDs = append(form.DataScopeDeptIDs, deptID)
+		}
+		for _, res := range role.Resources {
+			form.Resources = append(form.Resources, &schema.RoleResource{
+				Method: res.Method,
+				Path:   res.Path,
+				Effect: res.Effect,
+			})
+		}
+		if err := form.Validate(); err != nil {
+			return err
+		}
+		forms = append(forms, form)
+	}
+
+	known := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		known[role.Code] = struct{}{}
+	}
+	for _, role := range roles {
+		for _, parent := range role.Parents {
+			if _, ok := known[parent]; ok {
+				continue
+			} else if _, ok := roleIDs[parent]; !ok {
+				return errors.BadRequest("", "Parent role '%s' of role '%s' not found", parent, role.Code)
+			}
+		}
+	}
+
+	for i, role := range roles {
+		form := forms[i]
+		for _, parent := range role.Parents {
+			form.ParentIDs = append(form.ParentIDs, roleIDs[parent])
+		}
+		if err := form.Validate(); err != nil {
+			return err
+		}
+
+		if id, ok := roleIDs[role.Code]; ok {
+			if err := a.RoleBIZ.Update(ctx, id, form); err != nil {
+				return err
+			}
+			result.UpdatedRoles = append(result.UpdatedRoles, role.Code)
+			continue
+		}
+
+		created, err := a.RoleBIZ.Create(ctx, form)
+		if err != nil {
+			return err
+		}
+		roleIDs[role.Code] = created.ID
+		result.CreatedRoles = append(result.CreatedRoles, role.Code)
+	}
+	return nil
+}
+
+func (a *Bundle) importUsers(ctx context.Context, users schema.BundleUsers, result *schema.BundleImportResult) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	roleIDs, err := a.queryRoleIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		for _, userRole := range user.Roles {
+			if _, ok := roleIDs[userRole.Role]; !ok {
+				return errors.BadRequest("", "Role '%s' of user '%s' not found", userRole.Role, user.Username)
+			}
+		}
+	}
+
+	for _, item := range users {
+		user, err := a.UserDAL.GetByUsername(ctx, item.Username, schema.UserQueryOptions{
+			QueryOptions: util.QueryOptions{
+				SelectFields: []string{"id", "tenant_id", "username"},
+			},
+		})
+		if err != nil {
+			return err
+		} else if user == nil {
+			result.SkippedUsers = append(result.SkippedUsers, item.Username)
+			continue
+		}
+
+		userRoles := make(schema.UserRoles, 0, len(item.Roles))
+		for _, userRole := range item.Roles {
+			userRoles = append(userRoles, &schema.UserRole{
+				TenantID:  user.TenantID,
+				UserID:    user.ID,
+				RoleID:    roleIDs[userRole.Role],
+				StartAt:   userRole.StartAt,
+				ExpireAt:  userRole.ExpireAt,
+				CreatedAt: time.Now(),
+			})
+		}
+		if err := userRoles.Validate(); err != nil {
+			return err
+		}
+
+		oldRoleIDs, err := a.UserBIZ.GetRoleIDs(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		// Tokens issued with the old roles must not be used anymore
+		revokeTokens := !equalRoleIDs(oldRoleIDs, userRoles.Active(time.Now()).ToRoleIDs())
+		err = a.Trans.Exec(ctx, func(ctx context.Context) error {
+			if err := a.UserRoleDAL.DeleteByUserID(ctx, user.ID); err != nil {
+				return err
+			}
+			for _, userRole := range userRoles {
+				if err := a.UserRoleDAL.Create(ctx, userRole); err != nil {
+					return err
+				}
+			}
+
+			if revokeTokens {
+				return a.UserBIZ.RevokeTokens(ctx, user.ID)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if revokeTokens {
+			err = a.UserBIZ.InvalidateTokens(ctx, user.ID)
+		} else {
+			err = a.EventBIZ.UserChanged(ctx, user.ID)
+		}
+		if err != nil {
+			return err
+		}
+		result.BoundUsers = append(result.BoundUsers, item.Username)
+	}
+	return nil
+}
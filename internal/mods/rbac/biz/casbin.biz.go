@@ -0,0 +1,263 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"go.uber.org/zap"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/pkg/cachex"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/logging"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// Casbinx Keeps the casbin enforcer in sync with the rbac permissions. The policies are loaded once from the database,
+// then the policies of the changed roles are updated incrementally when the events are received. As a fallback for
+// lost events, the instances poll the cache for changes and reconcile all the policies.
+type Casbinx struct {
+	enforcer      *casbin.SyncedEnforcer `wire:"-"`
+	ticker        *time.Ticker           `wire:"-"`
+	mu            sync.Mutex             `wire:"-"`
+	notified      atomic.Value           `wire:"-"` // Last value of the sync key set by this instance
+	Cache         cachex.Cacher
+	CasbinAdapter *dal.CasbinAdapter
+}
+
+func (a *Casbinx) GetEnforcer() *casbin.SyncedEnforcer {
+	return a.enforcer
+}
+
+func (a *Casbinx) Load(ctx context.Context) error {
+	if config.C.Middleware.Casbin.Disable {
+		return nil
+	}
+
+	start := time.Now()
+	modelFile := filepath.Join(config.C.General.WorkDir, config.C.Middleware.Casbin.ModelFile)
+	e, err := casbin.NewSyncedEnforcer(modelFile, a.CasbinAdapter)
+	if err != nil {
+		logging.Context(ctx).Error("Failed to create casbin enforcer", zap.Error(err))
+		return err
+	}
+	// The policies are stored by the role and menu management
+	e.EnableAutoSave(false)
+	e.EnableLog(config.C.IsDebug())
+	a.enforcer = e
+
+	logging.Context(ctx).Info("Casbin load policy",
+		zap.Duration("cost", time.Since(start)),
+		zap.Int("policies", len(e.GetPolicy())),
+	)
+	a.export(ctx)
+
+	a.ticker = time.NewTicker(time.Duration(config.C.Middleware.Casbin.AutoLoadInterval) * time.Second)
+	go a.autoLoad(ctx)
+	return nil
+}
+
+// SyncRoles Update the policies and the inheritance of the roles from the database.
+func (a *Casbinx) SyncRoles(ctx context.Context, roleIDs ...int64) error {
+	if a.enforcer == nil || len(roleIDs) == 0 {
+		return nil
+	}
+
+	// The policies of the roles are loaded whatever the tenant of the caller
+	ctx = util.NewCrossTenant(ctx)
+	a.mu.Lock()
+	err := a.syncRoles(ctx, roleIDs)
+	a.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	a.export(ctx)
+	return nil
+}
+
+func (a *Casbinx) syncRoles(ctx context.Context, roleIDs []int64) error {
+	policies, err := a.CasbinAdapter.QueryPolicies(ctx, roleIDs...)
+	if err != nil {
+		return err
+	}
+	groupingPolicies, err := a.CasbinAdapter.QueryGroupingPolicies(ctx, roleIDs...)
+	if err != nil {
+		return err
+	}
+
+	var current, currentGrouping [][]string
+	for _, roleID := range roleIDs {
+		sub := strconv.FormatInt(roleID, 10)
+		current = append(current, a.enforcer.GetFilteredPolicy(0, sub)...)
+		currentGrouping = append(currentGrouping, a.enforcer.GetFilteredGroupingPolicy(0, sub)...)
+		currentGrouping = append(currentGrouping, a.enforcer.GetFilteredGroupingPolicy(1, sub)...)
+	}
+
+	if err := a.apply(ctx, "p", current, policies); err != nil {
+		return err
+	}
+	return a.apply(ctx, "g", currentGrouping, groupingPolicies)
+}
+
+// reload Reconcile all the policies with the database.
+func (a *Casbinx) reload(ctx context.Context) error {
+	start := time.Now()
+	a.mu.Lock()
+	policies, err := a.CasbinAdapter.QueryPolicies(ctx)
+	if err != nil {
+		a.mu.Unlock()
+		return err
+	}
+	groupingPolicies, err := a.CasbinAdapter.QueryGroupingPolicies(ctx)
+	if err == nil {
+		err = a.apply(ctx, "p", a.enforcer.GetPolicy(), policies)
+	}
+	if err == nil {
+		err = a.apply(ctx, "g", a.enforcer.GetGroupingPolicy(), groupingPolicies)
+	}
+	a.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	logging.Context(ctx).Info("Casbin reload policy",
+		zap.Duration("cost", time.Since(start)),
+		zap.Int("policies", len(policies)),
+		zap.Int("grouping_policies", len(groupingPolicies)),
+	)
+	a.export(ctx)
+	return nil
+}
+
+// apply Remove the current policies of the type (p or g) which are not expected anymore and add the missing ones, the
+// unchanged policies are kept so that requests are never denied during the update.
+func (a *Casbinx) apply(ctx context.Context, ptype string, current, policies [][]string) error {
+	key := func(rule []string) string {
+		return strings.Join(rule, "\n")
+	}
+
+	expected := make(map[string]struct{}, len(policies))
+	for _, rule := range policies {
+		expected[key(rule)] = struct{}{}
+	}
+
+	var removed [][]string
+	currentMapper := make(map[string]struct{}, len(current))
+	for _, rule := range current {
+		k := key(rule)
+		if _, ok := currentMapper[k]; ok {
+			continue
+		}
+		currentMapper[k] = struct{}{}
+		if _, ok := expected[k]; !ok {
+			removed = append(removed, rule)
+		}
+	}
+
+	var added [][]string
+	for _, rule := range policies {
+		if _, ok := currentMapper[key(rule)]; !ok {
+			added = append(added, rule)
+		}
+	}
+
+	removePolicies, addPolicies := a.enforcer.RemovePolicies, a.enforcer.AddPoliciesEx
+	if ptype == "g" {
+		removePolicies, addPolicies = a.enforcer.RemoveGroupingPolicies, a.enforcer.AddGroupingPoliciesEx
+	}
+	if len(removed) > 0 {
+		if _, err := removePolicies(removed); err != nil {
+			return errors.Wrap(err, "failed to remove casbin policies")
+		}
+	}
+	if len(added) > 0 {
+		if _, err := addPolicies(added); err != nil {
+			return errors.Wrap(err, "failed to add casbin policies")
+		}
+	}
+
+	if len(removed) > 0 || len(added) > 0 {
+		logging.Context(ctx).Debug("Casbin policies updated",
+			zap.String("ptype", ptype),
+			zap.Int("removed", len(removed)),
+			zap.Int("added", len(added)),
+		)
+	}
+	return nil
+}
+
+// Notify Record the change for the instances polling the cache.
+func (a *Casbinx) Notify(ctx context.Context) error {
+	if a.enforcer == nil {
+		return nil
+	}
+
+	val := strconv.FormatInt(time.Now().UnixNano(), 10)
+	a.notified.Store(val)
+	return a.Cache.Set(ctx, config.CacheNSForRole, config.CacheKeyForSyncToCasbin, val)
+}
+
+// export Write the policies to the file for debugging if it is configured.
+func (a *Casbinx) export(ctx context.Context) {
+	name := config.C.Middleware.Casbin.GenPolicyFile
+	if name == "" {
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	for _, rule := range a.enforcer.GetPolicy() {
+		_, _ = fmt.Fprintf(buf, "p, %s\n", strings.Join(rule, ", "))
+	}
+	for _, rule := range a.enforcer.GetGroupingPolicy() {
+		_, _ = fmt.Fprintf(buf, "g, %s\n", strings.Join(rule, ", "))
+	}
+
+	policyFile := filepath.Join(config.C.General.WorkDir, name)
+	_ = os.MkdirAll(filepath.Dir(policyFile), 0755)
+	if err := os.WriteFile(policyFile, buf.Bytes(), 0644); err != nil {
+		logging.Context(ctx).Warn("Failed to export casbin policy", zap.Error(err), zap.String("file", policyFile))
+	}
+}
+
+func (a *Casbinx) autoLoad(ctx context.Context) {
+	var lastUpdated string
+	for range a.ticker.C {
+		val, ok, err := a.Cache.Get(ctx, config.CacheNSForRole, config.CacheKeyForSyncToCasbin)
+		if err != nil {
+			logging.Context(ctx).Error("Failed to get cache", zap.Error(err), zap.String("key", config.CacheKeyForSyncToCasbin))
+			continue
+		} else if !ok || val == lastUpdated {
+			continue
+		}
+
+		// The changes of this instance are already applied
+		if notified, _ := a.notified.Load().(string); notified == val {
+			lastUpdated = val
+			continue
+		}
+
+		if err := a.reload(ctx); err != nil {
+			logging.Context(ctx).Error("Failed to reload casbin policy", zap.Error(err))
+		} else {
+			lastUpdated = val
+		}
+	}
+}
+
+func (a *Casbinx) Release(ctx context.Context) error {
+	if a.ticker != nil {
+		a.ticker.Stop()
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	orgDAL "github.com/supermicah/go-framework-admin/internal/mods/org/dal"
+	orgSchema "github.com/supermicah/go-framework-admin/internal/mods/org/schema"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/cachex"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// DataScope Resolve the rows visible to a user from the data scopes of its roles, the data scopes of the roles are
+// cached until the roles are changed.
+type DataScope struct {
+	Cache   cachex.Cacher
+	RoleDAL *dal.Role
+	DeptDAL *orgDAL.Dept
+}
+
+// Resolve Union of the data scopes of the enabled roles, nil if one of them sees all the rows.
+func (a *DataScope) Resolve(ctx context.Context, userID, deptID int64, roleIDs []int64) (*util.DataScope, error) {
+	dataScope := &util.DataScope{}
+	deptIDs := make(map[int64]struct{})
+	addDept := func(id int64) {
+		if _, ok := deptIDs[id]; ok || id == 0 {
+			return
+		}
+		deptIDs[id] = struct{}{}
+		dataScope.DeptIDs = append(dataScope.DeptIDs, id)
+	}
+
+	for _, roleID := range roleIDs {
+		role, err := a.getRole(ctx, roleID)
+		if err != nil {
+			return nil, err
+		} else if role == nil || role.Status != schema.RoleStatusEnabled {
+			continue
+		}
+
+		switch role.DataScope {
+		case schema.RoleDataScopeSelf:
+			dataScope.UserID = userID
+		case schema.RoleDataScopeDept:
+			addDept(deptID)
+		case schema.RoleDataScopeDeptAndChildren:
+			addDept(deptID)
+			childIDs, err := a.queryChildDeptIDs(ctx, deptID)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range childIDs {
+				addDept(id)
+			}
+		case schema.RoleDataScopeCustom:
+			for _, id := range role.DataScopeDeptIDs {
+				addDept(id)
+			}
+		default:
+			return nil, nil
+		}
+	}
+	return dataScope, nil
+}
+
+// Delete Drop the cached data scopes of the roles.
+func (a *DataScope) Delete(ctx context.Context, roleIDs ...int64) error {
+	for _, roleID := range roleIDs {
+		if err := a.Cache.Delete(ctx, config.CacheNSForDataScope, fmt.Sprintf("%d", roleID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryChildDeptIDs Query the departments under the department of the user.
+func (a *DataScope) queryChildDeptIDs(ctx context.Context, deptID int64) ([]int64, error) {
+	if deptID == 0 {
+		return nil, nil
+	}
+
+	dept, err := a.DeptDAL.Get(ctx, deptID, orgSchema.DeptQueryOptions{
+		QueryOptions: util.QueryOptions{SelectFields: []string{"id", "parent_path"}},
+	})
+	if err != nil {
+		return nil, err
+	} else if dept == nil {
+		return nil, nil
+	}
+
+	childResult, err := a.DeptDAL.Query(ctx, orgSchema.DeptQueryParam{
+		ParentPathPrefix: dept.ChildPath(),
+	}, orgSchema.DeptQueryOptions{
+		QueryOptions: util.QueryOptions{SelectFields: []string{"id"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return childResult.Data.ToIDs(), nil
+}
+
+func (a *DataScope) getRole(ctx context.Context, roleID int64) (*schema.Role, error) {
+	key := fmt.Sprintf("%d", roleID)
+	val, ok, err := a.Cache.Get(ctx, config.CacheNSForDataScope, key)
+	if err != nil {
+		return nil, err
+	} else if ok {
+		var role schema.Role
+		if err := json.Unmarshal([]byte(val), &role); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return &role, nil
+	}
+
+	role, err := a.RoleDAL.Get(util.NewCrossTenant(ctx), roleID, schema.RoleQueryOptions{
+		QueryOptions: util.QueryOptions{SelectFields: []string{"id", "status", "data_scope", "data_scope_dept_ids"}},
+	})
+	if err != nil {
+		return nil, err
+	} else if role == nil {
+		role = &schema.Role{ID: roleID, Status: schema.RoleStatusDisabled}
+	}
+
+	b, err := json.Marshal(role)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	err = a.Cache.Set(ctx, config.CacheNSForDataScope, key, string(b),
+		time.Duration(config.C.Dictionary.UserCacheExp)*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
@@ -0,0 +1,110 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/bus"
+	"github.com/supermicah/go-framework-admin/pkg/cachex"
+	"github.com/supermicah/go-framework-admin/pkg/encoding/json"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+)
+
+// Event Publishes the changes of the rbac permissions on the bus, every instance applies them when received. The
+// changes are applied by the publishing instance before Publish returns.
+type Event struct {
+	Bus          bus.Bus
+	Cache        cachex.Cacher
+	Casbinx      *Casbinx
+	DataScopeBIZ *DataScope
+}
+
+// Subscribe Apply the changes published by any instance.
+func (a *Event) Subscribe(ctx context.Context) error {
+	handlers := map[string]bus.Handler{
+		schema.EventRoleChanged: a.handleRoleChanged,
+		schema.EventMenuChanged: a.handleMenuChanged,
+		schema.EventUserChanged: a.handleUserChanged,
+	}
+	for topic, handler := range handlers {
+		if err := a.Bus.Subscribe(ctx, topic, handler); err != nil {
+			return errors.Wrapf(err, "failed to subscribe %s", topic)
+		}
+	}
+	return nil
+}
+
+// RoleChanged Reload the policies of the roles, it must be called after the changes are committed.
+func (a *Event) RoleChanged(ctx context.Context, roleIDs ...int64) error {
+	if len(roleIDs) == 0 {
+		return nil
+	}
+	if err := a.publish(ctx, schema.EventRoleChanged, &schema.RoleChangedEvent{RoleIDs: roleIDs}); err != nil {
+		return err
+	}
+	return a.Casbinx.Notify(ctx)
+}
+
+// MenuChanged Reload the policies of the roles granted the menus, it must be called after the changes are committed.
+func (a *Event) MenuChanged(ctx context.Context, menuIDs, roleIDs []int64) error {
+	if len(roleIDs) == 0 {
+		return nil
+	}
+	if err := a.publish(ctx, schema.EventMenuChanged, &schema.MenuChangedEvent{
+		MenuIDs: menuIDs,
+		RoleIDs: roleIDs,
+	}); err != nil {
+		return err
+	}
+	return a.Casbinx.Notify(ctx)
+}
+
+// UserChanged Drop the cached roles and token versions of the users.
+func (a *Event) UserChanged(ctx context.Context, userIDs ...int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return a.publish(ctx, schema.EventUserChanged, &schema.UserChangedEvent{UserIDs: userIDs})
+}
+
+func (a *Event) publish(ctx context.Context, topic string, event interface{}) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return a.Bus.Publish(ctx, topic, payload)
+}
+
+func (a *Event) handleRoleChanged(ctx context.Context, topic string, payload []byte) error {
+	var event schema.RoleChangedEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := a.DataScopeBIZ.Delete(ctx, event.RoleIDs...); err != nil {
+		return err
+	}
+	return a.Casbinx.SyncRoles(ctx, event.RoleIDs...)
+}
+
+func (a *Event) handleMenuChanged(ctx context.Context, topic string, payload []byte) error {
+	var event schema.MenuChangedEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return errors.WithStack(err)
+	}
+	return a.Casbinx.SyncRoles(ctx, event.RoleIDs...)
+}
+
+func (a *Event) handleUserChanged(ctx context.Context, topic string, payload []byte) error {
+	var event schema.UserChangedEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, userID := range event.UserIDs {
+		if err := a.Cache.Delete(ctx, config.CacheNSForUser, fmt.Sprintf("%d", userID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
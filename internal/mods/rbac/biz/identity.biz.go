@@ -0,0 +1,268 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/rand"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/logging"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// ExternalUser User authenticated by an external identity provider
+type ExternalUser struct {
+	Provider string   // Name of the provider
+	Subject  string   // Unique and stable ID of the user at the provider
+	Username string   // Username of provisioned users
+	Name     string   // Display name, kept up to date if not empty
+	Email    string   // Email, kept up to date if not empty
+	Groups   []string // Groups mapped to roles
+}
+
+// ProvisionOptions How external users are provisioned
+type ProvisionOptions struct {
+	RoleMapping  map[string]string // Group => role code, if empty then group names are used as role codes
+	DefaultRoles []string          // Role codes given to provisioned users
+	AutoCreate   bool              // Provision unknown users
+	SyncRoles    bool              // Replace the roles of linked users with the mapped groups
+	ErrorID      string            // ID of the errors for users who cannot be provisioned
+}
+
+// Identity Links the users of external identity providers to local users
+type Identity struct {
+	Trans           *util.Trans
+	UserDAL         *dal.User
+	UserRoleDAL     *dal.UserRole
+	RoleDAL         *dal.Role
+	UserIdentityDAL *dal.UserIdentity
+	UserBIZ         *User
+}
+
+// identityUserFields Fields of the user required to complete the login
+var identityUserFields = []string{"id", "tenant_id", "dept_id", "username", "name", "email", "status", "token_version",
+	"two_factor_enabled", "created_at", "locale"}
+
+// Resolve Find the user linked to the subject at the provider, or provision a new one. The profile of a linked user
+// is updated and its roles are replaced by the mapped groups if SyncRoles is enabled.
+func (a *Identity) Resolve(ctx context.Context, ext *ExternalUser, opts ProvisionOptions) (*schema.User, error) {
+	identity, err := a.UserIdentityDAL.GetBySubject(ctx, ext.Provider, ext.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *schema.User
+	if identity != nil {
+		user, err = a.getUser(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		} else if user == nil {
+			// The linked user was removed, the identity is provisioned again
+			if err := a.UserIdentityDAL.Delete(ctx, identity.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if user == nil {
+		if !opts.AutoCreate {
+			logging.Context(ctx).Info("External user is not provisioned", zap.String("provider", ext.Provider),
+				zap.String("subject", ext.Subject))
+			return nil, errors.Forbidden(opts.ErrorID, "User is not registered, please contact the administrator")
+		}
+		return a.create(ctx, ext, opts)
+	}
+
+	if err := a.updateProfile(ctx, user, ext); err != nil {
+		return nil, err
+	}
+
+	if opts.SyncRoles {
+		if changed, err := a.syncRoles(ctx, ext, opts, user.ID); err != nil {
+			return nil, err
+		} else if changed {
+			// The token version was bumped by revoking the tokens
+			return a.getUser(ctx, user.ID)
+		}
+	}
+	return user, nil
+}
+
+func (a *Identity) getUser(ctx context.Context, id int64) (*schema.User, error) {
+	user, err := a.UserDAL.Get(ctx, id, schema.UserQueryOptions{
+		QueryOptions: util.QueryOptions{SelectFields: identityUserFields},
+	})
+	if err != nil || user == nil {
+		return nil, err
+	}
+
+	// The password of external users is managed by the provider, it never expires locally
+	now := time.Now()
+	user.PasswordChangedAt = &now
+	return user, nil
+}
+
+func (a *Identity) updateProfile(ctx context.Context, user *schema.User, ext *ExternalUser) error {
+	var fields []string
+	if ext.Name != "" && ext.Name != user.Name {
+		user.Name = ext.Name
+		fields = append(fields, "name")
+	}
+	if ext.Email != "" && ext.Email != user.Email {
+		user.Email = ext.Email
+		fields = append(fields, "email")
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	user.UpdatedAt = time.Now()
+	return a.UserDAL.Update(ctx, user, append(fields, "updated_at")...)
+}
+
+// mapRoles Get the roles of the groups of the user and the default roles.
+func (a *Identity) mapRoles(ctx context.Context, ext *ExternalUser, opts ProvisionOptions) (schema.Roles, error) {
+	codes := append([]string{}, opts.DefaultRoles...)
+	for _, group := range ext.Groups {
+		if len(opts.RoleMapping) == 0 {
+			codes = append(codes, group)
+		} else if code, ok := opts.RoleMapping[group]; ok {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	roleResult, err := a.RoleDAL.Query(ctx, schema.RoleQueryParam{
+		InCodes: codes,
+		Status:  schema.RoleStatusEnabled,
+	}, schema.RoleQueryOptions{
+		QueryOptions: util.QueryOptions{SelectFields: []string{"id"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return roleResult.Data, nil
+}
+
+func (a *Identity) create(ctx context.Context, ext *ExternalUser, opts ProvisionOptions) (*schema.User, error) {
+	// Existing accounts are never linked by the username, they could belong to someone else
+	if exists, err := a.UserDAL.ExistsUsername(ctx, ext.Username); err != nil {
+		return nil, err
+	} else if exists || ext.Username == config.C.General.Root.Username {
+		logging.Context(ctx).Warn("Username of external user already exists", zap.String("provider", ext.Provider),
+			zap.String("username", ext.Username))
+		return nil, errors.BadRequest(opts.ErrorID, "Username already exists, please contact the administrator")
+	}
+
+	roles, err := a.mapRoles(ctx, ext, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user signs in by the provider only, the random password is never handed out
+	pwd, err := rand.Random(32, rand.LdigitAndLetter)
+	if err != nil {
+		return nil, err
+	}
+	hashPass, err := hash.GeneratePassword(pwd)
+	if err != nil {
+		return nil, errors.BadRequest("", "Failed to generate hash password: %s", err.Error())
+	}
+
+	name := ext.Name
+	if name == "" {
+		name = ext.Username
+	}
+	now := time.Now()
+	user := &schema.User{
+		Username:          ext.Username,
+		Name:              name,
+		Password:          hashPass,
+		Email:             ext.Email,
+		Status:            schema.UserStatusActivated,
+		PasswordChangedAt: &now,
+		CreatedAt:         now,
+	}
+
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
+		if err := a.UserDAL.Create(ctx, user); err != nil {
+			return err
+		}
+		for _, role := range roles {
+			if err := a.UserRoleDAL.Create(ctx, &schema.UserRole{
+				TenantID:  user.TenantID,
+				UserID:    user.ID,
+				RoleID:    role.ID,
+				CreatedAt: now,
+			}); err != nil {
+				return err
+			}
+		}
+		return a.UserIdentityDAL.Create(ctx, &schema.UserIdentity{
+			UserID:    user.ID,
+			Provider:  ext.Provider,
+			Subject:   ext.Subject,
+			CreatedAt: now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logging.Context(ctx).Info("External user provisioned", zap.String("provider", ext.Provider),
+		zap.String("username", ext.Username), zap.Int64("user_id", user.ID))
+	return user, nil
+}
+
+// syncRoles Replace the roles of the user with the mapped groups, reports whether the roles changed.
+func (a *Identity) syncRoles(ctx context.Context, ext *ExternalUser, opts ProvisionOptions, userID int64) (bool, error) {
+	roles, err := a.mapRoles(ctx, ext, opts)
+	if err != nil {
+		return false, err
+	}
+
+	oldRoleIDs, err := a.UserBIZ.GetRoleIDs(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	roleIDs := make([]int64, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	if equalRoleIDs(oldRoleIDs, roleIDs) {
+		return false, nil
+	}
+
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
+		if err := a.UserRoleDAL.DeleteByUserID(ctx, userID); err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, roleID := range roleIDs {
+			if err := a.UserRoleDAL.Create(ctx, &schema.UserRole{
+				UserID:    userID,
+				RoleID:    roleID,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}); err != nil {
+				return err
+			}
+		}
+		// Tokens issued with the old roles must not be used anymore
+		return a.UserBIZ.RevokeTokens(ctx, userID)
+	})
+	if err != nil {
+		return false, err
+	} else if err := a.UserBIZ.InvalidateTokens(ctx, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,204 @@
+package biz
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-ldap/ldap/v3"
+	"go.uber.org/zap"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/logging"
+)
+
+// ldapProvider Provider name of the identities of directory users
+const ldapProvider = "ldap"
+
+// LDAPAuth Authenticate by binding to the directory (LDAP or Active Directory) as the user. The user is searched by
+// the service account, then the password is verified by a bind with the DN of the user.
+type LDAPAuth struct {
+	IdentityBIZ *Identity
+}
+
+func (a *LDAPAuth) Authenticate(ctx context.Context, username, password string) (*schema.User, error) {
+	cfg := config.C.Util.LDAP
+	if password == "" {
+		return nil, nil
+	}
+
+	conn, err := a.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if cfg.BindDN != "" {
+		if err := conn.Bind(cfg.BindDN, cfg.BindPassword); err != nil {
+			return nil, errors.Wrap(err, "failed to bind ldap service account")
+		}
+	}
+
+	var attrs []string
+	for _, attr := range []string{cfg.UsernameAttribute, cfg.NameAttribute, cfg.EmailAttribute, cfg.GroupAttribute,
+		cfg.SubjectAttribute} {
+		if attr != "" {
+			attrs = append(attrs, attr)
+		}
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2,
+		cfg.Timeout, false, strings.ReplaceAll(cfg.UserFilter, "%s", ldap.EscapeFilter(username)), attrs, nil))
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, errors.Wrap(err, "failed to search ldap user")
+	} else if len(result.Entries) != 1 {
+		if len(result.Entries) > 1 {
+			logging.Context(ctx).Warn("Ambiguous ldap user", zap.String("username", username))
+		}
+		return nil, nil
+	}
+	entry := result.Entries[0]
+
+	// Groups are searched by the service account before binding as the user
+	groups, err := a.groups(conn, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to bind ldap user")
+	}
+
+	subject := entry.DN
+	if cfg.SubjectAttribute != "" {
+		if subject = entry.GetEqualFoldAttributeValue(cfg.SubjectAttribute); subject == "" {
+			logging.Context(ctx).Warn("Ldap user without subject attribute", zap.String("dn", entry.DN))
+			return nil, nil
+		}
+		// Binary IDs such as the objectGUID of Active Directory are stored in hex
+		if !utf8.ValidString(subject) {
+			subject = hex.EncodeToString([]byte(subject))
+		}
+	}
+	ldapUsername := entry.GetEqualFoldAttributeValue(cfg.UsernameAttribute)
+	if ldapUsername == "" {
+		ldapUsername = username
+	}
+
+	user, err := a.IdentityBIZ.Resolve(ctx, &ExternalUser{
+		Provider: ldapProvider,
+		Subject:  subject,
+		Username: ldapUsername,
+		Name:     entry.GetEqualFoldAttributeValue(cfg.NameAttribute),
+		Email:    entry.GetEqualFoldAttributeValue(cfg.EmailAttribute),
+		Groups:   groups,
+	}, ProvisionOptions{
+		RoleMapping:  cfg.RoleMapping,
+		DefaultRoles: cfg.DefaultRoles,
+		AutoCreate:   cfg.AutoCreate,
+		SyncRoles:    cfg.SyncRoles,
+		ErrorID:      config.ErrLDAPLoginFailedID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logging.Context(ctx).Info("Login by ldap", zap.String("username", user.Username), zap.String("dn", entry.DN))
+	return user, nil
+}
+
+func (a *LDAPAuth) dial() (*ldap.Conn, error) {
+	cfg := config.C.Util.LDAP
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	if name := cfg.CAFile; name != "" {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(config.C.General.WorkDir, name)
+		}
+		buf, err := os.ReadFile(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read ldap ca file %s", name)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(buf) {
+			return nil, errors.Errorf("no certificate found in ldap ca file %s", name)
+		}
+	}
+
+	// The certificate is verified against the host of the URL, also after StartTLS
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid ldap url %s", cfg.URL)
+	}
+	tlsConfig.ServerName = u.Hostname()
+
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	conn, err := ldap.DialURL(cfg.URL, ldap.DialWithDialer(&net.Dialer{Timeout: timeout}),
+		ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to ldap")
+	}
+	conn.SetTimeout(timeout)
+
+	if cfg.StartTLS && u.Scheme == "ldap" {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			_ = conn.Close()
+			return nil, errors.Wrap(err, "failed to start tls with ldap")
+		}
+	}
+	return conn, nil
+}
+
+// groups Get the groups of the user, a group is named by its DN if the DN is mapped to a role, otherwise by its CN.
+func (a *LDAPAuth) groups(conn *ldap.Conn, entry *ldap.Entry) ([]string, error) {
+	cfg := config.C.Util.LDAP
+	dns := entry.GetEqualFoldAttributeValues(cfg.GroupAttribute)
+	if cfg.GroupBaseDN != "" {
+		result, err := conn.Search(ldap.NewSearchRequest(cfg.GroupBaseDN, ldap.ScopeWholeSubtree,
+			ldap.NeverDerefAliases, 0, cfg.Timeout, false,
+			strings.ReplaceAll(cfg.GroupFilter, "%s", ldap.EscapeFilter(entry.DN)), []string{"cn"}, nil))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to search ldap groups")
+		}
+		dns = make([]string, 0, len(result.Entries))
+		for _, groupEntry := range result.Entries {
+			dns = append(dns, groupEntry.DN)
+		}
+	}
+
+	// DNs are case-insensitive
+	mappedDNs := make(map[string]string, len(cfg.RoleMapping))
+	for group := range cfg.RoleMapping {
+		mappedDNs[strings.ToLower(group)] = group
+	}
+
+	groups := make([]string, 0, len(dns))
+	for _, dn := range dns {
+		if group, ok := mappedDNs[strings.ToLower(dn)]; ok {
+			groups = append(groups, group)
+		} else {
+			groups = append(groups, rdnValue(dn))
+		}
+	}
+	return groups, nil
+}
+
+// rdnValue Get the value of the first relative distinguished name, e.g. "admins" of "cn=admins,ou=groups,dc=example".
+func rdnValue(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 || len(parsed.RDNs[0].Attributes) == 0 {
+		return dn
+	}
+	return parsed.RDNs[0].Attributes[0].Value
+}
@@ -0,0 +1,150 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/pkg/cachex"
+	"github.com/supermicah/go-framework-admin/pkg/encoding/json"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/logging"
+)
+
+// loginAttempts Failed login attempts of a username or client IP, stored in the cache.
+type loginAttempts struct {
+	Failures      int   `json:"n"`
+	FirstFailedAt int64 `json:"since"`
+	Locks         int   `json:"locks"`
+	LockedUntil   int64 `json:"until"` // Unix milliseconds
+}
+
+// Lockout Brute-force protection of login, usernames and client IPs are locked after too many failed attempts
+type Lockout struct {
+	Cache cachex.Cacher
+}
+
+func lockoutUserKey(username string) string {
+	return "user:" + username
+}
+
+func lockoutIPKey(ip string) string {
+	return "ip:" + ip
+}
+
+func (a *Lockout) get(ctx context.Context, key string) (*loginAttempts, error) {
+	val, ok, err := a.Cache.Get(ctx, config.CacheNSForLockout, key)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := new(loginAttempts)
+	if ok {
+		_ = json.Unmarshal([]byte(val), attempts)
+	}
+	return attempts, nil
+}
+
+// Check Reject the login if the username or the client IP is locked.
+func (a *Lockout) Check(ctx context.Context, username, ip string) error {
+	if config.C.Util.Lockout.Disable {
+		return nil
+	}
+
+	keys := []string{lockoutUserKey(username)}
+	if ip != "" {
+		keys = append(keys, lockoutIPKey(ip))
+	}
+
+	now := time.Now()
+	for _, key := range keys {
+		attempts, err := a.get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if until := time.UnixMilli(attempts.LockedUntil); until.After(now) {
+			return errors.TooManyRequests(config.ErrLoginLockedID,
+				"Too many failed login attempts, please try again in %d seconds", int(until.Sub(now).Seconds()+1))
+		}
+	}
+	return nil
+}
+
+// Fail Count a failed login attempt of the username and the client IP, the lock duration doubles on each
+// further lockout up to the maximum.
+func (a *Lockout) Fail(ctx context.Context, username, ip string) error {
+	cfg := config.C.Util.Lockout
+	if cfg.Disable {
+		return nil
+	}
+
+	if err := a.fail(ctx, lockoutUserKey(username), cfg.MaxAttempts, zap.String("username", username)); err != nil {
+		return err
+	}
+	if ip != "" {
+		return a.fail(ctx, lockoutIPKey(ip), cfg.MaxIPAttempts, zap.String("client_ip", ip))
+	}
+	return nil
+}
+
+func (a *Lockout) fail(ctx context.Context, key string, maxAttempts int, field zap.Field) error {
+	if maxAttempts <= 0 {
+		return nil
+	}
+
+	attempts, err := a.get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.C.Util.Lockout
+	now := time.Now()
+	if now.Sub(time.Unix(attempts.FirstFailedAt, 0)) > time.Duration(cfg.Window)*time.Second {
+		attempts.Failures = 0
+		attempts.FirstFailedAt = now.Unix()
+	}
+	attempts.Failures++
+
+	if attempts.Failures >= maxAttempts {
+		duration := time.Duration(cfg.LockDuration) * time.Second
+		maxDuration := time.Duration(cfg.MaxLockDuration) * time.Second
+		for i := 0; i < attempts.Locks && duration < maxDuration; i++ {
+			duration *= 2
+		}
+		if duration > maxDuration {
+			duration = maxDuration
+		}
+
+		attempts.Locks++
+		attempts.Failures = 0
+		attempts.LockedUntil = now.Add(duration).UnixMilli()
+		logging.Context(logging.NewTag(ctx, logging.TagKeyLogin)).Warn("Login locked after too many failed attempts",
+			field, zap.Int("locks", attempts.Locks), zap.Duration("duration", duration))
+	}
+
+	// Keep the lock count long enough for the backoff to take effect
+	expiration := time.Duration(cfg.MaxLockDuration) * time.Second
+	if window := time.Duration(cfg.Window) * time.Second; window > expiration {
+		expiration = window
+	}
+	return a.Cache.Set(ctx, config.CacheNSForLockout, key, json.MarshalToString(attempts), expiration)
+}
+
+// Reset Forget the failed attempts of the username after a successful login.
+func (a *Lockout) Reset(ctx context.Context, username string) error {
+	if config.C.Util.Lockout.Disable {
+		return nil
+	}
+	return a.Cache.Delete(ctx, config.CacheNSForLockout, lockoutUserKey(username))
+}
+
+// Unlock Remove the lock and the failed attempts of the username.
+func (a *Lockout) Unlock(ctx context.Context, username string) error {
+	if err := a.Cache.Delete(ctx, config.CacheNSForLockout, lockoutUserKey(username)); err != nil {
+		return err
+	}
+	logging.Context(logging.NewTag(ctx, logging.TagKeyLogin)).Info("Login unlocked", zap.String("username", username))
+	return nil
+}
@@ -2,10 +2,12 @@ package biz
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/LyricTian/captcha"
@@ -25,14 +27,42 @@ import (
 
 const illegalUserID = -1
 
+// expiredPasswordRequests Requests allowed while the password has expired, the others are rejected until the
+// password is changed
+var expiredPasswordRequests = map[string]struct{}{
+	http.MethodGet + " /api/v1/current/user":     {},
+	http.MethodPut + " /api/v1/current/password": {},
+	http.MethodPost + " /api/v1/current/logout":  {},
+}
+
+// apiKeyCurrentRequests Requests of the current user allowed with an API key, the self-service ones (API keys,
+// sessions, 2FA, password and profile) require a login, otherwise a key could widen its own roles
+var apiKeyCurrentRequests = map[string]struct{}{
+	http.MethodGet + " /api/v1/current/user":    {},
+	http.MethodGet + " /api/v1/current/menus":   {},
+	http.MethodPost + " /api/v1/current/logout": {},
+}
+
 // Login management for RBAC
 type Login struct {
-	Cache       cachex.Cacher
-	Auth        jwtx.Auther
-	UserDAL     *dal.User
-	UserRoleDAL *dal.UserRole
-	MenuDAL     *dal.Menu
-	UserBIZ     *User
+	Cache         cachex.Cacher
+	Trans         *util.Trans
+	Auth          jwtx.Auther
+	UserDAL       *dal.User
+	UserRoleDAL   *dal.UserRole
+	MenuDAL       *dal.Menu
+	RoleParentDAL *dal.RoleParent
+	MenuBIZ       *Menu
+	UserBIZ       *User
+	SessionBIZ    *Session
+	APIKeyBIZ     *APIKey
+	TwoFactorBIZ  *TwoFactor
+	LockoutBIZ    *Lockout
+	PasswordBIZ   *Password
+	LocalAuthBIZ  *LocalAuth
+	LDAPAuthBIZ   *LDAPAuth
+	TenantBIZ     *Tenant
+	DataScopeBIZ  *DataScope
 }
 
 func (a *Login) ParseUserID(c *gin.Context) (int64, error) {
@@ -49,59 +79,157 @@ func (a *Login) ParseUserID(c *gin.Context) (int64, error) {
 
 	ctx := c.Request.Context()
 	ctx = util.NewUserToken(ctx, token)
+	if util.IsAPIKey(token) {
+		return a.parseAPIKey(ctx, c, token)
+	}
 
-	userIDStr, err := a.Auth.ParseSubject(ctx, token)
+	claims, err := a.Auth.ParseToken(ctx, token)
 	if err != nil {
 		if errors.Is(err, jwtx.ErrInvalidToken) {
 			return illegalUserID, invalidToken
 		}
 		return illegalUserID, err
 	}
-	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
 	if err != nil {
 		return illegalUserID, err
 	}
+	ctx = util.NewSessionID(ctx, claims.FamilyID)
 	if userID == rootID {
 		c.Request = c.Request.WithContext(util.NewIsRootUser(ctx))
 		return userID, nil
 	}
 
+	// The users are scoped to the tenant they belong to, whatever the tenant of the request
+	ctx = util.NewTenantID(ctx, claims.TenantID)
+	if err := a.TenantBIZ.CheckEnabled(ctx, claims.TenantID); err != nil {
+		return illegalUserID, err
+	}
+
 	userCacheVal, ok, err := a.Cache.Get(ctx, config.CacheNSForUser, fmt.Sprintf("%d", userID))
 	if err != nil {
 		return illegalUserID, err
 	} else if ok {
 		userCache := util.ParseUserCache(userCacheVal)
-		c.Request = c.Request.WithContext(util.NewUserCache(ctx, userCache))
-		return userID, nil
+		if userCache.TokenVersion != claims.Version {
+			return illegalUserID, invalidToken
+		} else if err := checkPasswordExpired(c, userCache); err != nil {
+			return illegalUserID, err
+		}
+		return a.withUserCache(ctx, c, userID, userCache)
 	}
 
-	// Check user status, if not activated, force to logout
+	// Check user status and token version, if not activated or changed, force to logout
 	user, err := a.UserDAL.Get(ctx, userID, schema.UserQueryOptions{
-		QueryOptions: util.QueryOptions{SelectFields: []string{"status"}},
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"dept_id", "status", "token_version", "password_changed_at", "password_expired", "created_at", "locale"},
+		},
 	})
 	if err != nil {
 		return illegalUserID, err
-	} else if user == nil || user.Status != schema.UserStatusActivated {
+	} else if user == nil || user.Status != schema.UserStatusActivated || user.TokenVersion != claims.Version {
 		return illegalUserID, invalidToken
 	}
 
-	roleIDs, err := a.UserBIZ.GetRoleIDs(ctx, userID)
+	roleIDs, rolesUntil, err := a.UserBIZ.GetRoleIDsUntil(ctx, userID)
 	if err != nil {
 		return illegalUserID, err
 	}
 
 	userCache := util.UserCache{
-		RoleIDs: roleIDs,
+		RoleIDs:         roleIDs,
+		TokenVersion:    user.TokenVersion,
+		PasswordExpired: user.IsPasswordExpired(),
+		DeptID:          user.DeptID,
+		Locale:          user.Locale,
 	}
-	err = a.Cache.Set(ctx, config.CacheNSForUser, fmt.Sprintf("%d", userID), userCache.String())
+	err = a.Cache.Set(ctx, config.CacheNSForUser, fmt.Sprintf("%d", userID), userCache.String(),
+		userCacheExpiration(rolesUntil))
 	if err != nil {
 		return illegalUserID, err
+	} else if err := checkPasswordExpired(c, userCache); err != nil {
+		return illegalUserID, err
+	}
+	return a.withUserCache(ctx, c, userID, userCache)
+}
+
+// userCacheExpiration The cached roles are reloaded as soon as an assignment starts or expires (rolesUntil), without
+// waiting for the sweeper.
+func userCacheExpiration(rolesUntil time.Time) time.Duration {
+	expiration := time.Duration(config.C.Dictionary.UserCacheExp) * time.Hour
+	if rolesUntil.IsZero() {
+		return expiration
+	}
+	if until := time.Until(rolesUntil); until < expiration {
+		expiration = until
+	}
+	if expiration < time.Millisecond {
+		expiration = time.Millisecond
+	}
+	return expiration
+}
+
+// withUserCache Attach the user cache and the data scope of the roles to the request.
+func (a *Login) withUserCache(ctx context.Context, c *gin.Context, userID int64, userCache util.UserCache) (int64, error) {
+	dataScope, err := a.DataScopeBIZ.Resolve(ctx, userID, userCache.DeptID, userCache.RoleIDs)
+	if err != nil {
+		return illegalUserID, err
+	} else if dataScope != nil {
+		ctx = util.NewDataScope(ctx, dataScope)
 	}
 
 	c.Request = c.Request.WithContext(util.NewUserCache(ctx, userCache))
 	return userID, nil
 }
 
+// checkPasswordExpired Only allow changing the password if it has expired.
+func checkPasswordExpired(c *gin.Context, userCache util.UserCache) error {
+	if !userCache.PasswordExpired {
+		return nil
+	}
+	if _, ok := expiredPasswordRequests[c.Request.Method+" "+c.Request.URL.Path]; ok {
+		return nil
+	}
+	return errors.Forbidden(config.ErrPasswordExpiredID, "Password has expired, please change the password")
+}
+
+// parseAPIKey Authenticate the request with an API key, it acts as its owner restricted to the roles of the key.
+func (a *Login) parseAPIKey(ctx context.Context, c *gin.Context, key string) (int64, error) {
+	invalidKey := errors.Unauthorized(config.ErrInvalidTokenID, "Invalid API key")
+	apiKey, err := a.APIKeyBIZ.Authenticate(ctx, key)
+	if err != nil {
+		return illegalUserID, err
+	} else if apiKey == nil {
+		return illegalUserID, invalidKey
+	}
+	if strings.HasPrefix(c.Request.URL.Path, "/api/v1/current/") {
+		if _, ok := apiKeyCurrentRequests[c.Request.Method+" "+c.Request.URL.Path]; !ok {
+			return illegalUserID, errors.Forbidden("", "API keys are not allowed to manage the current user")
+		}
+	}
+
+	user, err := a.UserDAL.Get(ctx, apiKey.UserID, schema.UserQueryOptions{
+		QueryOptions: util.QueryOptions{SelectFields: []string{"status", "tenant_id", "dept_id", "locale"}},
+	})
+	if err != nil {
+		return illegalUserID, err
+	} else if user == nil || user.Status != schema.UserStatusActivated {
+		return illegalUserID, invalidKey
+	}
+
+	ctx = util.NewTenantID(ctx, user.TenantID)
+	if err := a.TenantBIZ.CheckEnabled(ctx, user.TenantID); err != nil {
+		return illegalUserID, err
+	}
+
+	roleIDs, err := a.APIKeyBIZ.RoleIDs(ctx, apiKey)
+	if err != nil {
+		return illegalUserID, err
+	}
+
+	return a.withUserCache(ctx, c, apiKey.UserID, util.UserCache{RoleIDs: roleIDs, DeptID: user.DeptID, Locale: user.Locale})
+}
+
 // GetCaptcha
 // This function generates a new captcha ID and returns it as a `schema.Captcha` struct. The length of
 // the captcha is determined by the `config.C.Util.Captcha.Length` configuration value.
@@ -133,22 +261,30 @@ func (a *Login) ResponseCaptcha(ctx context.Context, w http.ResponseWriter, id s
 	return nil
 }
 
-func (a *Login) genUserToken(ctx context.Context, userID int64) (*schema.LoginToken, error) {
-	token, err := a.Auth.GenerateToken(ctx, fmt.Sprintf("%d", userID))
+func (a *Login) genUserToken(ctx context.Context, userID, tokenVersion, tenantID int64) (*schema.LoginToken, error) {
+	token, err := a.Auth.GenerateToken(ctx, fmt.Sprintf("%d", userID),
+		jwtx.WithVersion(tokenVersion), jwtx.WithTenantID(tenantID))
 	if err != nil {
 		return nil, err
 	}
 
-	tokenBuf, err := token.EncodeToJSON()
-	if err != nil {
+	if err := a.SessionBIZ.Create(ctx, userID, token); err != nil {
 		return nil, err
 	}
-	logging.Context(ctx).Info("Generate user token", zap.Any("token", string(tokenBuf)))
+	return a.toLoginToken(ctx, token)
+}
+
+func (a *Login) toLoginToken(ctx context.Context, token jwtx.TokenInfo) (*schema.LoginToken, error) {
+	// The tokens are credentials, only their IDs are logged
+	logging.Context(ctx).Info("Generate user token",
+		zap.String("token_id", token.GetTokenID()), zap.String("family_id", token.GetFamilyID()))
 
 	return &schema.LoginToken{
-		AccessToken: token.GetAccessToken(),
-		TokenType:   token.GetTokenType(),
-		ExpiresAt:   token.GetExpiresAt(),
+		AccessToken:      token.GetAccessToken(),
+		TokenType:        token.GetTokenType(),
+		ExpiresAt:        token.GetExpiresAt(),
+		RefreshToken:     token.GetRefreshToken(),
+		RefreshExpiresAt: token.GetRefreshExpiresAt(),
 	}, nil
 }
 
@@ -160,80 +296,210 @@ func (a *Login) Login(ctx context.Context, formItem *schema.LoginForm) (*schema.
 
 	ctx = logging.NewTag(ctx, logging.TagKeyLogin)
 
+	// reject locked usernames and client IPs before checking the password
+	clientIP := util.FromClientIP(ctx)
+	if err := a.LockoutBIZ.Check(ctx, formItem.Username, clientIP); err != nil {
+		return nil, err
+	}
+
 	// login by root
 	if formItem.Username == config.C.General.Root.Username {
-		if formItem.Password != config.C.General.Root.Password {
-			return nil, errors.BadRequest(config.ErrInvalidUsernameOrPassword, "Incorrect username or password")
+		if subtle.ConstantTimeCompare([]byte(formItem.Password), []byte(config.C.General.Root.Password)) != 1 {
+			return nil, a.loginFailed(ctx, formItem.Username, clientIP)
 		}
 
 		userID := config.C.General.Root.ID
 		ctx = logging.NewUserID(ctx, userID)
 		logging.Context(ctx).Info("Login by root")
-		return a.genUserToken(ctx, userID)
+		if err := a.LockoutBIZ.Reset(ctx, formItem.Username); err != nil {
+			logging.Context(ctx).Error("Failed to reset login attempts", zap.Error(err))
+		}
+		return a.genUserToken(ctx, userID, 0, 0)
 	}
 
-	// get user info
-	user, err := a.UserDAL.GetByUsername(ctx, formItem.Username, schema.UserQueryOptions{
-		QueryOptions: util.QueryOptions{
-			SelectFields: []string{"id", "password", "status"},
-		},
-	})
-	if err != nil {
-		return nil, err
-	} else if user == nil {
-		return nil, errors.BadRequest(config.ErrInvalidUsernameOrPassword, "Incorrect username or password")
+	ctx = withRequestTenant(ctx)
+
+	// check password by the authenticators in order
+	var user *schema.User
+	for _, authenticator := range a.authenticators() {
+		var err error
+		if user, err = authenticator.Authenticate(ctx, formItem.Username, formItem.Password); err != nil {
+			return nil, err
+		} else if user != nil {
+			break
+		}
+	}
+	if user == nil {
+		return nil, a.loginFailed(ctx, formItem.Username, clientIP)
 	} else if user.Status != schema.UserStatusActivated {
 		return nil, errors.BadRequest("", "User status is not activated, please contact the administrator")
 	}
 
-	// check password
-	if err := hash.CompareHashAndPassword(user.Password, formItem.Password); err != nil {
-		return nil, errors.BadRequest(config.ErrInvalidUsernameOrPassword, "Incorrect username or password")
+	ctx = logging.NewUserID(ctx, user.ID)
+
+	roleIDs, rolesUntil, err := a.UserBIZ.GetRoleIDsUntil(ctx, user.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	userID := user.ID
-	ctx = logging.NewUserID(ctx, userID)
+	// the access token is issued after the second factor is verified
+	if required, err := a.TwoFactorBIZ.IsRequired(ctx, user, roleIDs); err != nil {
+		return nil, err
+	} else if required {
+		logging.Context(ctx).Info("Login requires two-factor authentication", zap.String("username", formItem.Username))
+		return a.TwoFactorBIZ.CreateChallenge(ctx, user)
+	}
+
+	logging.Context(ctx).Info("Login success", zap.String("username", formItem.Username))
+	return a.completeLogin(ctx, user, roleIDs, rolesUntil)
+}
+
+// authenticators Local passwords are checked first, then the directory if enabled.
+func (a *Login) authenticators() []Authenticator {
+	authenticators := []Authenticator{a.LocalAuthBIZ}
+	if config.C.Util.LDAP.Enable {
+		authenticators = append(authenticators, a.LDAPAuthBIZ)
+	}
+	return authenticators
+}
+
+// loginFailed Count the failed attempt for the lockout and return the error of incorrect credentials.
+func (a *Login) loginFailed(ctx context.Context, username, clientIP string) error {
+	if err := a.LockoutBIZ.Fail(ctx, username, clientIP); err != nil {
+		logging.Context(ctx).Error("Failed to record failed login attempt", zap.Error(err))
+	}
+	return errors.BadRequest(config.ErrInvalidUsernameOrPassword, "Incorrect username or password")
+}
 
-	// set user cache with role ids
-	roleIDs, err := a.UserBIZ.GetRoleIDs(ctx, userID)
+// LoginTwoFactor Exchange the challenge token of login and a TOTP or recovery code for the access token.
+func (a *Login) LoginTwoFactor(ctx context.Context, formItem *schema.TwoFactorChallengeForm) (*schema.LoginToken, error) {
+	if formItem.Code == "" {
+		return nil, errors.BadRequest(config.ErrInvalidTwoFactorCode, "Two-factor code is required")
+	}
+
+	ctx = logging.NewTag(ctx, logging.TagKeyLogin)
+	user, recoveryCodes, err := a.TwoFactorBIZ.VerifyChallenge(ctx, formItem)
 	if err != nil {
 		return nil, err
 	}
+	ctx = logging.NewUserID(ctx, user.ID)
 
-	userCache := util.UserCache{RoleIDs: roleIDs}
-	err = a.Cache.Set(ctx, config.CacheNSForUser, fmt.Sprintf("%d", userID), userCache.String(),
-		time.Duration(config.C.Dictionary.UserCacheExp)*time.Hour)
+	roleIDs, rolesUntil, err := a.UserBIZ.GetRoleIDsUntil(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	logging.Context(ctx).Info("Login success with two-factor", zap.String("username", user.Username))
+
+	loginToken, err := a.completeLogin(ctx, user, roleIDs, rolesUntil)
+	if err != nil {
+		return nil, err
+	}
+	loginToken.RecoveryCodes = recoveryCodes
+	return loginToken, nil
+}
+
+// SetupLoginTwoFactor Start enrolling two-factor with the challenge token of login, for users whose roles require
+// two-factor but have not enabled it yet.
+func (a *Login) SetupLoginTwoFactor(ctx context.Context, formItem *schema.TwoFactorChallengeForm) (*schema.TwoFactorSetup, error) {
+	return a.TwoFactorBIZ.SetupChallenge(ctx, formItem.ChallengeToken)
+}
+
+// completeLogin Set user cache with role ids and generate token
+func (a *Login) completeLogin(ctx context.Context, user *schema.User, roleIDs []int64, rolesUntil time.Time) (*schema.LoginToken, error) {
+	if err := a.LockoutBIZ.Reset(ctx, user.Username); err != nil {
+		logging.Context(ctx).Error("Failed to reset login attempts", zap.Error(err))
+	}
+
+	userCache := util.UserCache{
+		RoleIDs:         roleIDs,
+		TokenVersion:    user.TokenVersion,
+		PasswordExpired: user.IsPasswordExpired(),
+		DeptID:          user.DeptID,
+		Locale:          user.Locale,
+	}
+	err := a.Cache.Set(ctx, config.CacheNSForUser, fmt.Sprintf("%d", user.ID), userCache.String(),
+		userCacheExpiration(rolesUntil))
 	if err != nil {
 		logging.Context(ctx).Error("Failed to set cache", zap.Error(err))
 	}
-	logging.Context(ctx).Info("Login success", zap.String("username", formItem.Username))
 
-	// generate token
-	return a.genUserToken(ctx, userID)
+	loginToken, err := a.genUserToken(ctx, user.ID, user.TokenVersion, user.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if userCache.PasswordExpired {
+		logging.Context(ctx).Info("Login with expired password", zap.String("username", user.Username))
+		loginToken.PasswordExpired = true
+	}
+	return loginToken, nil
 }
 
-func (a *Login) RefreshToken(ctx context.Context) (*schema.LoginToken, error) {
-	userID := util.FromUserID(ctx)
+// RefreshToken Exchange a refresh token for a new token pair, the presented refresh token is rotated and
+// reusing it afterwards revokes every token issued from the same login.
+func (a *Login) RefreshToken(ctx context.Context, formItem *schema.RefreshTokenForm) (*schema.LoginToken, error) {
+	invalidToken := errors.Unauthorized(config.ErrInvalidTokenID, "Invalid refresh token")
+	claims, token, err := a.Auth.RefreshToken(ctx, formItem.RefreshToken)
+	if err != nil {
+		if errors.Is(err, jwtx.ErrRefreshTokenReused) {
+			logging.Context(logging.NewTag(ctx, logging.TagKeyLogin)).Warn("Refresh token reused, token family revoked",
+				zap.String("user_id", claims.Subject))
+			if userID, err := strconv.ParseInt(claims.Subject, 10, 64); err == nil {
+				if err := a.SessionBIZ.Remove(ctx, userID, claims.FamilyID); err != nil {
+					logging.Context(ctx).Error("Failed to remove session", zap.Error(err))
+				}
+			}
+			return nil, invalidToken
+		} else if errors.Is(err, jwtx.ErrInvalidToken) {
+			return nil, invalidToken
+		}
+		return nil, err
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	ctx = logging.NewUserID(ctx, userID)
+	if userID == config.C.General.Root.ID {
+		if err := a.SessionBIZ.Refresh(ctx, userID, token); err != nil {
+			return nil, err
+		}
+		return a.toLoginToken(ctx, token)
+	}
 
+	ctx = util.NewTenantID(ctx, claims.TenantID)
 	user, err := a.UserDAL.Get(ctx, userID, schema.UserQueryOptions{
 		QueryOptions: util.QueryOptions{
-			SelectFields: []string{"status"},
+			SelectFields: []string{"status", "token_version"},
 		},
 	})
 	if err != nil {
 		return nil, err
-	} else if user == nil {
-		return nil, errors.BadRequest("", "Incorrect user")
-	} else if user.Status != schema.UserStatusActivated {
+	} else if user == nil || user.Status != schema.UserStatusActivated || user.TokenVersion != claims.Version {
+		// Drop the rotated pair so the refresh token can not be used again
+		if err := a.Auth.DestroyToken(ctx, token.GetAccessToken()); err != nil {
+			logging.Context(ctx).Error("Failed to destroy token", zap.Error(err))
+		}
+		if err := a.SessionBIZ.Remove(ctx, userID, claims.FamilyID); err != nil {
+			logging.Context(ctx).Error("Failed to remove session", zap.Error(err))
+		}
+		if user == nil {
+			return nil, errors.BadRequest("", "Incorrect user")
+		} else if user.TokenVersion != claims.Version {
+			return nil, invalidToken
+		}
 		return nil, errors.BadRequest("", "User status is not activated, please contact the administrator")
 	}
 
-	return a.genUserToken(ctx, userID)
+	if err := a.SessionBIZ.Refresh(ctx, userID, token); err != nil {
+		return nil, err
+	}
+	return a.toLoginToken(ctx, token)
 }
 
 func (a *Login) Logout(ctx context.Context) error {
 	userToken := util.FromUserToken(ctx)
-	if userToken == "" {
+	if userToken == "" || util.IsAPIKey(userToken) {
 		return nil
 	}
 
@@ -243,6 +509,10 @@ func (a *Login) Logout(ctx context.Context) error {
 	}
 
 	userID := util.FromUserID(ctx)
+	if err := a.SessionBIZ.Remove(ctx, userID, util.FromSessionID(ctx)); err != nil {
+		logging.Context(ctx).Error("Failed to remove session", zap.Error(err))
+	}
+
 	err := a.Cache.Delete(ctx, config.CacheNSForUser, fmt.Sprintf("%d", userID))
 	if err != nil {
 		logging.Context(ctx).Error("Failed to delete user cache", zap.Error(err))
@@ -277,6 +547,7 @@ func (a *Login) GetUserInfo(ctx context.Context) (*schema.User, error) {
 
 	userRoleResult, err := a.UserRoleDAL.Query(ctx, schema.UserRoleQueryParam{
 		UserID: userID,
+		Active: true,
 	}, schema.UserRoleQueryOptions{
 		JoinRole: true,
 	})
@@ -311,12 +582,29 @@ func (a *Login) UpdatePassword(ctx context.Context, updateItem *schema.UpdateLog
 		return errors.BadRequest("", "Incorrect old password")
 	}
 
+	// check password policy and history
+	if err := a.PasswordBIZ.Check(ctx, "new_password", userID, user.Password, updateItem.NewPassword); err != nil {
+		return err
+	}
+
 	// update password
 	newPassword, err := hash.GeneratePassword(updateItem.NewPassword)
 	if err != nil {
 		return err
 	}
-	return a.UserDAL.UpdatePasswordByID(ctx, userID, newPassword)
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
+		if err := a.UserDAL.UpdatePasswordByID(ctx, userID, newPassword, false); err != nil {
+			return err
+		}
+		if err := a.PasswordBIZ.Record(ctx, userID, newPassword); err != nil {
+			return err
+		}
+		return a.UserBIZ.RevokeTokens(ctx, userID)
+	})
+	if err != nil {
+		return err
+	}
+	return a.UserBIZ.InvalidateTokens(ctx, userID)
 }
 
 // QueryMenus Query menus based on user permissions
@@ -327,7 +615,18 @@ func (a *Login) QueryMenus(ctx context.Context) (schema.Menus, error) {
 
 	isRoot := util.FromIsRootUser(ctx)
 	if !isRoot {
-		menuQueryParams.UserID = util.FromUserID(ctx)
+		// The menus of the roles inherited from the enabled ancestors are included
+		roleIDs, err := a.UserBIZ.GetRoleIDs(ctx, util.FromUserID(ctx))
+		if err != nil {
+			return nil, err
+		} else if len(roleIDs) == 0 {
+			return schema.Menus{}, nil
+		}
+		ancestorIDs, err := a.RoleParentDAL.QueryAncestorIDs(ctx, roleIDs, schema.RoleStatusEnabled)
+		if err != nil {
+			return nil, err
+		}
+		menuQueryParams.InRoleIDs = append(roleIDs, ancestorIDs...)
 	}
 	menuResult, err := a.MenuDAL.Query(ctx, menuQueryParams, schema.MenuQueryOptions{
 		QueryOptions: util.QueryOptions{
@@ -337,6 +636,9 @@ func (a *Login) QueryMenus(ctx context.Context) (schema.Menus, error) {
 	if err != nil {
 		return nil, err
 	} else if isRoot {
+		if err := a.MenuBIZ.Localize(ctx, menuResult.Data); err != nil {
+			return nil, err
+		}
 		return menuResult.Data.ToTree(), nil
 	}
 
@@ -361,6 +663,9 @@ func (a *Login) QueryMenus(ctx context.Context) (schema.Menus, error) {
 		}
 	}
 
+	if err := a.MenuBIZ.Localize(ctx, menuResult.Data); err != nil {
+		return nil, err
+	}
 	return menuResult.Data.ToTree(), nil
 }
 
@@ -382,5 +687,16 @@ func (a *Login) UpdateUser(ctx context.Context, updateItem *schema.UpdateCurrent
 	user.Phone = updateItem.Phone
 	user.Email = updateItem.Email
 	user.Remark = updateItem.Remark
-	return a.UserDAL.Update(ctx, user, "name", "phone", "email", "remark")
+	fields := []string{"name", "phone", "email", "remark"}
+	if updateItem.Locale != nil {
+		user.Locale = *updateItem.Locale
+		fields = append(fields, "locale")
+	}
+	if err := a.UserDAL.Update(ctx, user, fields...); err != nil {
+		return err
+	} else if updateItem.Locale == nil {
+		return nil
+	}
+	// The locale is cached with the roles of the user
+	return a.UserBIZ.EventBIZ.UserChanged(ctx, userID)
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,7 +15,6 @@ import (
 	"github.com/supermicah/go-framework-admin/internal/config"
 	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
 	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
-	"github.com/supermicah/go-framework-admin/pkg/cachex"
 	"github.com/supermicah/go-framework-admin/pkg/encoding/json"
 	"github.com/supermicah/go-framework-admin/pkg/encoding/yaml"
 	"github.com/supermicah/go-framework-admin/pkg/errors"
@@ -24,39 +24,73 @@ import (
 
 // Menu management for RBAC
 type Menu struct {
-	Cache           cachex.Cacher
 	Trans           *util.Trans
 	MenuDAL         *dal.Menu
 	MenuResourceDAL *dal.MenuResource
+	MenuI18nDAL     *dal.MenuI18n
 	RoleMenuDAL     *dal.RoleMenu
+	EventBIZ        *Event
 }
 
-func (a *Menu) InitFromFile(ctx context.Context, menuFile string) error {
+// readMenuFile Parse the menus of the JSON or YAML file.
+func readMenuFile(menuFile string) (schema.Menus, error) {
 	f, err := os.ReadFile(menuFile)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			logging.Context(ctx).Warn("Menu data file not found, skip init menu data from file", zap.String("file", menuFile))
-			return nil
-		}
-		return err
+		return nil, err
 	}
 
 	var menus schema.Menus
 	if ext := filepath.Ext(menuFile); ext == ".json" {
 		if err := json.Unmarshal(f, &menus); err != nil {
-			return errors.Wrapf(err, "Unmarshal JSON file '%s' failed", menuFile)
+			return nil, errors.Wrapf(err, "Unmarshal JSON file '%s' failed", menuFile)
 		}
 	} else if ext == ".yaml" || ext == ".yml" {
 		if err := yaml.Unmarshal(f, &menus); err != nil {
-			return errors.Wrapf(err, "Unmarshal YAML file '%s' failed", menuFile)
+			return nil, errors.Wrapf(err, "Unmarshal YAML file '%s' failed", menuFile)
 		}
 	} else {
-		return errors.Errorf("Unsupported file type '%s'", ext)
+		return nil, errors.Errorf("Unsupported file type '%s'", ext)
 	}
+	return menus, nil
+}
 
-	return a.Trans.Exec(ctx, func(ctx context.Context) error {
+// InitFromFile Create the missing menus and resources of the file, or reconcile the database with the file according
+// to the menu sync mode.
+func (a *Menu) InitFromFile(ctx context.Context, menuFile string) error {
+	menus, err := readMenuFile(menuFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logging.Context(ctx).Warn("Menu data file not found, skip init menu data from file", zap.String("file", menuFile))
+			return nil
+		}
+		return err
+	}
+
+	switch mode := config.C.General.MenuSyncMode; mode {
+	case schema.MenuSyncModeReconcile, schema.MenuSyncModeDryRun:
+		diff, err := a.reconcile(ctx, menus, mode == schema.MenuSyncModeDryRun, false)
+		if err != nil {
+			return err
+		}
+		logging.Context(ctx).Info("Reconcile menu data from file",
+			zap.String("file", menuFile),
+			zap.Bool("dry_run", diff.DryRun),
+			zap.Int("changes", len(diff.Changes)),
+			zap.String("diff", diff.String()),
+		)
+		if diff.DryRun {
+			return nil
+		}
+		return a.InitLocalesFromFiles(ctx)
+	}
+
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
 		return a.createInBatchByParent(ctx, menus, nil)
 	})
+	if err != nil {
+		return err
+	}
+	return a.InitLocalesFromFiles(ctx)
 }
 
 func (a *Menu) createInBatchByParent(ctx context.Context, items schema.Menus, parent *schema.Menu) error {
@@ -160,6 +194,623 @@ func (a *Menu) createInBatchByParent(ctx context.Context, items schema.Menus, pa
 	return nil
 }
 
+// ReconcileFromFile Compute the diff between the database and the configured menu file, and apply it unless in
+// dry-run mode.
+func (a *Menu) ReconcileFromFile(ctx context.Context, dryRun bool) (*schema.MenuDiff, error) {
+	name := config.C.General.MenuFile
+	if name == "" {
+		return nil, errors.BadRequest("", "Menu file is not configured")
+	}
+
+	menus, err := readMenuFile(filepath.Join(config.C.General.WorkDir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errors.BadRequest("", "Menu file '%s' not found", name)
+		}
+		return nil, err
+	}
+	diff, err := a.reconcile(ctx, menus, dryRun, false)
+	if err != nil {
+		return nil, err
+	} else if !dryRun {
+		if err := a.InitLocalesFromFiles(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return diff, nil
+}
+
+// InitLocalesFromFiles Create or update the translations of the menu names of the locale files, matched with the
+// menus by code path. The menus missing from a locale file keep their translations.
+func (a *Menu) InitLocalesFromFiles(ctx context.Context) error {
+	if len(config.C.General.MenuLocaleFiles) == 0 {
+		return nil
+	}
+
+	codePaths, err := a.queryCodePaths(ctx)
+	if err != nil {
+		return err
+	}
+	menuIDs := make(map[string]int64, len(codePaths))
+	for id, codePath := range codePaths {
+		menuIDs[codePath] = id
+	}
+
+	for locale, name := range config.C.General.MenuLocaleFiles {
+		fullPath := filepath.Join(config.C.General.WorkDir, name)
+		menus, err := readMenuFile(fullPath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				logging.Context(ctx).Warn("Menu locale file not found, skip init menu translations from file",
+					zap.String("locale", locale), zap.String("file", fullPath))
+				continue
+			}
+			return err
+		}
+
+		names := make(map[int64]string)
+		var collect func(items schema.Menus, parentCodePath string)
+		collect = func(items schema.Menus, parentCodePath string) {
+			for _, item := range items {
+				codePath := item.Code
+				if parentCodePath != "" {
+					codePath = parentCodePath + util.TreePathDelimiter + item.Code
+				}
+				if id, ok := menuIDs[codePath]; ok && item.Name != "" {
+					names[id] = item.Name
+				}
+				if item.Children != nil {
+					collect(*item.Children, codePath)
+				}
+			}
+		}
+		collect(menus, "")
+
+		if err := a.saveLocale(ctx, locale, names); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveLocale Create or update the translations of the menu names of the locale.
+func (a *Menu) saveLocale(ctx context.Context, locale string, names map[int64]string) error {
+	menuIDs := make([]int64, 0, len(names))
+	for id := range names {
+		menuIDs = append(menuIDs, id)
+	}
+	if len(menuIDs) == 0 {
+		return nil
+	}
+
+	i18nResult, err := a.MenuI18nDAL.Query(ctx, schema.MenuI18nQueryParam{
+		MenuIDs: menuIDs,
+		Locale:  locale,
+	})
+	if err != nil {
+		return err
+	}
+	existing := i18nResult.Data.ToMenuIDMap()
+
+	return a.Trans.Exec(ctx, func(ctx context.Context) error {
+		for id, name := range names {
+			if item, ok := existing[id]; ok {
+				if item.Name == name {
+					continue
+				}
+				item.Name = name
+				item.UpdatedAt = time.Now()
+				if err := a.MenuI18nDAL.Update(ctx, item); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := a.MenuI18nDAL.Create(ctx, &schema.MenuI18n{
+				MenuID:    id,
+				Locale:    locale,
+				Name:      name,
+				CreatedAt: time.Now(),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// queryCodePaths Code paths of all the menus by ID.
+func (a *Menu) queryCodePaths(ctx context.Context) (map[int64]string, error) {
+	menuResult, err := a.MenuDAL.Query(ctx, schema.MenuQueryParam{}, schema.MenuQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "code", "parent_path"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make(map[int64]string, len(menuResult.Data))
+	parentPaths := make(map[int64]string, len(menuResult.Data))
+	for _, menu := range menuResult.Data {
+		codes[menu.ID] = menu.Code
+		parentPaths[menu.ID] = menu.ParentPath
+	}
+	return treeCodePaths(codes, parentPaths), nil
+}
+
+// Locale Negotiate the locale of the menu names, from the preference of the current user (cached with the roles)
+// first and from the Accept-Language header of the request then, else the default locale.
+func (a *Menu) Locale(ctx context.Context) string {
+	supported := []string{config.C.General.DefaultLocale}
+	for locale := range config.C.General.MenuLocaleFiles {
+		supported = append(supported, locale)
+	}
+	sort.Strings(supported[1:])
+
+	var preferred []string
+	if locale := util.FromUserCache(ctx).Locale; locale != "" {
+		preferred = append(preferred, locale)
+	}
+	preferred = append(preferred, util.ParseAcceptLanguage(util.FromAcceptLanguage(ctx))...)
+
+	if locale := util.MatchLocale(preferred, supported); locale != "" {
+		return locale
+	}
+	return config.C.General.DefaultLocale
+}
+
+// Localize Replace the names of the menus (not the children) by their translations in the negotiated locale, the
+// menus without a translation keep the default name.
+func (a *Menu) Localize(ctx context.Context, menus schema.Menus) error {
+	if len(menus) == 0 || len(config.C.General.MenuLocaleFiles) == 0 {
+		return nil
+	}
+
+	locale := a.Locale(ctx)
+	if locale == config.C.General.DefaultLocale {
+		return nil
+	}
+
+	menuIDs := make([]int64, len(menus))
+	for i, menu := range menus {
+		menuIDs[i] = menu.ID
+	}
+	i18nResult, err := a.MenuI18nDAL.Query(ctx, schema.MenuI18nQueryParam{
+		MenuIDs: menuIDs,
+		Locale:  locale,
+	})
+	if err != nil {
+		return err
+	}
+	names := i18nResult.Data.ToMenuIDMap()
+	for _, menu := range menus {
+		if item, ok := names[menu.ID]; ok {
+			menu.Name = item.Name
+		}
+	}
+	return nil
+}
+
+// Upsert Create or update the menus and their resources matched by code path, the other menus are kept.
+func (a *Menu) Upsert(ctx context.Context, menus schema.Menus) (*schema.MenuDiff, error) {
+	return a.reconcile(ctx, menus, false, true)
+}
+
+// reconcile Create, update, move and delete the menus and their resources to match the menus of the file, then sync
+// the policies of the roles granted the changed menus. In upsert mode, the menus are only matched by code path and
+// the unmatched menus are kept.
+func (a *Menu) reconcile(ctx context.Context, menus schema.Menus, dryRun, upsert bool) (*schema.MenuDiff, error) {
+	r := &menuReconciler{
+		Menu:      a,
+		upsert:    upsert,
+		diff:      &schema.MenuDiff{DryRun: dryRun},
+		matched:   make(map[int64]bool),
+		fileCodes: make(map[string]int),
+		roleIDs:   make(map[int64]struct{}),
+	}
+	r.countCodes(menus)
+
+	err := a.Trans.Exec(ctx, func(ctx context.Context) error {
+		if err := r.load(ctx); err != nil {
+			return err
+		}
+		if err := r.reconcile(ctx, menus, nil, "", false); err != nil {
+			return err
+		} else if upsert {
+			return nil
+		}
+		return r.deleteUnmatched(ctx)
+	})
+	if err != nil {
+		return nil, err
+	} else if dryRun || len(r.diff.Changes) == 0 {
+		return r.diff, nil
+	}
+
+	roleIDs := make([]int64, 0, len(r.roleIDs))
+	for id := range r.roleIDs {
+		roleIDs = append(roleIDs, id)
+	}
+	if err := a.EventBIZ.MenuChanged(ctx, r.menuIDs, roleIDs); err != nil {
+		return nil, err
+	}
+	return r.diff, nil
+}
+
+// menuReconciler State of the reconciliation of the database with the menus of a file. A menu of the file matches
+// the menu of the database with the same ID, else the one with the same code (or name) under the same parent, else
+// the one with the same code if the code is unique in both, which is then moved.
+type menuReconciler struct {
+	*Menu
+	upsert    bool
+	diff      *schema.MenuDiff
+	menus     map[int64]*schema.Menu
+	children  map[int64]schema.Menus
+	resources map[int64]schema.MenuResources
+	codes     map[string]int
+	fileCodes map[string]int
+	matched   map[int64]bool
+	menuIDs   []int64
+	roleIDs   map[int64]struct{}
+}
+
+func (r *menuReconciler) countCodes(items schema.Menus) {
+	for _, item := range items {
+		if item.Code != "" {
+			r.fileCodes[item.Code]++
+		}
+		if item.Children != nil {
+			r.countCodes(*item.Children)
+		}
+	}
+}
+
+func (r *menuReconciler) load(ctx context.Context) error {
+	menuResult, err := r.MenuDAL.Query(ctx, schema.MenuQueryParam{}, schema.MenuQueryOptions{
+		QueryOptions: util.QueryOptions{
+			OrderFields: schema.MenusOrderParams,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	r.menus = make(map[int64]*schema.Menu, len(menuResult.Data))
+	r.children = make(map[int64]schema.Menus)
+	r.codes = make(map[string]int)
+	for _, item := range menuResult.Data {
+		r.menus[item.ID] = item
+		r.children[item.ParentID] = append(r.children[item.ParentID], item)
+		r.codes[item.Code]++
+	}
+
+	resResult, err := r.MenuResourceDAL.Query(ctx, schema.MenuResourceQueryParam{})
+	if err != nil {
+		return err
+	}
+	r.resources = make(map[int64]schema.MenuResources)
+	for _, res := range resResult.Data {
+		r.resources[res.MenuID] = append(r.resources[res.MenuID], res)
+	}
+	return nil
+}
+
+func (r *menuReconciler) match(item *schema.Menu, parentID int64, newParent bool) *schema.Menu {
+	if item.ID > 0 {
+		if menu, ok := r.menus[item.ID]; ok && !r.matched[menu.ID] {
+			return menu
+		}
+		return nil
+	}
+
+	if !newParent {
+		for _, menu := range r.children[parentID] {
+			if r.matched[menu.ID] {
+				continue
+			} else if item.Code != "" && menu.Code == item.Code {
+				return menu
+			} else if item.Code == "" && item.Name != "" && menu.Name == item.Name {
+				return menu
+			}
+		}
+	}
+
+	if !r.upsert && item.Code != "" && r.fileCodes[item.Code] == 1 && r.codes[item.Code] == 1 {
+		for _, menu := range r.menus {
+			if menu.Code == item.Code && !r.matched[menu.ID] {
+				return menu
+			}
+		}
+	}
+	return nil
+}
+
+// codePath Code path of the menu of the database from its parents.
+func (r *menuReconciler) codePath(menu *schema.Menu) string {
+	var codes []string
+	for _, pid := range strings.Split(menu.ParentPath, util.TreePathDelimiter) {
+		if parentID, err := strconv.ParseInt(pid, 10, 64); err == nil {
+			if parent, ok := r.menus[parentID]; ok {
+				codes = append(codes, parent.Code)
+			}
+		}
+	}
+	return strings.Join(append(codes, menu.Code), util.TreePathDelimiter)
+}
+
+// addRoles Collect the roles granted the menu or its children to sync their policies.
+func (r *menuReconciler) addRoles(ctx context.Context, menu *schema.Menu) error {
+	if r.diff.DryRun {
+		return nil
+	}
+	for _, id := range r.menuIDs {
+		if id == menu.ID {
+			return nil
+		}
+	}
+	r.menuIDs = append(r.menuIDs, menu.ID)
+
+	roleIDs, err := r.queryTreeRoleIDs(ctx, menu)
+	if err != nil {
+		return err
+	}
+	for _, id := range roleIDs {
+		r.roleIDs[id] = struct{}{}
+	}
+	return nil
+}
+
+func (r *menuReconciler) reconcile(ctx context.Context, items schema.Menus, parent *schema.Menu, parentCodePath string, newParent bool) error {
+	var (
+		parentID   int64
+		parentPath string
+	)
+	if parent != nil {
+		parentID = parent.ID
+		parentPath = fmt.Sprintf("%s%d%s", parent.ParentPath, parent.ID, util.TreePathDelimiter)
+	}
+
+	total := len(items)
+	for i, item := range items {
+		if item.Status == "" {
+			item.Status = schema.MenuStatusEnabled
+		}
+		if item.Sequence == 0 {
+			item.Sequence = total - i
+		}
+		for _, res := range item.Resources {
+			if res.Effect == "" {
+				res.Effect = schema.ResourceEffectAllow
+			}
+		}
+
+		code := item.Code
+		if code == "" {
+			code = item.Name
+		}
+		codePath := code
+		if parentCodePath != "" {
+			codePath = parentCodePath + util.TreePathDelimiter + code
+		}
+
+		menu := r.match(item, parentID, newParent)
+		if menu == nil {
+			if err := r.create(ctx, item, parentID, parentPath, codePath); err != nil {
+				return err
+			}
+			if item.Children != nil {
+				if err := r.reconcile(ctx, *item.Children, item, codePath, true); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		r.matched[menu.ID] = true
+
+		if err := r.update(ctx, menu, item, parentID, parentPath, codePath, newParent); err != nil {
+			return err
+		}
+		if err := r.reconcileResources(ctx, menu, item.Resources, codePath); err != nil {
+			return err
+		}
+		if item.Children != nil {
+			if err := r.reconcile(ctx, *item.Children, menu, codePath, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *menuReconciler) create(ctx context.Context, item *schema.Menu, parentID int64, parentPath, codePath string) error {
+	r.diff.Add(schema.MenuChangeCreate, item, codePath)
+	for _, res := range item.Resources {
+		r.diff.AddResource(schema.MenuChangeCreate, item, codePath, res)
+	}
+	if r.diff.DryRun {
+		return nil
+	}
+
+	item.ParentID = parentID
+	item.ParentPath = parentPath
+	item.CreatedAt = time.Now()
+	if err := r.MenuDAL.Create(ctx, item); err != nil {
+		return err
+	}
+	for _, res := range item.Resources {
+		res.MenuID = item.ID
+		res.CreatedAt = time.Now()
+		if err := r.MenuResourceDAL.Create(ctx, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *menuReconciler) update(ctx context.Context, menu, item *schema.Menu, parentID int64, parentPath, codePath string, newParent bool) error {
+	var fields []string
+	if menu.Code != item.Code {
+		fields = append(fields, "code")
+	}
+	if menu.Name != item.Name {
+		fields = append(fields, "name")
+	}
+	if menu.Description != item.Description {
+		fields = append(fields, "description")
+	}
+	if menu.Sequence != item.Sequence {
+		fields = append(fields, "sequence")
+	}
+	if menu.Type != item.Type {
+		fields = append(fields, "type")
+	}
+	if menu.Path != item.Path {
+		fields = append(fields, "path")
+	}
+	if menu.Properties != item.Properties {
+		fields = append(fields, "properties")
+	}
+	if menu.Status != item.Status {
+		fields = append(fields, "status")
+		if err := r.addRoles(ctx, menu); err != nil {
+			return err
+		}
+	}
+	if len(fields) > 0 {
+		change := r.diff.Add(schema.MenuChangeUpdate, menu, codePath)
+		change.Fields = fields
+	}
+
+	moved := newParent || menu.ParentID != parentID
+	if moved {
+		change := r.diff.Add(schema.MenuChangeMove, menu, codePath)
+		if parent, ok := r.menus[menu.ParentID]; ok {
+			change.From = r.codePath(parent)
+		}
+		// The roles granted the menu are granted the resources of its new parents
+		if err := r.addRoles(ctx, menu); err != nil {
+			return err
+		}
+	}
+	if r.diff.DryRun || (len(fields) == 0 && !moved) {
+		return nil
+	}
+
+	if moved {
+		oldPath := fmt.Sprintf("%s%d%s", menu.ParentPath, menu.ID, util.TreePathDelimiter)
+		newPath := fmt.Sprintf("%s%d%s", parentPath, menu.ID, util.TreePathDelimiter)
+		for _, child := range r.menus {
+			if !strings.HasPrefix(child.ParentPath, oldPath) {
+				continue
+			}
+			child.ParentPath = strings.Replace(child.ParentPath, oldPath, newPath, 1)
+			if err := r.MenuDAL.UpdateParentPath(ctx, child.ID, child.ParentPath); err != nil {
+				return err
+			}
+		}
+		menu.ParentID = parentID
+		menu.ParentPath = parentPath
+	}
+
+	menu.Code = item.Code
+	menu.Name = item.Name
+	menu.Description = item.Description
+	menu.Sequence = item.Sequence
+	menu.Type = item.Type
+	menu.Path = item.Path
+	menu.Properties = item.Properties
+	menu.Status = item.Status
+	menu.UpdatedAt = time.Now()
+	return r.MenuDAL.Update(ctx, menu)
+}
+
+func (r *menuReconciler) reconcileResources(ctx context.Context, menu *schema.Menu, items schema.MenuResources, codePath string) error {
+	existing := make(map[string]*schema.MenuResource)
+	for _, res := range r.resources[menu.ID] {
+		existing[res.Method+" "+res.Path] = res
+	}
+
+	changed := false
+	for _, item := range items {
+		key := item.Method + " " + item.Path
+		res, ok := existing[key]
+		if ok {
+			delete(existing, key)
+			if res.Effect == item.Effect {
+				continue
+			}
+			changed = true
+			r.diff.AddResource(schema.MenuChangeUpdate, menu, codePath, item)
+			if !r.diff.DryRun {
+				res.Effect = item.Effect
+				res.UpdatedAt = time.Now()
+				if err := r.MenuResourceDAL.Update(ctx, res); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		changed = true
+		r.diff.AddResource(schema.MenuChangeCreate, menu, codePath, item)
+		if !r.diff.DryRun {
+			item.MenuID = menu.ID
+			item.CreatedAt = time.Now()
+			if err := r.MenuResourceDAL.Create(ctx, item); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The resources left are not in the file anymore, in the order of the database
+	for _, res := range r.resources[menu.ID] {
+		if _, ok := existing[res.Method+" "+res.Path]; !ok {
+			continue
+		}
+		changed = true
+		r.diff.AddResource(schema.MenuChangeDelete, menu, codePath, res)
+		if !r.diff.DryRun {
+			if err := r.MenuResourceDAL.Delete(ctx, strconv.FormatInt(res.ID, 10)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if changed {
+		return r.addRoles(ctx, menu)
+	}
+	return nil
+}
+
+// deleteUnmatched Delete the menus of the database missing from the file, unless the deletion of menus is denied.
+func (r *menuReconciler) deleteUnmatched(ctx context.Context) error {
+	var menus schema.Menus
+	for _, menu := range r.menus {
+		if !r.matched[menu.ID] {
+			menus = append(menus, menu)
+		}
+	}
+	sort.Slice(menus, func(i, j int) bool { return menus[i].ID < menus[j].ID })
+
+	deny := config.C.General.DenyDeleteMenu
+	for _, menu := range menus {
+		change := r.diff.Add(schema.MenuChangeDelete, menu, r.codePath(menu))
+		if deny {
+			change.Skipped = true
+			continue
+		} else if r.diff.DryRun {
+			continue
+		}
+
+		if err := r.addRoles(ctx, menu); err != nil {
+			return err
+		}
+		if err := r.delete(ctx, menu.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Query menus from the data access object based on the provided parameters and options.
 func (a *Menu) Query(ctx context.Context, params schema.MenuQueryParam) (*schema.MenuQueryResult, error) {
 	params.Pagination = false
@@ -196,6 +847,9 @@ func (a *Menu) Query(ctx context.Context, params schema.MenuQueryParam) (*schema
 		}
 	}
 
+	if err := a.Localize(ctx, result.Data); err != nil {
+		return nil, err
+	}
 	result.Data = result.Data.ToTree()
 	return result, nil
 }
@@ -359,31 +1013,17 @@ func (a *Menu) Update(ctx context.Context, id int64, formItem *schema.MenuForm)
 
 	oldParentPath := menu.ParentPath
 	oldStatus := menu.Status
-	var childData schema.Menus
-	if menu.ParentID != formItem.ParentID {
-		if parentID := formItem.ParentID; parentID > 0 {
-			parent, err := a.MenuDAL.Get(ctx, parentID)
-			if err != nil {
-				return err
-			} else if parent == nil {
-				return errors.NotFound("", "Parent not found")
-			}
-			menu.ParentPath = fmt.Sprintf("%s%d%s", parent.ParentPath, parent.ID, util.TreePathDelimiter)
-		} else {
-			menu.ParentPath = ""
-		}
+	roleIDs, err := a.queryTreeRoleIDs(ctx, menu)
+	if err != nil {
+		return err
+	}
 
-		childResult, err := a.MenuDAL.Query(ctx, schema.MenuQueryParam{
-			ParentPathPrefix: fmt.Sprintf("%s%d%s", oldParentPath, menu.ID, util.TreePathDelimiter),
-		}, schema.MenuQueryOptions{
-			QueryOptions: util.QueryOptions{
-				SelectFields: []string{"id", "parent_path"},
-			},
-		})
+	if menu.ParentID != formItem.ParentID {
+		parentPath, err := a.movedParentPath(ctx, menu, formItem.ParentID)
 		if err != nil {
 			return err
 		}
-		childData = childResult.Data
+		menu.ParentPath = parentPath
 	}
 
 	if menu.Code != formItem.Code {
@@ -398,7 +1038,7 @@ func (a *Menu) Update(ctx context.Context, id int64, formItem *schema.MenuForm)
 		return err
 	}
 
-	return a.Trans.Exec(ctx, func(ctx context.Context) error {
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
 		if oldStatus != formItem.Status {
 			oldPath := fmt.Sprintf("%s%d%s", oldParentPath, menu.ID, util.TreePathDelimiter)
 			if err := a.MenuDAL.UpdateStatusByParentPath(ctx, oldPath, formItem.Status); err != nil {
@@ -406,11 +1046,10 @@ func (a *Menu) Update(ctx context.Context, id int64, formItem *schema.MenuForm)
 			}
 		}
 
-		for _, child := range childData {
+		if menu.ParentPath != oldParentPath {
 			oldPath := fmt.Sprintf("%s%d%s", oldParentPath, menu.ID, util.TreePathDelimiter)
 			newPath := fmt.Sprintf("%s%d%s", menu.ParentPath, menu.ID, util.TreePathDelimiter)
-			err := a.MenuDAL.UpdateParentPath(ctx, child.ID, strings.Replace(child.ParentPath, oldPath, newPath, 1))
-			if err != nil {
+			if err := a.MenuDAL.ReplaceParentPath(ctx, oldPath, newPath); err != nil {
 				return err
 			}
 		}
@@ -432,8 +1071,134 @@ func (a *Menu) Update(ctx context.Context, id int64, formItem *schema.MenuForm)
 				return err
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return a.EventBIZ.MenuChanged(ctx, []int64{id}, roleIDs)
+}
+
+// movedParentPath Parent path of the menu moved under the new parent, which cannot be the menu or its children.
+func (a *Menu) movedParentPath(ctx context.Context, menu *schema.Menu, parentID int64) (string, error) {
+	if parentID == 0 {
+		return "", nil
+	}
+
+	parent, err := a.MenuDAL.Get(ctx, parentID)
+	if err != nil {
+		return "", err
+	} else if parent == nil {
+		return "", errors.NotFound("", "Parent not found")
+	}
+	childPath := fmt.Sprintf("%s%d%s", menu.ParentPath, menu.ID, util.TreePathDelimiter)
+	if parent.ID == menu.ID || strings.HasPrefix(parent.ParentPath, childPath) {
+		return "", errors.BadRequest("", "Menu cannot be moved under itself or its children")
+	}
+	return fmt.Sprintf("%s%d%s", parent.ParentPath, parent.ID, util.TreePathDelimiter), nil
+}
+
+// Move the specified menu and its children under a new parent at the position among the new siblings, the sequences
+// of the siblings are renumbered.
+func (a *Menu) Move(ctx context.Context, id int64, formItem *schema.MenuMoveForm) error {
+	menu, err := a.MenuDAL.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if menu == nil {
+		return errors.NotFound("", "Menu not found")
+	}
+
+	oldPath := fmt.Sprintf("%s%d%s", menu.ParentPath, menu.ID, util.TreePathDelimiter)
+	moved := menu.ParentID != formItem.ParentID
+	var roleIDs []int64
+	if moved {
+		parentPath, err := a.movedParentPath(ctx, menu, formItem.ParentID)
+		if err != nil {
+			return err
+		}
+		if exists, err := a.MenuDAL.ExistsCodeByParentID(ctx, menu.Code, formItem.ParentID); err != nil {
+			return err
+		} else if exists {
+			return errors.BadRequest("", "Menu code already exists at the same level")
+		}
 
-		return a.syncToCasbin(ctx)
+		// The roles granted the menu or its children are granted the resources of the ancestors
+		roleIDs, err = a.queryTreeRoleIDs(ctx, menu)
+		if err != nil {
+			return err
+		}
+		menu.ParentID = formItem.ParentID
+		menu.ParentPath = parentPath
+	}
+
+	siblingIDs, err := a.MenuDAL.QueryIDsByParentID(ctx, formItem.ParentID)
+	if err != nil {
+		return err
+	}
+	ids := make([]int64, 0, len(siblingIDs)+1)
+	for _, siblingID := range siblingIDs {
+		if siblingID != id {
+			ids = append(ids, siblingID)
+		}
+	}
+	position := formItem.Position
+	if position > len(ids) {
+		position = len(ids)
+	}
+	ids = append(ids[:position], append([]int64{id}, ids[position:]...)...)
+
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
+		if moved {
+			menu.UpdatedAt = time.Now()
+			if err := a.MenuDAL.Update(ctx, menu); err != nil {
+				return err
+			}
+			newPath := fmt.Sprintf("%s%d%s", menu.ParentPath, menu.ID, util.TreePathDelimiter)
+			if err := a.MenuDAL.ReplaceParentPath(ctx, oldPath, newPath); err != nil {
+				return err
+			}
+		}
+		return a.MenuDAL.UpdateSequences(ctx, ids)
+	})
+	if err != nil {
+		return err
+	} else if !moved {
+		return nil
+	}
+	return a.EventBIZ.MenuChanged(ctx, []int64{id}, roleIDs)
+}
+
+// Reorder the children of a parent, the sequences are renumbered in the order of the IDs which must be all the
+// children of the parent.
+func (a *Menu) Reorder(ctx context.Context, formItem *schema.MenuReorderForm) error {
+	menuResult, err := a.MenuDAL.Query(ctx, schema.MenuQueryParam{
+		InIDs: formItem.IDs,
+	}, schema.MenuQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "parent_id"},
+		},
+	})
+	if err != nil {
+		return err
+	} else if len(menuResult.Data) != len(formItem.IDs) {
+		return errors.BadRequest("", "Menus not found or duplicated")
+	}
+
+	parentID := menuResult.Data[0].ParentID
+	for _, menu := range menuResult.Data {
+		if menu.ParentID != parentID {
+			return errors.BadRequest("", "Menus must have the same parent")
+		}
+	}
+	siblingIDs, err := a.MenuDAL.QueryIDsByParentID(ctx, parentID)
+	if err != nil {
+		return err
+	} else if len(siblingIDs) != len(formItem.IDs) {
+		return errors.BadRequest("", "Menus must include all the children of the parent")
+	}
+
+	return a.Trans.Exec(ctx, func(ctx context.Context) error {
+		return a.MenuDAL.UpdateSequences(ctx, formItem.IDs)
 	})
 }
 
@@ -461,7 +1226,12 @@ func (a *Menu) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 
-	return a.Trans.Exec(ctx, func(ctx context.Context) error {
+	roleIDs, err := a.queryTreeRoleIDs(ctx, menu)
+	if err != nil {
+		return err
+	}
+
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
 		if err := a.delete(ctx, id); err != nil {
 			return err
 		}
@@ -471,9 +1241,12 @@ func (a *Menu) Delete(ctx context.Context, id int64) error {
 				return err
 			}
 		}
-
-		return a.syncToCasbin(ctx)
+		return nil
 	})
+	if err != nil {
+		return err
+	}
+	return a.EventBIZ.MenuChanged(ctx, []int64{id}, roleIDs)
 }
 
 func (a *Menu) delete(ctx context.Context, id int64) error {
@@ -483,12 +1256,49 @@ func (a *Menu) delete(ctx context.Context, id int64) error {
 	if err := a.MenuResourceDAL.DeleteByMenuID(ctx, id); err != nil {
 		return err
 	}
-	if err := a.RoleMenuDAL.DeleteByMenuID(ctx, id); err != nil {
+	if err := a.MenuI18nDAL.DeleteByMenuID(ctx, id); err != nil {
+		return err
+	}
+	// The menus are shared by the tenants
+	if err := a.RoleMenuDAL.DeleteByMenuID(util.NewCrossTenant(ctx), id); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (a *Menu) syncToCasbin(ctx context.Context) error {
-	return a.Cache.Set(ctx, config.CacheNSForRole, config.CacheKeyForSyncToCasbin, fmt.Sprintf("%d", time.Now().Unix()))
+// queryTreeRoleIDs Query the roles of all the tenants granted the menu or its children, they are also granted the
+// resources of the menu.
+func (a *Menu) queryTreeRoleIDs(ctx context.Context, menu *schema.Menu) ([]int64, error) {
+	ctx = util.NewCrossTenant(ctx)
+	childResult, err := a.MenuDAL.Query(ctx, schema.MenuQueryParam{
+		ParentPathPrefix: fmt.Sprintf("%s%d%s", menu.ParentPath, menu.ID, util.TreePathDelimiter),
+	}, schema.MenuQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	menuIDs := []int64{menu.ID}
+	for _, child := range childResult.Data {
+		menuIDs = append(menuIDs, child.ID)
+	}
+	roleMenuResult, err := a.RoleMenuDAL.Query(ctx, schema.RoleMenuQueryParam{
+		InMenuIDs: menuIDs,
+	}, schema.RoleMenuQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"role_id"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roleIDs := make([]int64, 0, len(roleMenuResult.Data))
+	for _, item := range roleMenuResult.Data {
+		roleIDs = append(roleIDs, item.RoleID)
+	}
+	return roleIDs, nil
 }
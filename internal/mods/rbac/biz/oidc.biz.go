@@ -0,0 +1,231 @@
+package biz
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/cachex"
+	"github.com/supermicah/go-framework-admin/pkg/encoding/json"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/logging"
+	"github.com/supermicah/go-framework-admin/pkg/oidc"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// oidcLoginState Login request waiting for the redirect back from the provider, stored in the cache by the state.
+type oidcLoginState struct {
+	Provider string `json:"p"`
+	Verifier string `json:"v"`
+	Nonce    string `json:"n"`
+	TenantID int64  `json:"t,omitempty"` // Tenant of the login request, the callback is not sent with the tenant header
+}
+
+type oidcProvider struct {
+	key      string
+	provider *oidc.Provider
+}
+
+// OIDC Single sign-on with OpenID Connect providers
+type OIDC struct {
+	Cache       cachex.Cacher
+	UserBIZ     *User
+	LoginBIZ    *Login
+	IdentityBIZ *Identity
+
+	mu        sync.Mutex               `wire:"-"`
+	providers map[string]*oidcProvider `wire:"-"`
+}
+
+func getOIDCProviderConfig(name string) (config.OIDCProvider, bool) {
+	for _, item := range config.C.Util.OIDC.Providers {
+		if item.Name == name {
+			return item, true
+		}
+	}
+	return config.OIDCProvider{}, false
+}
+
+// getProvider Get the discovered provider, the discovery is repeated when the configuration changes.
+func (a *OIDC) getProvider(ctx context.Context, cfg config.OIDCProvider) (*oidc.Provider, error) {
+	key := strings.Join([]string{cfg.Issuer, cfg.ClientID, cfg.ClientSecret, cfg.RedirectURL,
+		strings.Join(cfg.Scopes, " ")}, "\n")
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if item, ok := a.providers[cfg.Name]; ok && item.key == key {
+		return item.provider, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, oidc.Config{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Scopes:       cfg.Scopes,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to discover oidc provider %s", cfg.Name)
+	}
+
+	if a.providers == nil {
+		a.providers = make(map[string]*oidcProvider)
+	}
+	a.providers[cfg.Name] = &oidcProvider{key: key, provider: provider}
+	return provider, nil
+}
+
+// AuthURL Start the login with the provider, returns the URL of the provider to redirect to and the state which the
+// callback must present.
+func (a *OIDC) AuthURL(ctx context.Context, name string) (string, string, error) {
+	cfg, ok := getOIDCProviderConfig(name)
+	if !ok {
+		return "", "", errors.NotFound("", "Single sign-on provider not found")
+	}
+
+	provider, err := a.getProvider(ctx, cfg)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, verifier, nonce := oauth2.GenerateVerifier(), oauth2.GenerateVerifier(), oauth2.GenerateVerifier()
+
+	tenantID, _ := util.FromTenantID(ctx)
+	loginState := &oidcLoginState{Provider: name, Verifier: verifier, Nonce: nonce, TenantID: tenantID}
+	expiration := time.Duration(config.C.Util.OIDC.StateExpired) * time.Second
+	err = a.Cache.Set(ctx, config.CacheNSForOIDC, "state:"+state, json.MarshalToString(loginState), expiration)
+	if err != nil {
+		return "", "", err
+	}
+	return provider.AuthCodeURL(state, nonce, verifier), state, nil
+}
+
+// Callback Complete the login with the code from the provider. The state must be the one issued to the same browser
+// and is consumed, the user is provisioned or linked by the subject and the roles are synchronized as configured.
+func (a *OIDC) Callback(ctx context.Context, name string, formItem *schema.OIDCCallbackForm, browserState string) (*schema.LoginToken, error) {
+	ctx = logging.NewTag(ctx, logging.TagKeyLogin)
+	invalidState := errors.BadRequest(config.ErrInvalidSSOStateID, "Invalid or expired single sign-on state")
+	if formItem.State == "" || formItem.State != browserState {
+		return nil, invalidState
+	}
+
+	val, ok, err := a.Cache.GetAndDelete(ctx, config.CacheNSForOIDC, "state:"+formItem.State)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, invalidState
+	}
+	loginState := new(oidcLoginState)
+	if err := json.Unmarshal([]byte(val), loginState); err != nil || loginState.Provider != name {
+		return nil, invalidState
+	}
+	ctx = util.NewTenantID(ctx, loginState.TenantID)
+
+	cfg, ok := getOIDCProviderConfig(name)
+	if !ok {
+		return nil, errors.NotFound("", "Single sign-on provider not found")
+	}
+
+	loginFailed := errors.BadRequest(config.ErrSSOLoginFailedID, "Single sign-on failed")
+	if formItem.Error != "" {
+		logging.Context(ctx).Info("Single sign-on rejected by provider", zap.String("provider", name),
+			zap.String("error", formItem.Error), zap.String("description", formItem.ErrorDescription))
+		return nil, loginFailed
+	}
+
+	provider, err := a.getProvider(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := provider.Exchange(ctx, formItem.Code, loginState.Verifier)
+	if err != nil {
+		logging.Context(ctx).Warn("Failed to exchange single sign-on code", zap.String("provider", name), zap.Error(err))
+		return nil, loginFailed
+	}
+	claims, err := provider.VerifyIDToken(ctx, token.IDToken, loginState.Nonce)
+	if err != nil {
+		logging.Context(ctx).Warn("Failed to verify single sign-on id token", zap.String("provider", name), zap.Error(err))
+		return nil, loginFailed
+	}
+	if token.AccessToken != "" {
+		userInfo, err := provider.UserInfo(ctx, token.AccessToken)
+		if err != nil {
+			logging.Context(ctx).Warn("Failed to get single sign-on user info", zap.String("provider", name), zap.Error(err))
+			return nil, loginFailed
+		}
+		// The subject of the user info must be the one of the ID token
+		if sub := userInfo.String("sub"); sub != "" && sub != claims.String("sub") {
+			return nil, loginFailed
+		}
+		claims.Merge(userInfo)
+	}
+
+	user, err := a.resolveUser(ctx, cfg, claims)
+	if err != nil {
+		return nil, err
+	} else if user.Status != schema.UserStatusActivated {
+		return nil, errors.BadRequest("", "User status is not activated, please contact the administrator")
+	}
+	ctx = logging.NewUserID(ctx, user.ID)
+
+	roleIDs, rolesUntil, err := a.UserBIZ.GetRoleIDsUntil(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if required, err := a.LoginBIZ.TwoFactorBIZ.IsRequired(ctx, user, roleIDs); err != nil {
+		return nil, err
+	} else if required {
+		logging.Context(ctx).Info("Single sign-on requires two-factor authentication", zap.String("provider", name),
+			zap.String("username", user.Username))
+		return a.LoginBIZ.TwoFactorBIZ.CreateChallenge(ctx, user)
+	}
+
+	logging.Context(ctx).Info("Login success by single sign-on", zap.String("provider", name),
+		zap.String("username", user.Username))
+	return a.LoginBIZ.completeLogin(ctx, user, roleIDs, rolesUntil)
+}
+
+func claimOrDefault(claims oidc.Claims, name, defName string) string {
+	if name == "" {
+		name = defName
+	}
+	return claims.String(name)
+}
+
+// resolveUser Map the claims to the external user and find or provision the linked user.
+func (a *OIDC) resolveUser(ctx context.Context, cfg config.OIDCProvider, claims oidc.Claims) (*schema.User, error) {
+	username := claimOrDefault(claims, cfg.UsernameClaim, "preferred_username")
+	if username == "" {
+		username = claims.String("email")
+	}
+	if username == "" {
+		username = claims.String("sub")
+	}
+	groupsClaim := cfg.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+
+	return a.IdentityBIZ.Resolve(ctx, &ExternalUser{
+		Provider: cfg.Name,
+		Subject:  claims.String("sub"),
+		Username: username,
+		Name:     claimOrDefault(claims, cfg.NameClaim, "name"),
+		Email:    claimOrDefault(claims, cfg.EmailClaim, "email"),
+		Groups:   claims.Strings(groupsClaim),
+	}, ProvisionOptions{
+		RoleMapping:  cfg.RoleMapping,
+		DefaultRoles: cfg.DefaultRoles,
+		AutoCreate:   cfg.AutoCreate,
+		SyncRoles:    cfg.SyncRoles,
+		ErrorID:      config.ErrSSOLoginFailedID,
+	})
+}
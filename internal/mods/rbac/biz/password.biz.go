@@ -0,0 +1,103 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/password"
+)
+
+const passwordReusedCode = "reused"
+
+// Password policy of login passwords, checked whenever a password is set
+type Password struct {
+	PasswordHistoryDAL *dal.PasswordHistory
+}
+
+func (a *Password) policy() password.Policy {
+	cfg := config.C.General.PasswordPolicy
+	return password.Policy{
+		MinLength:     cfg.MinLength,
+		RequireUpper:  cfg.RequireUpper,
+		RequireLower:  cfg.RequireLower,
+		RequireDigit:  cfg.RequireDigit,
+		RequireSymbol: cfg.RequireSymbol,
+		DenyCommon:    cfg.DenyCommon,
+		DenyList:      cfg.DenyList,
+	}
+}
+
+// Check Validate the password of the field against the policy, for an existing user (userID > 0) the current and
+// the recent passwords can not be reused. All violations are returned as field errors.
+func (a *Password) Check(ctx context.Context, field string, userID int64, currentHash, pwd string) error {
+	var fields []*errors.FieldError
+	for _, v := range a.policy().Validate(pwd) {
+		fields = append(fields, &errors.FieldError{Field: field, Code: v.Code, Message: v.Message})
+	}
+
+	if userID > 0 {
+		reused, err := a.isReused(ctx, userID, currentHash, pwd)
+		if err != nil {
+			return err
+		} else if reused {
+			fields = append(fields, &errors.FieldError{
+				Field:   field,
+				Code:    passwordReusedCode,
+				Message: "Password has been used recently",
+			})
+		}
+	}
+
+	if len(fields) > 0 {
+		return errors.InvalidFields(config.ErrInvalidPasswordID, fields...)
+	}
+	return nil
+}
+
+func (a *Password) isReused(ctx context.Context, userID int64, currentHash, pwd string) (bool, error) {
+	size := config.C.General.PasswordPolicy.HistorySize
+	if size <= 0 {
+		return false, nil
+	}
+
+	hashes := []string{currentHash}
+	histories, err := a.PasswordHistoryDAL.QueryLatest(ctx, userID, size)
+	if err != nil {
+		return false, err
+	}
+	for _, item := range histories {
+		if item.Password != currentHash {
+			hashes = append(hashes, item.Password)
+		}
+	}
+
+	for _, h := range hashes {
+		if h != "" && hash.CompareHashAndPassword(h, pwd) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Record Keep the password hash in the history of the user, entries beyond the history size are dropped.
+func (a *Password) Record(ctx context.Context, userID int64, passwordHash string) error {
+	size := config.C.General.PasswordPolicy.HistorySize
+	if size <= 0 {
+		return nil
+	}
+
+	err := a.PasswordHistoryDAL.Create(ctx, &schema.PasswordHistory{
+		UserID:    userID,
+		Password:  passwordHash,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return a.PasswordHistoryDAL.DeleteOlder(ctx, userID, size)
+}
@@ -0,0 +1,258 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/LyricTian/captcha"
+	"go.uber.org/zap"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/cachex"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/rand"
+	"github.com/supermicah/go-framework-admin/pkg/encoding/json"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/logging"
+	"github.com/supermicah/go-framework-admin/pkg/mail"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+const passwordResetTokenLength = 48
+
+const defaultPasswordResetTemplate = `<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #333;">
+  <p>Hi {{.Name}},</p>
+  <p>We received a request to reset the password of your {{.AppName}} account <b>{{.Username}}</b>.</p>
+  <p><a href="{{.Link}}">Reset your password</a></p>
+  <p>The link expires in {{.ExpiresIn}} minutes and can only be used once. If you did not request a password reset, you can ignore this email.</p>
+</body>
+</html>`
+
+// passwordResetToken Reset token of a user, stored in the cache by the hash of the token.
+type passwordResetToken struct {
+	UserID       int64 `json:"uid"`
+	TokenVersion int64 `json:"tv"`
+}
+
+// passwordResetMail Data of the email template
+type passwordResetMail struct {
+	AppName   string
+	Name      string
+	Username  string
+	Link      string
+	Token     string
+	ExpiresIn int // minutes
+}
+
+// PasswordReset Self-service password reset by a one-time link sent to the email of the user
+type PasswordReset struct {
+	Cache       cachex.Cacher
+	Trans       *util.Trans
+	UserDAL     *dal.User
+	UserBIZ     *User
+	PasswordBIZ *Password
+	LockoutBIZ  *Lockout
+}
+
+func passwordResetKey(token string) string {
+	return hash.SHA256String(token)
+}
+
+// Forgot Email a reset link to each activated user of the email. The result is the same whether the email exists
+// or not, the tokens are created and the emails are sent in the background.
+func (a *PasswordReset) Forgot(ctx context.Context, formItem *schema.PasswordForgotForm) error {
+	if !captcha.VerifyString(formItem.CaptchaID, formItem.CaptchaCode) {
+		return errors.BadRequest(config.ErrInvalidCaptchaID, "Incorrect captcha")
+	}
+
+	// A broken template fails before the lookup, whatever the email
+	tmpl, err := a.parseTemplate()
+	if err != nil {
+		return err
+	}
+
+	ctx = logging.NewTag(withRequestTenant(ctx), logging.TagKeyLogin)
+	userResult, err := a.UserDAL.Query(ctx, schema.UserQueryParam{
+		Email:  formItem.Email,
+		Status: schema.UserStatusActivated,
+	}, schema.UserQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "username", "name", "email", "token_version"},
+		},
+	})
+	if err != nil {
+		return err
+	} else if len(userResult.Data) == 0 {
+		logging.Context(ctx).Info("Password reset requested for unknown email", zap.String("email", formItem.Email))
+		return nil
+	}
+
+	for _, user := range userResult.Data {
+		mailCtx := logging.NewUserID(logging.NewTag(logging.NewTraceID(context.Background(),
+			logging.FromTraceID(ctx)), logging.TagKeyLogin), user.ID)
+		go a.sendMail(mailCtx, tmpl, user)
+	}
+	return nil
+}
+
+func (a *PasswordReset) createToken(ctx context.Context, user *schema.User) (string, error) {
+	token, err := rand.Random(passwordResetTokenLength, rand.LdigitAndLetter)
+	if err != nil {
+		return "", err
+	}
+
+	resetToken := &passwordResetToken{UserID: user.ID, TokenVersion: user.TokenVersion}
+	expiration := time.Duration(config.C.Util.PasswordReset.Expired) * time.Second
+	err = a.Cache.Set(ctx, config.CacheNSForPwdReset, passwordResetKey(token), json.MarshalToString(resetToken), expiration)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (a *PasswordReset) parseTemplate() (*template.Template, error) {
+	text := defaultPasswordResetTemplate
+	if name := config.C.Util.PasswordReset.TemplateFile; name != "" {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(config.C.General.WorkDir, name)
+		}
+		buf, err := os.ReadFile(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read password reset template %s", name)
+		}
+		text = string(buf)
+	}
+	return template.New("password_reset").Parse(text)
+}
+
+func (a *PasswordReset) renderMail(tmpl *template.Template, user *schema.User, token string) (string, error) {
+	cfg := config.C.Util.PasswordReset
+	link := cfg.URL
+	if link != "" {
+		sep := "?"
+		if strings.Contains(link, "?") {
+			sep = "&"
+		}
+		link += sep + "token=" + url.QueryEscape(token)
+	}
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, passwordResetMail{
+		AppName:   config.C.General.AppName,
+		Name:      user.Name,
+		Username:  user.Username,
+		Link:      link,
+		Token:     token,
+		ExpiresIn: cfg.Expired / 60,
+	})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return buf.String(), nil
+}
+
+func (a *PasswordReset) sendMail(ctx context.Context, tmpl *template.Template, user *schema.User) {
+	token, err := a.createToken(ctx, user)
+	if err != nil {
+		logging.Context(ctx).Error("Failed to create password reset token", zap.Error(err))
+		return
+	}
+
+	body, err := a.renderMail(tmpl, user, token)
+	if err != nil {
+		logging.Context(ctx).Error("Failed to render password reset email", zap.Error(err))
+		return
+	}
+
+	cfg := config.C.Util.Mail
+	sender := &mail.SmtpSender{
+		SmtpHost: cfg.SmtpHost,
+		Port:     cfg.Port,
+		FromName: cfg.FromName,
+		FromMail: cfg.FromMail,
+		UserName: cfg.UserName,
+		AuthCode: cfg.AuthCode,
+	}
+	if err := sender.SendTo(ctx, []string{user.Email}, config.C.Util.PasswordReset.Subject, body); err != nil {
+		logging.Context(ctx).Error("Failed to send password reset email", zap.Error(err))
+		return
+	}
+	logging.Context(ctx).Info("Password reset email sent")
+}
+
+// Reset Set the new password with the reset token, the token is consumed once the password is accepted.
+// All issued tokens are revoked and the login lock of the user is removed.
+func (a *PasswordReset) Reset(ctx context.Context, formItem *schema.PasswordResetForm) error {
+	invalidToken := errors.BadRequest(config.ErrInvalidResetTokenID, "Invalid or expired reset token")
+	key := passwordResetKey(formItem.Token)
+	val, ok, err := a.Cache.Get(ctx, config.CacheNSForPwdReset, key)
+	if err != nil {
+		return err
+	} else if !ok {
+		return invalidToken
+	}
+
+	resetToken := new(passwordResetToken)
+	if err := json.Unmarshal([]byte(val), resetToken); err != nil {
+		return invalidToken
+	}
+
+	ctx = logging.NewUserID(logging.NewTag(ctx, logging.TagKeyLogin), resetToken.UserID)
+	user, err := a.UserDAL.Get(ctx, resetToken.UserID, schema.UserQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "username", "password", "status", "token_version"},
+		},
+	})
+	if err != nil {
+		return err
+	} else if user == nil || user.Status != schema.UserStatusActivated || user.TokenVersion != resetToken.TokenVersion {
+		return invalidToken
+	}
+
+	if err := a.PasswordBIZ.Check(ctx, "password", user.ID, user.Password, formItem.Password); err != nil {
+		return err
+	}
+
+	// Consume the token, only one of the concurrent requests wins
+	if _, ok, err := a.Cache.GetAndDelete(ctx, config.CacheNSForPwdReset, key); err != nil {
+		return err
+	} else if !ok {
+		return invalidToken
+	}
+
+	hashPass, err := hash.GeneratePassword(formItem.Password)
+	if err != nil {
+		return errors.BadRequest("", "Failed to generate hash password: %s", err.Error())
+	}
+
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
+		if err := a.UserDAL.UpdatePasswordByID(ctx, user.ID, hashPass, false); err != nil {
+			return err
+		}
+		if err := a.PasswordBIZ.Record(ctx, user.ID, hashPass); err != nil {
+			return err
+		}
+		return a.UserBIZ.RevokeTokens(ctx, user.ID)
+	})
+	if err != nil {
+		return err
+	} else if err := a.UserBIZ.InvalidateTokens(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := a.LockoutBIZ.Unlock(ctx, user.Username); err != nil {
+		logging.Context(ctx).Error("Failed to unlock login", zap.Error(err))
+	}
+	logging.Context(ctx).Info("Password reset by email", zap.String("username", user.Username))
+	return nil
+}
@@ -0,0 +1,318 @@
+package biz
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/casbin/casbin/v2"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// permissionSubject Role checked against the policies of its tenant
+type permissionSubject struct {
+	RoleID int64
+	Domain string
+}
+
+// Permission Checks and explains the permissions of the users and roles with the casbin enforcer, the same way as the
+// casbin middleware: a request is allowed if one of the roles is allowed and none of them is explicitly denied.
+type Permission struct {
+	Casbinx         *Casbinx
+	UserBIZ         *User
+	UserDAL         *dal.User
+	RoleDAL         *dal.Role
+	RoleMenuDAL     *dal.RoleMenu
+	RoleResourceDAL *dal.RoleResource
+	MenuDAL         *dal.Menu
+	MenuResourceDAL *dal.MenuResource
+}
+
+func (a *Permission) getEnforcer() (*casbin.SyncedEnforcer, error) {
+	enforcer := a.Casbinx.GetEnforcer()
+	if enforcer == nil {
+		return nil, errors.BadRequest("", "Casbin is disabled")
+	}
+	return enforcer, nil
+}
+
+// Check Whether the user or the roles are allowed to request the path with the method, and the policy deciding it.
+func (a *Permission) Check(ctx context.Context, formItem *schema.PermissionCheckForm) (*schema.PermissionCheck, error) {
+	enforcer, err := a.getEnforcer()
+	if err != nil {
+		return nil, err
+	}
+
+	var subjects []permissionSubject
+	if formItem.UserID > 0 {
+		if formItem.UserID == config.C.General.Root.ID {
+			return &schema.PermissionCheck{Allowed: true, Root: true}, nil
+		}
+		subjects, err = a.getUserSubjects(ctx, formItem.UserID)
+	} else {
+		subjects, err = a.getRoleSubjects(ctx, formItem.RoleIDs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(schema.PermissionCheck)
+	for _, sub := range subjects {
+		allowed, explain, err := enforcer.EnforceEx(strconv.FormatInt(sub.RoleID, 10), sub.Domain, formItem.Path, formItem.Method)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		} else if allowed {
+			if !result.Allowed {
+				result.Allowed = true
+				result.SubjectID = sub.RoleID
+				result.Policy = explain
+			}
+		} else if len(explain) > 0 && explain[len(explain)-1] == schema.ResourceEffectDeny {
+			// An explicit deny overrides the policies allowing the request
+			result.Allowed = false
+			result.SubjectID = sub.RoleID
+			result.Policy = explain
+			break
+		}
+	}
+
+	if len(result.Policy) == 0 {
+		return result, nil
+	}
+	result.Effect = result.Policy[len(result.Policy)-1]
+	if err := a.explain(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getUserSubjects The roles of the user, checked in the tenant of the user.
+func (a *Permission) getUserSubjects(ctx context.Context, userID int64) ([]permissionSubject, error) {
+	user, err := a.UserDAL.Get(ctx, userID, schema.UserQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "tenant_id"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	} else if user == nil {
+		return nil, errors.NotFound("", "User not found")
+	}
+
+	roleIDs, err := a.UserBIZ.GetRoleIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	domain := strconv.FormatInt(user.TenantID, 10)
+	subjects := make([]permissionSubject, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		subjects = append(subjects, permissionSubject{RoleID: roleID, Domain: domain})
+	}
+	return subjects, nil
+}
+
+// getRoleSubjects The roles, each checked in its own tenant.
+func (a *Permission) getRoleSubjects(ctx context.Context, roleIDs []int64) ([]permissionSubject, error) {
+	roleResult, err := a.RoleDAL.Query(ctx, schema.RoleQueryParam{
+		InIDs: roleIDs,
+	}, schema.RoleQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "tenant_id"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make(map[int64]*schema.Role, len(roleResult.Data))
+	for _, role := range roleResult.Data {
+		roles[role.ID] = role
+	}
+	subjects := make([]permissionSubject, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		role, ok := roles[roleID]
+		if !ok {
+			return nil, errors.NotFound("", "Role %d not found", roleID)
+		}
+		subjects = append(subjects, permissionSubject{RoleID: roleID, Domain: strconv.FormatInt(role.TenantID, 10)})
+	}
+	return subjects, nil
+}
+
+// explain Fill the role of the policy (the checked role or one of its parents) and the resource it comes from, either
+// a resource of the role or a resource of a menu granted to the role.
+func (a *Permission) explain(ctx context.Context, result *schema.PermissionCheck) error {
+	if len(result.Policy) < 5 {
+		return nil
+	}
+	roleID, err := strconv.ParseInt(result.Policy[0], 10, 64)
+	if err != nil {
+		return nil
+	}
+	path, method, effect := result.Policy[2], result.Policy[3], result.Policy[4]
+
+	role, err := a.RoleDAL.Get(ctx, roleID, schema.RoleQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "tenant_id", "code", "name", "status"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	result.Role = role
+
+	roleResourceResult, err := a.RoleResourceDAL.Query(ctx, schema.RoleResourceQueryParam{
+		RoleID: roleID,
+		Method: method,
+		Path:   path,
+		Effect: effect,
+	})
+	if err != nil {
+		return err
+	} else if len(roleResourceResult.Data) > 0 {
+		result.RoleResource = roleResourceResult.Data[0]
+		return nil
+	}
+
+	menuIDs, err := a.getRoleMenuIDs(ctx, roleID)
+	if err != nil || len(menuIDs) == 0 {
+		return err
+	}
+
+	menuResourceResult, err := a.MenuResourceDAL.Query(ctx, schema.MenuResourceQueryParam{
+		MenuIDs: menuIDs,
+		Method:  method,
+		Path:    path,
+		Effect:  effect,
+	})
+	if err != nil {
+		return err
+	} else if len(menuResourceResult.Data) == 0 {
+		return nil
+	}
+	result.MenuResource = menuResourceResult.Data[0]
+
+	menu, err := a.MenuDAL.Get(ctx, result.MenuResource.MenuID, schema.MenuQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "code", "name", "type", "status", "parent_id", "parent_path"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	result.Menu = menu
+	return nil
+}
+
+// getRoleMenuIDs The menus granted to the role and their parents, whose resources are granted as well.
+func (a *Permission) getRoleMenuIDs(ctx context.Context, roleID int64) ([]int64, error) {
+	roleMenuResult, err := a.RoleMenuDAL.Query(ctx, schema.RoleMenuQueryParam{
+		RoleID: roleID,
+	})
+	if err != nil {
+		return nil, err
+	} else if len(roleMenuResult.Data) == 0 {
+		return nil, nil
+	}
+
+	roleMenuIDs := make([]int64, 0, len(roleMenuResult.Data))
+	for _, roleMenu := range roleMenuResult.Data {
+		roleMenuIDs = append(roleMenuIDs, roleMenu.MenuID)
+	}
+
+	menuResult, err := a.MenuDAL.Query(ctx, schema.MenuQueryParam{
+		InIDs:  roleMenuIDs,
+		Status: schema.MenuStatusEnabled,
+	}, schema.MenuQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "parent_path"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	menuIDs := make([]int64, 0, len(menuResult.Data))
+	for _, menu := range menuResult.Data {
+		menuIDs = append(menuIDs, menu.ID)
+		for _, pid := range strings.Split(menu.ParentPath, util.TreePathDelimiter) {
+			if parentID, err := strconv.ParseInt(pid, 10, 64); err == nil {
+				menuIDs = append(menuIDs, parentID)
+			}
+		}
+	}
+	return menuIDs, nil
+}
+
+// QueryUserPermissions List the methods and paths allowed to the user by its roles and their parents, except the ones
+// explicitly denied to one of the roles.
+func (a *Permission) QueryUserPermissions(ctx context.Context, userID int64) (schema.Permissions, error) {
+	enforcer, err := a.getEnforcer()
+	if err != nil {
+		return nil, err
+	} else if userID == config.C.General.Root.ID {
+		return nil, errors.BadRequest("", "The root user has all the permissions")
+	}
+
+	subjects, err := a.getUserSubjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make(map[string]*schema.Permission)
+	for _, sub := range subjects {
+		policies, err := enforcer.GetImplicitPermissionsForUser(strconv.FormatInt(sub.RoleID, 10), sub.Domain)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		for _, policy := range policies {
+			if len(policy) < 5 || policy[4] != schema.ResourceEffectAllow {
+				continue
+			}
+			path, method := policy[2], policy[3]
+			key := method + " " + path
+			item, ok := permissions[key]
+			if !ok {
+				item = &schema.Permission{Method: method, Path: path}
+				permissions[key] = item
+			}
+			if len(item.RoleIDs) == 0 || item.RoleIDs[len(item.RoleIDs)-1] != sub.RoleID {
+				item.RoleIDs = append(item.RoleIDs, sub.RoleID)
+			}
+		}
+	}
+
+	list := make(schema.Permissions, 0, len(permissions))
+	for _, item := range permissions {
+		denied := false
+		for _, sub := range subjects {
+			// The path pattern matches the deny policies of the same or of a wider pattern
+			_, explain, err := enforcer.EnforceEx(strconv.FormatInt(sub.RoleID, 10), sub.Domain, item.Path, item.Method)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			} else if len(explain) > 0 && explain[len(explain)-1] == schema.ResourceEffectDeny {
+				denied = true
+				break
+			}
+		}
+		if !denied {
+			list = append(list, item)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Path == list[j].Path {
+			return list[i].Method < list[j].Method
+		}
+		return list[i].Path < list[j].Path
+	})
+	return list, nil
+}
@@ -2,24 +2,23 @@ package biz
 
 import (
 	"context"
-	"fmt"
 	"time"
 
-	"github.com/supermicah/go-framework-admin/internal/config"
 	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
 	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
-	"github.com/supermicah/go-framework-admin/pkg/cachex"
 	"github.com/supermicah/go-framework-admin/pkg/errors"
 	"github.com/supermicah/go-framework-admin/pkg/util"
 )
 
 // Role management for RBAC
 type Role struct {
-	Cache       cachex.Cacher
-	Trans       *util.Trans
-	RoleDAL     *dal.Role
-	RoleMenuDAL *dal.RoleMenu
-	UserRoleDAL *dal.UserRole
+	Trans           *util.Trans
+	RoleDAL         *dal.Role
+	RoleMenuDAL     *dal.RoleMenu
+	RoleParentDAL   *dal.RoleParent
+	RoleResourceDAL *dal.RoleResource
+	UserRoleDAL     *dal.UserRole
+	EventBIZ        *Event
 }
 
 // Query roles from the data access object based on the provided parameters and options.
@@ -64,9 +63,122 @@ func (a *Role) Get(ctx context.Context, id int64) (*schema.Role, error) {
 	}
 	role.Menus = roleMenuResult.Data
 
+	roleParentResult, err := a.RoleParentDAL.Query(ctx, schema.RoleParentQueryParam{
+		RoleID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	role.ParentIDs = roleParentResult.Data.ToParentIDs()
+
+	roleResourceResult, err := a.RoleResourceDAL.Query(ctx, schema.RoleResourceQueryParam{
+		RoleID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	role.Resources = roleResourceResult.Data
+
+	// The effective menus include the menus inherited from the enabled ancestors
+	role.EffectiveMenus = append(role.EffectiveMenus, role.Menus...)
+	ancestorIDs, err := a.RoleParentDAL.QueryAncestorIDs(ctx, []int64{id}, schema.RoleStatusEnabled)
+	if err != nil {
+		return nil, err
+	} else if len(ancestorIDs) > 0 {
+		inheritedResult, err := a.RoleMenuDAL.Query(ctx, schema.RoleMenuQueryParam{
+			InRoleIDs: ancestorIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		menuIDs := make(map[int64]struct{})
+		for _, item := range role.EffectiveMenus {
+			menuIDs[item.MenuID] = struct{}{}
+		}
+		for _, item := range inheritedResult.Data {
+			if _, ok := menuIDs[item.MenuID]; ok {
+				continue
+			}
+			menuIDs[item.MenuID] = struct{}{}
+			role.EffectiveMenus = append(role.EffectiveMenus, item)
+		}
+	}
+
 	return role, nil
 }
 
+// checkParents Check that the parent roles exist and that the role does not inherit itself.
+func (a *Role) checkParents(ctx context.Context, id int64, parentIDs []int64) error {
+	if len(parentIDs) == 0 {
+		return nil
+	}
+
+	parentResult, err := a.RoleDAL.Query(ctx, schema.RoleQueryParam{
+		InIDs: parentIDs,
+	}, schema.RoleQueryOptions{
+		QueryOptions: util.QueryOptions{SelectFields: []string{"id"}},
+	})
+	if err != nil {
+		return err
+	} else if len(parentResult.Data) != len(parentIDs) {
+		return errors.BadRequest("", "Parent role not found")
+	}
+
+	if id == 0 {
+		return nil
+	}
+	for _, parentID := range parentIDs {
+		if parentID == id {
+			return errors.BadRequest("", "Role inheritance cannot be circular")
+		}
+	}
+	ancestorIDs, err := a.RoleParentDAL.QueryAncestorIDs(ctx, parentIDs, "")
+	if err != nil {
+		return err
+	}
+	for _, ancestorID := range ancestorIDs {
+		if ancestorID == id {
+			return errors.BadRequest("", "Role inheritance cannot be circular")
+		}
+	}
+	return nil
+}
+
+// saveParents Replace the parents of the role.
+func (a *Role) saveParents(ctx context.Context, id int64, parentIDs []int64) error {
+	if err := a.RoleParentDAL.DeleteByRoleID(ctx, id); err != nil {
+		return err
+	}
+	for _, parentID := range parentIDs {
+		if err := a.RoleParentDAL.Create(ctx, &schema.RoleParent{
+			RoleID:    id,
+			ParentID:  parentID,
+			CreatedAt: time.Now(),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveResources Replace the resources of the role.
+func (a *Role) saveResources(ctx context.Context, role *schema.Role, resources schema.RoleResources) error {
+	if err := a.RoleResourceDAL.DeleteByRoleID(ctx, role.ID); err != nil {
+		return err
+	}
+	for _, res := range resources {
+		res.ID = 0
+		res.RoleID = role.ID
+		res.TenantID = role.TenantID
+		res.CreatedAt = time.Now()
+		if err := a.RoleResourceDAL.Create(ctx, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Create a new role in the data access object.
 func (a *Role) Create(ctx context.Context, formItem *schema.RoleForm) (*schema.Role, error) {
 	if exists, err := a.RoleDAL.ExistsCode(ctx, formItem.Code); err != nil {
@@ -74,6 +186,9 @@ func (a *Role) Create(ctx context.Context, formItem *schema.RoleForm) (*schema.R
 	} else if exists {
 		return nil, errors.BadRequest("", "Role code already exists")
 	}
+	if err := a.checkParents(ctx, 0, formItem.ParentIDs); err != nil {
+		return nil, err
+	}
 
 	role := &schema.Role{
 		CreatedAt: time.Now(),
@@ -89,17 +204,25 @@ func (a *Role) Create(ctx context.Context, formItem *schema.RoleForm) (*schema.R
 
 		for _, roleMenu := range formItem.Menus {
 			roleMenu.RoleID = role.ID
+			roleMenu.TenantID = role.TenantID
 			roleMenu.CreatedAt = time.Now()
 			if err := a.RoleMenuDAL.Create(ctx, roleMenu); err != nil {
 				return err
 			}
 		}
-		return a.syncToCasbin(ctx)
+		if err := a.saveResources(ctx, role, formItem.Resources); err != nil {
+			return err
+		}
+		return a.saveParents(ctx, role.ID, formItem.ParentIDs)
 	})
 	if err != nil {
 		return nil, err
+	} else if err := a.EventBIZ.RoleChanged(ctx, role.ID); err != nil {
+		return nil, err
 	}
 	role.Menus = formItem.Menus
+	role.ParentIDs = formItem.ParentIDs
+	role.Resources = formItem.Resources
 
 	return role, nil
 }
@@ -118,13 +241,16 @@ func (a *Role) Update(ctx context.Context, id int64, formItem *schema.RoleForm)
 			return errors.BadRequest("", "Role code already exists")
 		}
 	}
+	if err := a.checkParents(ctx, id, formItem.ParentIDs); err != nil {
+		return err
+	}
 
 	if err := formItem.FillTo(role); err != nil {
 		return err
 	}
 	role.UpdatedAt = time.Now()
 
-	return a.Trans.Exec(ctx, func(ctx context.Context) error {
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
 		if err := a.RoleDAL.Update(ctx, role); err != nil {
 			return err
 		}
@@ -133,6 +259,7 @@ func (a *Role) Update(ctx context.Context, id int64, formItem *schema.RoleForm)
 		}
 		for _, roleMenu := range formItem.Menus {
 			roleMenu.RoleID = role.ID
+			roleMenu.TenantID = role.TenantID
 			if roleMenu.CreatedAt.IsZero() {
 				roleMenu.CreatedAt = time.Now()
 			}
@@ -141,8 +268,15 @@ func (a *Role) Update(ctx context.Context, id int64, formItem *schema.RoleForm)
 				return err
 			}
 		}
-		return a.syncToCasbin(ctx)
+		if err := a.saveResources(ctx, role, formItem.Resources); err != nil {
+			return err
+		}
+		return a.saveParents(ctx, id, formItem.ParentIDs)
 	})
+	if err != nil {
+		return err
+	}
+	return a.EventBIZ.RoleChanged(ctx, id)
 }
 
 // Delete the specified role from the data access object.
@@ -154,21 +288,26 @@ func (a *Role) Delete(ctx context.Context, id int64) error {
 		return errors.NotFound("", "Role not found")
 	}
 
-	return a.Trans.Exec(ctx, func(ctx context.Context) error {
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
 		if err := a.RoleDAL.Delete(ctx, id); err != nil {
 			return err
 		}
 		if err := a.RoleMenuDAL.DeleteByRoleID(ctx, id); err != nil {
 			return err
 		}
-		if err := a.UserRoleDAL.DeleteByRoleID(ctx, id); err != nil {
+		if err := a.RoleParentDAL.DeleteByRoleID(ctx, id); err != nil {
 			return err
 		}
-
-		return a.syncToCasbin(ctx)
+		if err := a.RoleParentDAL.DeleteByParentID(ctx, id); err != nil {
+			return err
+		}
+		if err := a.RoleResourceDAL.DeleteByRoleID(ctx, id); err != nil {
+			return err
+		}
+		return a.UserRoleDAL.DeleteByRoleID(ctx, id)
 	})
-}
-
-func (a *Role) syncToCasbin(ctx context.Context) error {
-	return a.Cache.Set(ctx, config.CacheNSForRole, config.CacheKeyForSyncToCasbin, fmt.Sprintf("%d", time.Now().Unix()))
+	if err != nil {
+		return err
+	}
+	return a.EventBIZ.RoleChanged(ctx, id)
 }
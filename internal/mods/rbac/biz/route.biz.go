@@ -0,0 +1,163 @@
+package biz
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	casbinUtil "github.com/casbin/casbin/v2/util"
+	"github.com/gin-gonic/gin"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// Route Discovers the routes registered in the HTTP server to pick and validate the resources of the menus.
+type Route struct {
+	lock            sync.RWMutex  `wire:"-"`
+	routes          schema.Routes `wire:"-"`
+	MenuDAL         *dal.Menu
+	MenuResourceDAL *dal.MenuResource
+}
+
+// SetRoutes Keep the routes of the engine under the allowed prefixes, the routes are protected unless casbin skips
+// them.
+func (a *Route) SetRoutes(routes gin.RoutesInfo, allowedPrefixes []string) {
+	list := make(schema.Routes, 0, len(routes))
+	for _, route := range routes {
+		if !hasPathPrefix(route.Path, allowedPrefixes) {
+			continue
+		}
+
+		handler := route.Handler
+		if i := strings.LastIndex(handler, "/"); i >= 0 {
+			handler = handler[i+1:]
+		}
+		list = append(list, &schema.Route{
+			Method:    route.Method,
+			Path:      route.Path,
+			Handler:   strings.TrimSuffix(handler, "-fm"),
+			Protected: !hasPathPrefix(route.Path, config.C.Middleware.Casbin.SkippedPathPrefixes),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Path == list[j].Path {
+			return list[i].Method < list[j].Method
+		}
+		return list[i].Path < list[j].Path
+	})
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.routes = list
+}
+
+func hasPathPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Route) getRoutes() (schema.Routes, error) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	if a.routes == nil {
+		return nil, errors.BadRequest("", "Routes are not loaded")
+	}
+	return a.routes, nil
+}
+
+// matchRoute Whether the resource matches the route the same way as the casbin model matches the requests.
+func matchRoute(route *schema.Route, method, path string) bool {
+	return route.Method == method &&
+		(casbinUtil.KeyMatch2(route.Path, path) || casbinUtil.KeyMatch3(route.Path, path))
+}
+
+// Query the registered routes.
+func (a *Route) Query(ctx context.Context, params schema.RouteQueryParam) (schema.Routes, error) {
+	routes, err := a.getRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make(schema.Routes, 0, len(routes))
+	for _, route := range routes {
+		if params.Protected && !route.Protected {
+			continue
+		} else if params.LikePath != "" && !strings.Contains(route.Path, params.LikePath) {
+			continue
+		}
+		list = append(list, route)
+	}
+	return list, nil
+}
+
+// Report List the menu resources matching no registered route, and the protected routes allowed by no menu resource.
+func (a *Route) Report(ctx context.Context) (*schema.RouteReport, error) {
+	routes, err := a.getRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	menuResult, err := a.MenuDAL.Query(ctx, schema.MenuQueryParam{}, schema.MenuQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "code", "parent_path"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	codes := make(map[int64]string, len(menuResult.Data))
+	parentPaths := make(map[int64]string, len(menuResult.Data))
+	for _, menu := range menuResult.Data {
+		codes[menu.ID] = menu.Code
+		parentPaths[menu.ID] = menu.ParentPath
+	}
+	codePaths := treeCodePaths(codes, parentPaths)
+
+	resResult, err := a.MenuResourceDAL.Query(ctx, schema.MenuResourceQueryParam{}, schema.MenuResourceQueryOptions{
+		QueryOptions: util.QueryOptions{
+			OrderFields: []util.OrderByParam{{Field: "menu_id", Direction: util.ASC}, {Field: "id", Direction: util.ASC}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &schema.RouteReport{Routes: len(routes)}
+	covered := make(map[*schema.Route]bool, len(routes))
+	for _, res := range resResult.Data {
+		matched := false
+		for _, route := range routes {
+			if matchRoute(route, res.Method, res.Path) {
+				matched = true
+				if res.Effect != schema.ResourceEffectDeny {
+					covered[route] = true
+				}
+			}
+		}
+		if !matched {
+			report.StaleResources = append(report.StaleResources, &schema.RouteReportResource{
+				MenuID:   res.MenuID,
+				CodePath: codePaths[res.MenuID],
+				Method:   res.Method,
+				Path:     res.Path,
+				Effect:   res.Effect,
+			})
+		}
+	}
+
+	for _, route := range routes {
+		if route.Protected && !covered[route] {
+			report.UncoveredRoutes = append(report.UncoveredRoutes, route)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,174 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/cachex"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/jwtx"
+	"github.com/supermicah/go-framework-admin/pkg/logging"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// Session Registry of active logins, each login is stored in the cache until its refresh token expires
+type Session struct {
+	Cache   cachex.Cacher
+	Auth    jwtx.Auther
+	UserDAL *dal.User
+}
+
+func (a *Session) cacheNS(userID int64) string {
+	return fmt.Sprintf("%s:%d", config.CacheNSForSession, userID)
+}
+
+func (a *Session) set(ctx context.Context, item *schema.Session) error {
+	expiration := time.Until(item.ExpiresAt)
+	if expiration <= 0 {
+		return nil
+	}
+	return a.Cache.Set(ctx, a.cacheNS(item.UserID), item.ID, item.String(), expiration)
+}
+
+func (a *Session) get(ctx context.Context, userID int64, id string) (*schema.Session, error) {
+	val, ok, err := a.Cache.Get(ctx, a.cacheNS(userID), id)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+	return schema.ParseSession(val)
+}
+
+// Create Record a new login of the user with the client info of the request.
+func (a *Session) Create(ctx context.Context, userID int64, token jwtx.TokenInfo) error {
+	now := time.Now()
+	return a.set(ctx, &schema.Session{
+		ID:          token.GetFamilyID(),
+		UserID:      userID,
+		TokenID:     token.GetTokenID(),
+		ClientIP:    util.FromClientIP(ctx),
+		UserAgent:   util.FromUserAgent(ctx),
+		IssuedAt:    now,
+		RefreshedAt: now,
+		ExpiresAt:   time.Unix(token.GetRefreshExpiresAt(), 0),
+	})
+}
+
+// Refresh Update the session with the rotated token pair.
+func (a *Session) Refresh(ctx context.Context, userID int64, token jwtx.TokenInfo) error {
+	item, err := a.get(ctx, userID, token.GetFamilyID())
+	if err != nil {
+		return err
+	} else if item == nil {
+		// Logins issued before the registry existed are registered on the first refresh
+		return a.Create(ctx, userID, token)
+	}
+
+	item.TokenID = token.GetTokenID()
+	item.RefreshedAt = time.Now()
+	item.ExpiresAt = time.Unix(token.GetRefreshExpiresAt(), 0)
+	return a.set(ctx, item)
+}
+
+// Remove Drop the session record, the tokens must have been invalidated by the caller.
+func (a *Session) Remove(ctx context.Context, userID int64, id string) error {
+	if id == "" {
+		return nil
+	}
+	return a.Cache.Delete(ctx, a.cacheNS(userID), id)
+}
+
+// checkUser The sessions are only keyed by the user, its existence in the tenant of the context is checked before
+// they are accessed.
+func (a *Session) checkUser(ctx context.Context, userID int64) error {
+	exists, err := a.UserDAL.Exists(ctx, userID)
+	if err != nil {
+		return err
+	} else if !exists {
+		return errors.NotFound("", "User not found")
+	}
+	return nil
+}
+
+// Query Active sessions of the user, the session of the request is marked as current.
+func (a *Session) Query(ctx context.Context, userID int64) (schema.Sessions, error) {
+	if err := a.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return a.query(ctx, userID)
+}
+
+func (a *Session) query(ctx context.Context, userID int64) (schema.Sessions, error) {
+	currentID := util.FromSessionID(ctx)
+
+	list := make(schema.Sessions, 0)
+	var parseErr error
+	err := a.Cache.Iterator(ctx, a.cacheNS(userID), func(ctx context.Context, key, value string) bool {
+		item, err := schema.ParseSession(value)
+		if err != nil {
+			parseErr = err
+			return false
+		}
+		item.Current = currentID != "" && item.ID == currentID
+		list = append(list, item)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	} else if parseErr != nil {
+		return nil, parseErr
+	}
+
+	sort.Sort(list)
+	return list, nil
+}
+
+// Delete Revoke a session of the user, every token issued from the login becomes invalid.
+func (a *Session) Delete(ctx context.Context, userID int64, id string) error {
+	if err := a.checkUser(ctx, userID); err != nil {
+		return err
+	}
+	return a.revoke(ctx, userID, id)
+}
+
+func (a *Session) revoke(ctx context.Context, userID int64, id string) error {
+	item, err := a.get(ctx, userID, id)
+	if err != nil {
+		return err
+	} else if item == nil {
+		return errors.NotFound("", "Session not found")
+	}
+
+	if err := a.Auth.RevokeFamily(ctx, item.ID); err != nil {
+		return err
+	}
+	logging.Context(logging.NewTag(ctx, logging.TagKeyLogout)).Info("Revoke session",
+		zap.Int64("session_user_id", userID), zap.String("session_id", item.ID))
+	return a.Remove(ctx, userID, item.ID)
+}
+
+// DeleteAll Revoke all sessions of the user except the excluded one, the user may already be deleted.
+func (a *Session) DeleteAll(ctx context.Context, userID int64, excludeID string) error {
+	list, err := a.query(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range list {
+		if item.ID == excludeID {
+			continue
+		}
+		if err := a.revoke(ctx, userID, item.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/cachex"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// Tenant management for RBAC, the users, roles and role permissions are isolated by tenant. The tenant 0 is the
+// default tenant of the data created without a tenant.
+type Tenant struct {
+	Cache     cachex.Cacher
+	TenantDAL *dal.Tenant
+	UserDAL   *dal.User
+	RoleDAL   *dal.Role
+}
+
+// withRequestTenant Scope the anonymous requests to the tenant of the request, the default tenant if none is given.
+func withRequestTenant(ctx context.Context) context.Context {
+	tenantID, _ := util.FromTenantID(ctx)
+	return util.NewTenantID(ctx, tenantID)
+}
+
+// checkCrossTenant The tenants are only managed by the users who are not scoped to a tenant (e.g. root).
+func (a *Tenant) checkCrossTenant(ctx context.Context) error {
+	if _, ok := util.FromTenantID(ctx); ok {
+		return errors.Forbidden("", "Tenants can only be managed by the root user")
+	}
+	return nil
+}
+
+// Query tenants from the data access object based on the provided parameters and options.
+func (a *Tenant) Query(ctx context.Context, params schema.TenantQueryParam) (*schema.TenantQueryResult, error) {
+	if err := a.checkCrossTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	params.Pagination = true
+	result, err := a.TenantDAL.Query(ctx, params, schema.TenantQueryOptions{
+		QueryOptions: util.QueryOptions{
+			OrderFields: []util.OrderByParam{
+				{Field: "created_at", Direction: util.DESC},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Get the specified tenant from the data access object.
+func (a *Tenant) Get(ctx context.Context, id int64) (*schema.Tenant, error) {
+	if err := a.checkCrossTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	tenant, err := a.TenantDAL.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if tenant == nil {
+		return nil, errors.NotFound("", "Tenant not found")
+	}
+	return tenant, nil
+}
+
+// Create a new tenant in the data access object.
+func (a *Tenant) Create(ctx context.Context, formItem *schema.TenantForm) (*schema.Tenant, error) {
+	if err := a.checkCrossTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	if exists, err := a.TenantDAL.ExistsCode(ctx, formItem.Code); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, errors.BadRequest("", "Tenant code already exists")
+	}
+
+	tenant := &schema.Tenant{
+		CreatedAt: time.Now(),
+	}
+	if err := formItem.FillTo(tenant); err != nil {
+		return nil, err
+	}
+
+	if err := a.TenantDAL.Create(ctx, tenant); err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// Update the specified tenant in the data access object.
+func (a *Tenant) Update(ctx context.Context, id int64, formItem *schema.TenantForm) error {
+	if err := a.checkCrossTenant(ctx); err != nil {
+		return err
+	}
+
+	tenant, err := a.TenantDAL.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if tenant == nil {
+		return errors.NotFound("", "Tenant not found")
+	} else if tenant.Code != formItem.Code {
+		if exists, err := a.TenantDAL.ExistsCode(ctx, formItem.Code); err != nil {
+			return err
+		} else if exists {
+			return errors.BadRequest("", "Tenant code already exists")
+		}
+	}
+
+	if err := formItem.FillTo(tenant); err != nil {
+		return err
+	}
+	tenant.UpdatedAt = time.Now()
+
+	if err := a.TenantDAL.Update(ctx, tenant); err != nil {
+		return err
+	}
+	return a.Cache.Delete(ctx, config.CacheNSForTenant, fmt.Sprintf("%d", id))
+}
+
+// Delete the specified tenant from the data access object, only the tenants without users and roles can be deleted.
+func (a *Tenant) Delete(ctx context.Context, id int64) error {
+	if err := a.checkCrossTenant(ctx); err != nil {
+		return err
+	}
+
+	exists, err := a.TenantDAL.Exists(ctx, id)
+	if err != nil {
+		return err
+	} else if !exists {
+		return errors.NotFound("", "Tenant not found")
+	}
+
+	tenantCtx := util.NewTenantID(ctx, id)
+	countParam := util.PaginationParam{OnlyCount: true}
+	if userResult, err := a.UserDAL.Query(tenantCtx, schema.UserQueryParam{PaginationParam: countParam}); err != nil {
+		return err
+	} else if userResult.PageResult.Total > 0 {
+		return errors.BadRequest("", "Tenant still has users")
+	}
+	if roleResult, err := a.RoleDAL.Query(tenantCtx, schema.RoleQueryParam{PaginationParam: countParam}); err != nil {
+		return err
+	} else if roleResult.PageResult.Total > 0 {
+		return errors.BadRequest("", "Tenant still has roles")
+	}
+
+	if err := a.TenantDAL.Delete(ctx, id); err != nil {
+		return err
+	}
+	return a.Cache.Delete(ctx, config.CacheNSForTenant, fmt.Sprintf("%d", id))
+}
+
+// GetTenantID Resolve the tenant of the request by its code, the tenant must be enabled.
+func (a *Tenant) GetTenantID(c *gin.Context, code string) (int64, error) {
+	tenant, err := a.TenantDAL.GetByCode(c.Request.Context(), code, schema.TenantQueryOptions{
+		QueryOptions: util.QueryOptions{SelectFields: []string{"id", "status"}},
+	})
+	if err != nil {
+		return 0, err
+	} else if tenant == nil || tenant.Status != schema.TenantStatusEnabled {
+		return 0, errors.BadRequest(config.ErrInvalidTenantID, "Invalid tenant")
+	}
+	return tenant.ID, nil
+}
+
+// CheckEnabled Check that the tenant of an authenticated user is still enabled, the status is cached until the tenant
+// is changed.
+func (a *Tenant) CheckEnabled(ctx context.Context, id int64) error {
+	if id == 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf("%d", id)
+	status, ok, err := a.Cache.Get(ctx, config.CacheNSForTenant, key)
+	if err != nil {
+		return err
+	} else if !ok {
+		tenant, err := a.TenantDAL.Get(ctx, id, schema.TenantQueryOptions{
+			QueryOptions: util.QueryOptions{SelectFields: []string{"status"}},
+		})
+		if err != nil {
+			return err
+		} else if tenant != nil {
+			status = tenant.Status
+		}
+		err = a.Cache.Set(ctx, config.CacheNSForTenant, key, status,
+			time.Duration(config.C.Dictionary.UserCacheExp)*time.Hour)
+		if err != nil {
+			return err
+		}
+	}
+
+	if status != schema.TenantStatusEnabled {
+		return errors.Unauthorized(config.ErrInvalidTenantID, "Tenant is not available")
+	}
+	return nil
+}
@@ -0,0 +1,491 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/hotp"
+	"github.com/pquerna/otp/totp"
+	"go.uber.org/zap"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/cachex"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/aes"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/rand"
+	"github.com/supermicah/go-framework-admin/pkg/encoding/json"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/logging"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+const (
+	twoFactorChallengeLength    = 32
+	twoFactorRecoveryCodeLength = 10
+	twoFactorCodeLength         = 6
+	twoFactorPeriod             = 30  // seconds
+	twoFactorQRCodeSize         = 200 // pixels
+)
+
+// twoFactorChallenge Pending login waiting for the second factor, stored in the cache by challenge token.
+type twoFactorChallenge struct {
+	UserID       int64 `json:"uid"`
+	TokenVersion int64 `json:"tv"`
+	Attempts     int   `json:"n"`
+	ExpiresAt    int64 `json:"exp"`
+}
+
+// TwoFactor TOTP two-factor authentication for RBAC
+type TwoFactor struct {
+	Cache      cachex.Cacher
+	UserDAL    *dal.User
+	RoleDAL    *dal.Role
+	LockoutBIZ *Lockout
+}
+
+func (a *TwoFactor) secretKey() []byte {
+	if key := config.C.Util.TwoFactor.SecretKey; key != "" {
+		return []byte(key)
+	}
+	return aes.SecretKey
+}
+
+// validateTOTP Check the code against the periods around now, it returns the counter of the matched period so a
+// code that has already been used can be rejected.
+func (a *TwoFactor) validateTOTP(secret, code string, now time.Time) (uint64, bool) {
+	opts := hotp.ValidateOpts{Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+	counter := uint64(now.Unix()) / twoFactorPeriod
+	for i := -config.C.Util.TwoFactor.Skew; i <= config.C.Util.TwoFactor.Skew; i++ {
+		c := counter + uint64(i)
+		if ok, _ := hotp.ValidateCustom(code, c, secret, opts); ok {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+func (a *TwoFactor) getUser(ctx context.Context, id int64) (*schema.User, error) {
+	user, err := a.UserDAL.Get(ctx, id, schema.UserQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "username", "status", "token_version", "two_factor_secret", "two_factor_enabled", "recovery_codes", "locale"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	} else if user == nil {
+		return nil, errors.NotFound("", "User not found")
+	}
+	return user, nil
+}
+
+// IsRequired Reports whether the user must sign in with two-factor, either enabled by the user or required by
+// one of the enabled roles.
+func (a *TwoFactor) IsRequired(ctx context.Context, user *schema.User, roleIDs []int64) (bool, error) {
+	if user.TwoFactorEnabled {
+		return true, nil
+	}
+	return a.isRequiredByRoles(ctx, roleIDs)
+}
+
+func (a *TwoFactor) isRequiredByRoles(ctx context.Context, roleIDs []int64) (bool, error) {
+	if len(roleIDs) == 0 {
+		return false, nil
+	}
+
+	roleResult, err := a.RoleDAL.Query(ctx, schema.RoleQueryParam{
+		InIDs:  roleIDs,
+		Status: schema.RoleStatusEnabled,
+	}, schema.RoleQueryOptions{
+		QueryOptions: util.QueryOptions{SelectFields: []string{"require_two_factor"}},
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, role := range roleResult.Data {
+		if role.RequireTwoFactor {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// setup Generate a new pending secret for the user, it takes effect after the first code is verified.
+func (a *TwoFactor) setup(ctx context.Context, user *schema.User) (*schema.TwoFactorSetup, error) {
+	issuer := config.C.Util.TwoFactor.Issuer
+	if issuer == "" {
+		issuer = config.C.General.AppName
+	}
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: user.Username,
+		Period:      twoFactorPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := aes.EncryptToBase64([]byte(key.Secret()), a.secretKey())
+	if err != nil {
+		return nil, err
+	}
+
+	user.TwoFactorSecret = encrypted
+	if err := a.UserDAL.Update(ctx, user, "two_factor_secret"); err != nil {
+		return nil, err
+	}
+
+	img, err := key.Image(twoFactorQRCodeSize, twoFactorQRCodeSize)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return &schema.TwoFactorSetup{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// enable Verify the first code against the pending secret, then enable two-factor and generate recovery codes.
+func (a *TwoFactor) enable(ctx context.Context, user *schema.User, code string) ([]string, bool, error) {
+	if user.TwoFactorSecret == "" {
+		return nil, false, errors.BadRequest("", "Two-factor authentication is not set up")
+	}
+
+	if ok, err := a.verifyTOTP(ctx, user, code); err != nil || !ok {
+		return nil, false, err
+	}
+
+	codes, err := a.generateRecoveryCodes(user)
+	if err != nil {
+		return nil, false, err
+	}
+	user.TwoFactorEnabled = true
+	if err := a.UserDAL.Update(ctx, user, "two_factor_enabled", "recovery_codes"); err != nil {
+		return nil, false, err
+	}
+	return codes, true, nil
+}
+
+// verify Check a TOTP code or an unused recovery code of the user, a recovery code is consumed once matched.
+func (a *TwoFactor) verify(ctx context.Context, user *schema.User, code string) (bool, error) {
+	if isTOTPCode(code) {
+		return a.verifyTOTP(ctx, user, code)
+	}
+
+	codeHash := hash.SHA256String(normalizeRecoveryCode(code))
+	for i, v := range user.RecoveryCodes {
+		if v != codeHash {
+			continue
+		}
+
+		user.RecoveryCodes = append(user.RecoveryCodes[:i:i], user.RecoveryCodes[i+1:]...)
+		if err := a.UserDAL.Update(ctx, user, "recovery_codes"); err != nil {
+			return false, err
+		}
+		logging.Context(ctx).Info("Recovery code used", zap.Int("remaining", len(user.RecoveryCodes)))
+		return true, nil
+	}
+	return false, nil
+}
+
+// verifyTOTP Check the TOTP code, a code that has been accepted once can not be used again.
+func (a *TwoFactor) verifyTOTP(ctx context.Context, user *schema.User, code string) (bool, error) {
+	if !isTOTPCode(code) {
+		return false, nil
+	}
+
+	secret, err := aes.DecryptFromBase64(user.TwoFactorSecret, a.secretKey())
+	if err != nil {
+		return false, err
+	}
+
+	counter, ok := a.validateTOTP(string(secret), code, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	key := fmt.Sprintf("used:%d", user.ID)
+	if val, exists, err := a.Cache.Get(ctx, config.CacheNSForTwoFactor, key); err != nil {
+		return false, err
+	} else if exists {
+		if last, _ := strconv.ParseUint(val, 10, 64); counter <= last {
+			return false, nil
+		}
+	}
+
+	// Remember the period until no code of it can be accepted anymore
+	expiration := time.Duration(twoFactorPeriod*(2*config.C.Util.TwoFactor.Skew+2)) * time.Second
+	err = a.Cache.Set(ctx, config.CacheNSForTwoFactor, key, strconv.FormatUint(counter, 10), expiration)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// generateRecoveryCodes Replace the recovery codes of the user, only the hashes are kept.
+func (a *TwoFactor) generateRecoveryCodes(user *schema.User) ([]string, error) {
+	n := config.C.Util.TwoFactor.RecoveryCodes
+	codes := make([]string, 0, n)
+	hashes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		code, err := rand.Random(twoFactorRecoveryCodeLength, rand.LdigitAndLowerCase)
+		if err != nil {
+			return nil, err
+		}
+		half := twoFactorRecoveryCodeLength / 2
+		codes = append(codes, code[:half]+"-"+code[half:])
+		hashes = append(hashes, hash.SHA256String(code))
+	}
+	user.RecoveryCodes = hashes
+	return codes, nil
+}
+
+// Setup Start enrolling two-factor for the current user and return the secret to add to an authenticator app.
+func (a *TwoFactor) Setup(ctx context.Context) (*schema.TwoFactorSetup, error) {
+	if util.FromIsRootUser(ctx) {
+		return nil, errors.BadRequest("", "Root user cannot enable two-factor authentication")
+	}
+
+	user, err := a.getUser(ctx, util.FromUserID(ctx))
+	if err != nil {
+		return nil, err
+	} else if user.TwoFactorEnabled {
+		return nil, errors.BadRequest("", "Two-factor authentication is already enabled")
+	}
+	return a.setup(ctx, user)
+}
+
+// Enable two-factor for the current user with the first code of the pending secret.
+func (a *TwoFactor) Enable(ctx context.Context, formItem *schema.TwoFactorCodeForm) (*schema.TwoFactorRecoveryCodes, error) {
+	user, err := a.getUser(ctx, util.FromUserID(ctx))
+	if err != nil {
+		return nil, err
+	} else if user.TwoFactorEnabled {
+		return nil, errors.BadRequest("", "Two-factor authentication is already enabled")
+	}
+
+	codes, ok, err := a.enable(ctx, user, formItem.Code)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.BadRequest(config.ErrInvalidTwoFactorCode, "Incorrect two-factor code")
+	}
+	logging.Context(ctx).Info("Two-factor authentication enabled")
+	return &schema.TwoFactorRecoveryCodes{Codes: codes}, nil
+}
+
+// Disable two-factor for the current user, not allowed while one of the user's roles requires it.
+func (a *TwoFactor) Disable(ctx context.Context, formItem *schema.TwoFactorCodeForm) error {
+	user, err := a.getUser(ctx, util.FromUserID(ctx))
+	if err != nil {
+		return err
+	} else if !user.TwoFactorEnabled {
+		return errors.BadRequest("", "Two-factor authentication is not enabled")
+	}
+
+	required, err := a.isRequiredByRoles(ctx, util.FromUserCache(ctx).RoleIDs)
+	if err != nil {
+		return err
+	} else if required {
+		return errors.BadRequest("", "Two-factor authentication is required by your roles")
+	}
+
+	if ok, err := a.verify(ctx, user, formItem.Code); err != nil {
+		return err
+	} else if !ok {
+		return errors.BadRequest(config.ErrInvalidTwoFactorCode, "Incorrect two-factor code")
+	}
+
+	user.TwoFactorSecret = ""
+	user.TwoFactorEnabled = false
+	user.RecoveryCodes = nil
+	if err := a.UserDAL.Update(ctx, user, "two_factor_secret", "two_factor_enabled", "recovery_codes"); err != nil {
+		return err
+	}
+	logging.Context(ctx).Info("Two-factor authentication disabled")
+	return nil
+}
+
+// RegenerateRecoveryCodes Replace the recovery codes of the current user, the previous codes become invalid.
+func (a *TwoFactor) RegenerateRecoveryCodes(ctx context.Context, formItem *schema.TwoFactorCodeForm) (*schema.TwoFactorRecoveryCodes, error) {
+	user, err := a.getUser(ctx, util.FromUserID(ctx))
+	if err != nil {
+		return nil, err
+	} else if !user.TwoFactorEnabled {
+		return nil, errors.BadRequest("", "Two-factor authentication is not enabled")
+	}
+
+	if ok, err := a.verifyTOTP(ctx, user, formItem.Code); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.BadRequest(config.ErrInvalidTwoFactorCode, "Incorrect two-factor code")
+	}
+
+	codes, err := a.generateRecoveryCodes(user)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.UserDAL.Update(ctx, user, "recovery_codes"); err != nil {
+		return nil, err
+	}
+	return &schema.TwoFactorRecoveryCodes{Codes: codes}, nil
+}
+
+// CreateChallenge Issue a short-lived challenge token instead of the access token, the login completes when
+// the challenge is verified with a second factor.
+func (a *TwoFactor) CreateChallenge(ctx context.Context, user *schema.User) (*schema.LoginToken, error) {
+	token, err := rand.Random(twoFactorChallengeLength, rand.LdigitAndLetter)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(time.Duration(config.C.Util.TwoFactor.ChallengeExpired) * time.Second)
+	challenge := &twoFactorChallenge{
+		UserID:       user.ID,
+		TokenVersion: user.TokenVersion,
+		ExpiresAt:    expiresAt.Unix(),
+	}
+	if err := a.saveChallenge(ctx, token, challenge); err != nil {
+		return nil, err
+	}
+
+	return &schema.LoginToken{
+		ChallengeToken:     token,
+		ChallengeExpiresAt: challenge.ExpiresAt,
+		TwoFactorSetup:     !user.TwoFactorEnabled,
+	}, nil
+}
+
+func (a *TwoFactor) saveChallenge(ctx context.Context, token string, challenge *twoFactorChallenge) error {
+	expiration := time.Until(time.Unix(challenge.ExpiresAt, 0))
+	if expiration <= 0 {
+		return nil
+	}
+	return a.Cache.Set(ctx, config.CacheNSForTwoFactor, "challenge:"+token, json.MarshalToString(challenge), expiration)
+}
+
+// getChallenge Get the challenge and its user, the challenge is removed from the cache if consume is set.
+func (a *TwoFactor) getChallenge(ctx context.Context, token string, consume bool) (*twoFactorChallenge, *schema.User, error) {
+	invalidChallenge := errors.Unauthorized(config.ErrInvalidTokenID, "Invalid or expired challenge token")
+
+	var (
+		val string
+		ok  bool
+		err error
+	)
+	if consume {
+		val, ok, err = a.Cache.GetAndDelete(ctx, config.CacheNSForTwoFactor, "challenge:"+token)
+	} else {
+		val, ok, err = a.Cache.Get(ctx, config.CacheNSForTwoFactor, "challenge:"+token)
+	}
+	if err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, invalidChallenge
+	}
+
+	challenge := new(twoFactorChallenge)
+	if err := json.Unmarshal([]byte(val), challenge); err != nil {
+		return nil, nil, invalidChallenge
+	}
+
+	user, err := a.UserDAL.Get(ctx, challenge.UserID, schema.UserQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"id", "tenant_id", "dept_id", "username", "status", "token_version", "two_factor_secret",
+				"two_factor_enabled", "recovery_codes", "password_changed_at", "password_expired", "created_at", "locale"},
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	} else if user == nil || user.Status != schema.UserStatusActivated || user.TokenVersion != challenge.TokenVersion {
+		return nil, nil, invalidChallenge
+	}
+	return challenge, user, nil
+}
+
+// SetupChallenge Start enrolling two-factor during login for a user whose roles require it.
+func (a *TwoFactor) SetupChallenge(ctx context.Context, token string) (*schema.TwoFactorSetup, error) {
+	_, user, err := a.getChallenge(ctx, token, false)
+	if err != nil {
+		return nil, err
+	} else if user.TwoFactorEnabled {
+		return nil, errors.BadRequest("", "Two-factor authentication is already enabled")
+	}
+	return a.setup(ctx, user)
+}
+
+// VerifyChallenge Verify the second factor of the challenge and return the user to sign in, the recovery codes
+// are returned if two-factor is enabled by this verification. The challenge is dropped after too many failures.
+func (a *TwoFactor) VerifyChallenge(ctx context.Context, formItem *schema.TwoFactorChallengeForm) (*schema.User, []string, error) {
+	challenge, user, err := a.getChallenge(ctx, formItem.ChallengeToken, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx = logging.NewUserID(ctx, user.ID)
+
+	clientIP := util.FromClientIP(ctx)
+	if err := a.LockoutBIZ.Check(ctx, user.Username, clientIP); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		codes []string
+		ok    bool
+	)
+	if user.TwoFactorEnabled {
+		ok, err = a.verify(ctx, user, formItem.Code)
+	} else {
+		codes, ok, err = a.enable(ctx, user, formItem.Code)
+	}
+	if err != nil {
+		if err := a.saveChallenge(ctx, formItem.ChallengeToken, challenge); err != nil {
+			logging.Context(ctx).Error("Failed to save two-factor challenge", zap.Error(err))
+		}
+		return nil, nil, err
+	} else if !ok {
+		challenge.Attempts++
+		logging.Context(ctx).Warn("Incorrect two-factor code", zap.Int("attempts", challenge.Attempts))
+		if err := a.LockoutBIZ.Fail(ctx, user.Username, clientIP); err != nil {
+			logging.Context(ctx).Error("Failed to record failed login attempt", zap.Error(err))
+		}
+		if challenge.Attempts < config.C.Util.TwoFactor.MaxAttempts {
+			if err := a.saveChallenge(ctx, formItem.ChallengeToken, challenge); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, errors.BadRequest(config.ErrInvalidTwoFactorCode, "Incorrect two-factor code")
+	}
+
+	return user, codes, nil
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != twoFactorCodeLength {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
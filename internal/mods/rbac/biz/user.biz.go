@@ -2,13 +2,12 @@ package biz
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/supermicah/go-framework-admin/internal/config"
+	orgDAL "github.com/supermicah/go-framework-admin/internal/mods/org/dal"
 	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
 	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
-	"github.com/supermicah/go-framework-admin/pkg/cachex"
 	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
 	"github.com/supermicah/go-framework-admin/pkg/errors"
 	"github.com/supermicah/go-framework-admin/pkg/util"
@@ -16,10 +15,17 @@ import (
 
 // User management for RBAC
 type User struct {
-	Cache       cachex.Cacher
-	Trans       *util.Trans
-	UserDAL     *dal.User
-	UserRoleDAL *dal.UserRole
+	Trans              *util.Trans
+	UserDAL            *dal.User
+	UserRoleDAL        *dal.UserRole
+	APIKeyDAL          *dal.APIKey
+	SessionBIZ         *Session
+	LockoutBIZ         *Lockout
+	PasswordBIZ        *Password
+	PasswordHistoryDAL *dal.PasswordHistory
+	UserIdentityDAL    *dal.UserIdentity
+	DeptUserDAL        *orgDAL.DeptUser
+	EventBIZ           *Event
 }
 
 // Query users from the data access object based on the provided parameters and options.
@@ -89,12 +95,19 @@ func (a *User) Create(ctx context.Context, formItem *schema.UserForm) (*schema.U
 		return nil, errors.BadRequest("", "Username already exists")
 	}
 
-	user := &schema.User{
-		CreatedAt: time.Now(),
-	}
-
 	if formItem.Password == "" {
 		formItem.Password = config.C.General.DefaultLoginPwd
+	} else if err := a.PasswordBIZ.Check(ctx, "password", 0, "", formItem.Password); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &schema.User{
+		PasswordChangedAt: &now,
+		CreatedAt:         now,
+	}
+	if userID := util.FromUserID(ctx); userID > 0 {
+		user.CreatedBy = userID
 	}
 
 	if err := formItem.FillTo(user); err != nil {
@@ -105,9 +118,13 @@ func (a *User) Create(ctx context.Context, formItem *schema.UserForm) (*schema.U
 		if err := a.UserDAL.Create(ctx, user); err != nil {
 			return err
 		}
+		if err := a.PasswordBIZ.Record(ctx, user.ID, user.Password); err != nil {
+			return err
+		}
 
 		for _, userRole := range formItem.Roles {
 			userRole.UserID = user.ID
+			userRole.TenantID = user.TenantID
 			userRole.CreatedAt = time.Now()
 			if err := a.UserRoleDAL.Create(ctx, userRole); err != nil {
 				return err
@@ -139,21 +156,45 @@ func (a *User) Update(ctx context.Context, id int64, formItem *schema.UserForm)
 		}
 	}
 
+	oldRoleIDs, err := a.GetRoleIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+	// Tokens issued with the old password, status or roles must not be used anymore
+	revokeTokens := formItem.Password != "" || user.Status != formItem.Status ||
+		!equalRoleIDs(oldRoleIDs, formItem.Roles.Active(time.Now()).ToRoleIDs())
+
+	if formItem.Password != "" {
+		if err := a.PasswordBIZ.Check(ctx, "password", id, user.Password, formItem.Password); err != nil {
+			return err
+		}
+	}
+
 	if err := formItem.FillTo(user); err != nil {
 		return err
 	}
 	user.UpdatedAt = time.Now()
+	if formItem.Password != "" {
+		user.PasswordChangedAt = &user.UpdatedAt
+		user.PasswordExpired = false
+	}
 
-	return a.Trans.Exec(ctx, func(ctx context.Context) error {
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
 		if err := a.UserDAL.Update(ctx, user); err != nil {
 			return err
 		}
+		if formItem.Password != "" {
+			if err := a.PasswordBIZ.Record(ctx, id, user.Password); err != nil {
+				return err
+			}
+		}
 
 		if err := a.UserRoleDAL.DeleteByUserID(ctx, id); err != nil {
 			return err
 		}
 		for _, userRole := range formItem.Roles {
 			userRole.UserID = user.ID
+			userRole.TenantID = user.TenantID
 			if userRole.CreatedAt.IsZero() {
 				userRole.CreatedAt = time.Now()
 			}
@@ -163,8 +204,19 @@ func (a *User) Update(ctx context.Context, id int64, formItem *schema.UserForm)
 			}
 		}
 
-		return a.Cache.Delete(ctx, config.CacheNSForUser, fmt.Sprintf("%d", id))
+		if revokeTokens {
+			return a.RevokeTokens(ctx, id)
+		}
+		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	if revokeTokens {
+		return a.InvalidateTokens(ctx, id)
+	}
+	return a.EventBIZ.UserChanged(ctx, id)
 }
 
 // Delete the specified user from the data access object.
@@ -176,41 +228,111 @@ func (a *User) Delete(ctx context.Context, id int64) error {
 		return errors.NotFound("", "User not found")
 	}
 
-	return a.Trans.Exec(ctx, func(ctx context.Context) error {
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
 		if err := a.UserDAL.Delete(ctx, id); err != nil {
 			return err
 		}
 		if err := a.UserRoleDAL.DeleteByUserID(ctx, id); err != nil {
 			return err
 		}
-		return a.Cache.Delete(ctx, config.CacheNSForUser, fmt.Sprintf("%d", id))
+		if err := a.APIKeyDAL.DeleteByUserID(ctx, id); err != nil {
+			return err
+		}
+		if err := a.PasswordHistoryDAL.DeleteByUserID(ctx, id); err != nil {
+			return err
+		}
+		if err := a.UserIdentityDAL.DeleteByUserID(ctx, id); err != nil {
+			return err
+		}
+		return a.DeptUserDAL.DeleteByUserID(ctx, id)
 	})
+	if err != nil {
+		return err
+	}
+
+	// The sessions live outside the database, they are only revoked once the deletion is committed
+	if err := a.SessionBIZ.DeleteAll(ctx, id, ""); err != nil {
+		return err
+	}
+	return a.EventBIZ.UserChanged(ctx, id)
 }
 
-func (a *User) ResetPassword(ctx context.Context, id int64) error {
-	exists, err := a.UserDAL.Exists(ctx, id)
+// ResetPassword Set the password of the specified user to the given one or the default password, the user must
+// change it at next login.
+func (a *User) ResetPassword(ctx context.Context, id int64, formItem *schema.ResetPasswordForm) error {
+	user, err := a.UserDAL.Get(ctx, id, schema.UserQueryOptions{
+		QueryOptions: util.QueryOptions{SelectFields: []string{"password"}},
+	})
 	if err != nil {
 		return err
-	} else if !exists {
+	} else if user == nil {
 		return errors.NotFound("", "User not found")
 	}
 
-	hashPass, err := hash.GeneratePassword(config.C.General.DefaultLoginPwd)
+	pwd := formItem.Password
+	if pwd == "" {
+		pwd = config.C.General.DefaultLoginPwd
+	} else if err := a.PasswordBIZ.Check(ctx, "password", id, user.Password, pwd); err != nil {
+		return err
+	}
+
+	hashPass, err := hash.GeneratePassword(pwd)
 	if err != nil {
 		return errors.BadRequest("", "Failed to generate hash password: %s", err.Error())
 	}
 
-	return a.Trans.Exec(ctx, func(ctx context.Context) error {
-		if err := a.UserDAL.UpdatePasswordByID(ctx, id, hashPass); err != nil {
+	err = a.Trans.Exec(ctx, func(ctx context.Context) error {
+		if err := a.UserDAL.UpdatePasswordByID(ctx, id, hashPass, true); err != nil {
 			return err
 		}
-		return nil
+		if err := a.PasswordBIZ.Record(ctx, id, hashPass); err != nil {
+			return err
+		}
+		return a.RevokeTokens(ctx, id)
 	})
+	if err != nil {
+		return err
+	}
+	return a.InvalidateTokens(ctx, id)
+}
+
+// Unlock Remove the login lock of the specified user caused by failed attempts.
+func (a *User) Unlock(ctx context.Context, id int64) error {
+	if id == config.C.General.Root.ID {
+		return a.LockoutBIZ.Unlock(ctx, config.C.General.Root.Username)
+	}
+
+	user, err := a.UserDAL.Get(ctx, id, schema.UserQueryOptions{
+		QueryOptions: util.QueryOptions{SelectFields: []string{"username"}},
+	})
+	if err != nil {
+		return err
+	} else if user == nil {
+		return errors.NotFound("", "User not found")
+	}
+	return a.LockoutBIZ.Unlock(ctx, user.Username)
 }
 
+// RevokeTokens Invalidate every token issued to the user by bumping the token version, InvalidateTokens must be
+// called once the new version is committed.
+func (a *User) RevokeTokens(ctx context.Context, id int64) error {
+	return a.UserDAL.IncrTokenVersion(ctx, id)
+}
+
+// InvalidateTokens Drop the sessions and the cached token version of the user, it must be called after the changes
+// are committed, otherwise a concurrent request could cache the old version again.
+func (a *User) InvalidateTokens(ctx context.Context, id int64) error {
+	if err := a.SessionBIZ.DeleteAll(ctx, id, ""); err != nil {
+		return err
+	}
+	return a.EventBIZ.UserChanged(ctx, id)
+}
+
+// GetRoleIDs The roles granted to the user now, the assignments outside their validity window are ignored.
 func (a *User) GetRoleIDs(ctx context.Context, id int64) ([]int64, error) {
 	userRoleResult, err := a.UserRoleDAL.Query(ctx, schema.UserRoleQueryParam{
 		UserID: id,
+		Active: true,
 	}, schema.UserRoleQueryOptions{
 		QueryOptions: util.QueryOptions{
 			SelectFields: []string{"role_id"},
@@ -221,3 +343,38 @@ func (a *User) GetRoleIDs(ctx context.Context, id int64) ([]int64, error) {
 	}
 	return userRoleResult.Data.ToRoleIDs(), nil
 }
+
+// GetRoleIDsUntil The roles granted to the user now and the time they change next as an assignment starts or expires,
+// the time is zero if they never change.
+func (a *User) GetRoleIDsUntil(ctx context.Context, id int64) ([]int64, time.Time, error) {
+	userRoleResult, err := a.UserRoleDAL.Query(ctx, schema.UserRoleQueryParam{
+		UserID: id,
+	}, schema.UserRoleQueryOptions{
+		QueryOptions: util.QueryOptions{
+			SelectFields: []string{"role_id", "start_at", "expire_at"},
+		},
+	})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	now := time.Now()
+	return userRoleResult.Data.Active(now).ToRoleIDs(), userRoleResult.Data.NextChangeAt(now), nil
+}
+
+// equalRoleIDs Reports whether both lists hold the same roles regardless of order.
+func equalRoleIDs(a, b []int64) bool {
+	set := make(map[int64]struct{}, len(a))
+	for _, id := range a {
+		set[id] = struct{}{}
+	}
+
+	other := make(map[int64]struct{}, len(b))
+	for _, id := range b {
+		if _, ok := set[id]; !ok {
+			return false
+		}
+		other[id] = struct{}{}
+	}
+	return len(set) == len(other)
+}
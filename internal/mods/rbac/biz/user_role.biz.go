@@ -0,0 +1,122 @@
+package biz
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
+	"github.com/supermicah/go-framework-admin/pkg/cachex"
+	"github.com/supermicah/go-framework-admin/pkg/logging"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// UserRoleSweeper Periodically purges the expired role assignments of the users, and drops the cached roles of the
+// users whose assignments expired or started since the last sweep. The time of the last sweep is kept in the cache,
+// so the assignments started while no instance was running are handled by the first sweep.
+type UserRoleSweeper struct {
+	done        chan struct{} `wire:"-"`
+	Cache       cachex.Cacher
+	UserRoleDAL *dal.UserRole
+	EventBIZ    *Event
+}
+
+func (a *UserRoleSweeper) Start(ctx context.Context) error {
+	interval := config.C.Dictionary.UserRoleSweepInterval
+	if interval <= 0 {
+		return nil
+	}
+
+	a.done = make(chan struct{})
+	go a.autoSweep(ctx, time.NewTicker(time.Duration(interval)*time.Second), a.done)
+	return nil
+}
+
+func (a *UserRoleSweeper) autoSweep(ctx context.Context, ticker *time.Ticker, done <-chan struct{}) {
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			since, err := a.lastSweptAt(ctx)
+			if err != nil {
+				logging.Context(ctx).Error("Failed to get the last sweep time", zap.Error(err))
+				continue
+			}
+			if err := a.Sweep(ctx, since, now); err != nil {
+				logging.Context(ctx).Error("Failed to sweep user roles", zap.Error(err))
+				continue
+			}
+			err = a.Cache.Set(ctx, config.CacheNSForRole, config.CacheKeyForUserRoleSweep, strconv.FormatInt(now.UnixNano(), 10))
+			if err != nil {
+				logging.Context(ctx).Error("Failed to set the last sweep time", zap.Error(err))
+			}
+		}
+	}
+}
+
+// lastSweptAt The time of the last sweep by any instance, zero if never swept so all started assignments are handled.
+func (a *UserRoleSweeper) lastSweptAt(ctx context.Context) (time.Time, error) {
+	val, ok, err := a.Cache.Get(ctx, config.CacheNSForRole, config.CacheKeyForUserRoleSweep)
+	if err != nil {
+		return time.Time{}, err
+	} else if !ok {
+		return time.Time{}, nil
+	}
+
+	nsec, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return time.Time{}, nil
+	}
+	return time.Unix(0, nsec), nil
+}
+
+// Sweep Delete the assignments expired at the time, and drop the cached roles of their users and of the users whose
+// assignments started after since.
+func (a *UserRoleSweeper) Sweep(ctx context.Context, since, now time.Time) error {
+	// The assignments of all the tenants are swept
+	ctx = util.NewCrossTenant(ctx)
+
+	expired, err := a.UserRoleDAL.QueryExpired(ctx, now)
+	if err != nil {
+		return err
+	}
+	started, err := a.UserRoleDAL.QueryStarted(ctx, since, now)
+	if err != nil {
+		return err
+	}
+
+	if len(expired) > 0 {
+		ids := make([]int64, 0, len(expired))
+		for _, item := range expired {
+			ids = append(ids, item.ID)
+		}
+		if err := a.UserRoleDAL.DeleteByIDs(ctx, ids); err != nil {
+			return err
+		}
+		logging.Context(ctx).Info("Purged expired user roles", zap.Int("count", len(ids)))
+	}
+
+	var userIDs []int64
+	userIDMapper := make(map[int64]struct{})
+	for _, item := range append(expired, started...) {
+		if _, ok := userIDMapper[item.UserID]; ok {
+			continue
+		}
+		userIDMapper[item.UserID] = struct{}{}
+		userIDs = append(userIDs, item.UserID)
+	}
+	return a.EventBIZ.UserChanged(ctx, userIDs...)
+}
+
+func (a *UserRoleSweeper) Release(ctx context.Context) error {
+	if a.done != nil {
+		close(a.done)
+		a.done = nil
+	}
+	return nil
+}
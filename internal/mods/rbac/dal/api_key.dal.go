@@ -0,0 +1,114 @@
+package dal
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// GetAPIKeyDB Get API key storage instance
+func GetAPIKeyDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDB(ctx, defDB).Model(new(schema.APIKey))
+}
+
+// APIKey Personal access tokens for RBAC
+type APIKey struct {
+	DB *gorm.DB
+}
+
+// Query API keys from the database based on the provided parameters and options.
+func (a *APIKey) Query(ctx context.Context, params schema.APIKeyQueryParam, opts ...schema.APIKeyQueryOptions) (*schema.APIKeyQueryResult, error) {
+	var opt schema.APIKeyQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	db := GetAPIKeyDB(ctx, a.DB)
+	if v := params.UserID; v > 0 {
+		db = db.Where("user_id = ?", v)
+	}
+	if v := params.LikeName; len(v) > 0 {
+		db = db.Where("name LIKE ?", "%"+v+"%")
+	}
+
+	var list schema.APIKeys
+	pageResult, err := util.WrapPageQuery(ctx, db, params.PaginationParam, opt.QueryOptions, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	queryResult := &schema.APIKeyQueryResult{
+		PageResult: pageResult,
+		Data:       list,
+	}
+	return queryResult, nil
+}
+
+// Get the specified API key from the database.
+func (a *APIKey) Get(ctx context.Context, id int64, opts ...schema.APIKeyQueryOptions) (*schema.APIKey, error) {
+	var opt schema.APIKeyQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	item := new(schema.APIKey)
+	ok, err := util.FindOne(ctx, GetAPIKeyDB(ctx, a.DB).Where("id=?", id), opt.QueryOptions, item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+	return item, nil
+}
+
+// GetBySecretHash Get the API key by the hash of the plain key.
+func (a *APIKey) GetBySecretHash(ctx context.Context, secretHash string, opts ...schema.APIKeyQueryOptions) (*schema.APIKey, error) {
+	var opt schema.APIKeyQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	item := new(schema.APIKey)
+	ok, err := util.FindOne(ctx, GetAPIKeyDB(ctx, a.DB).Where("secret_hash=?", secretHash), opt.QueryOptions, item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+	return item, nil
+}
+
+// Create a new API key.
+func (a *APIKey) Create(ctx context.Context, item *schema.APIKey) error {
+	result := GetAPIKeyDB(ctx, a.DB).Create(item)
+	return errors.WithStack(result.Error)
+}
+
+// Update the specified API key in the database.
+func (a *APIKey) Update(ctx context.Context, item *schema.APIKey) error {
+	result := GetAPIKeyDB(ctx, a.DB).Where("id=?", item.ID).Select("*").Omit("created_at").Updates(item)
+	return errors.WithStack(result.Error)
+}
+
+// UpdateLastUsedAt Record the time the API key was last used.
+func (a *APIKey) UpdateLastUsedAt(ctx context.Context, id int64, lastUsedAt time.Time) error {
+	result := GetAPIKeyDB(ctx, a.DB).Where("id=?", id).UpdateColumn("last_used_at", lastUsedAt)
+	return errors.WithStack(result.Error)
+}
+
+// Delete the specified API key from the database.
+func (a *APIKey) Delete(ctx context.Context, id int64) error {
+	result := GetAPIKeyDB(ctx, a.DB).Where("id=?", id).Delete(new(schema.APIKey))
+	return errors.WithStack(result.Error)
+}
+
+// DeleteByUserID Delete all API keys of the specified user.
+func (a *APIKey) DeleteByUserID(ctx context.Context, userID int64) error {
+	result := GetAPIKeyDB(ctx, a.DB).Where("user_id=?", userID).Delete(new(schema.APIKey))
+	return errors.WithStack(result.Error)
+}
@@ -0,0 +1,248 @@
+package dal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/casbin/casbin/v2/model"
+	"gorm.io/gorm"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// errNotImplemented Casbin ignores the errors of adapters with this message
+var errNotImplemented = errors.Errorf("not implemented")
+
+// CasbinAdapter Casbin adapter backed by the role_menu, menu_resource, role_resource and role_parent tables. A role is
+// granted the resources of its enabled menus and of their parents and its own resources, and inherits the policies of
+// its enabled parent roles. The resources are allowed or denied by their effect. The tables are written by the role and
+// menu management, so the adapter only loads policies and the enforcer is kept up to date by the callers. The tenant of
+// a role is the domain of its policies.
+type CasbinAdapter struct {
+	DB *gorm.DB
+}
+
+// QueryPolicies Query the policies (role ID, tenant ID, path, method, effect) of the enabled roles, all the roles if
+// none is specified.
+func (a *CasbinAdapter) QueryPolicies(ctx context.Context, roleIDs ...int64) ([][]string, error) {
+	roleTable := new(schema.Role).TableName()
+	menuTable := new(schema.Menu).TableName()
+	roleMenuTable := new(schema.RoleMenu).TableName()
+
+	db := GetRoleMenuDB(ctx, a.DB).
+		Select(fmt.Sprintf("%s.role_id, %s.tenant_id, %s.menu_id, %s.parent_path",
+			roleMenuTable, roleTable, roleMenuTable, menuTable)).
+		Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.role_id", roleTable, roleTable, roleMenuTable)).
+		Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.menu_id", menuTable, menuTable, roleMenuTable)).
+		Where(fmt.Sprintf("%s.status = ? AND %s.status = ?", roleTable, menuTable),
+			schema.RoleStatusEnabled, schema.MenuStatusEnabled)
+	if len(roleIDs) > 0 {
+		db = db.Where(fmt.Sprintf("%s.role_id IN ?", roleMenuTable), roleIDs)
+	}
+
+	var roleMenus []struct {
+		RoleID     int64
+		TenantID   int64
+		MenuID     int64
+		ParentPath string
+	}
+	if err := db.Scan(&roleMenus).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	roleResourceTable := new(schema.RoleResource).TableName()
+	resDB := GetRoleResourceDB(ctx, a.DB).
+		Select(fmt.Sprintf("%s.role_id, %s.tenant_id, %s.method, %s.path, %s.effect",
+			roleResourceTable, roleTable, roleResourceTable, roleResourceTable, roleResourceTable)).
+		Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.role_id", roleTable, roleTable, roleResourceTable)).
+		Where(fmt.Sprintf("%s.status = ?", roleTable), schema.RoleStatusEnabled)
+	if len(roleIDs) > 0 {
+		resDB = resDB.Where(fmt.Sprintf("%s.role_id IN ?", roleResourceTable), roleIDs)
+	}
+
+	var roleResources []struct {
+		RoleID   int64
+		TenantID int64
+		Method   string
+		Path     string
+		Effect   string
+	}
+	if err := resDB.Scan(&roleResources).Error; err != nil {
+		return nil, errors.WithStack(err)
+	} else if len(roleMenus) == 0 && len(roleResources) == 0 {
+		return nil, nil
+	}
+
+	roleMenuIDs := make(map[int64]map[int64]struct{})
+	roleTenantIDs := make(map[int64]string)
+	menuIDMapper := make(map[int64]struct{})
+	for _, item := range roleMenus {
+		roleTenantIDs[item.RoleID] = strconv.FormatInt(item.TenantID, 10)
+		menuIDs, ok := roleMenuIDs[item.RoleID]
+		if !ok {
+			menuIDs = make(map[int64]struct{})
+			roleMenuIDs[item.RoleID] = menuIDs
+		}
+		menuIDs[item.MenuID] = struct{}{}
+		menuIDMapper[item.MenuID] = struct{}{}
+		for _, pid := range strings.Split(item.ParentPath, util.TreePathDelimiter) {
+			if parentID, err := strconv.ParseInt(pid, 10, 64); err == nil {
+				menuIDs[parentID] = struct{}{}
+				menuIDMapper[parentID] = struct{}{}
+			}
+		}
+	}
+
+	menuResources := make(map[int64]schema.MenuResources)
+	if len(menuIDMapper) > 0 {
+		menuIDs := make([]int64, 0, len(menuIDMapper))
+		for id := range menuIDMapper {
+			menuIDs = append(menuIDs, id)
+		}
+		var resources schema.MenuResources
+		err := GetMenuResourceDB(ctx, a.DB).Select("menu_id", "method", "path", "effect").
+			Where("menu_id IN ?", menuIDs).Find(&resources).Error
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for _, res := range resources {
+			menuResources[res.MenuID] = append(menuResources[res.MenuID], res)
+		}
+	}
+
+	ownResources := make(map[int64]schema.MenuResources)
+	for _, item := range roleResources {
+		roleTenantIDs[item.RoleID] = strconv.FormatInt(item.TenantID, 10)
+		ownResources[item.RoleID] = append(ownResources[item.RoleID], &schema.MenuResource{
+			Method: item.Method,
+			Path:   item.Path,
+			Effect: item.Effect,
+		})
+	}
+
+	// Policies are sorted by role to be stable across loads
+	sortedRoleIDs := make([]int64, 0, len(roleTenantIDs))
+	for roleID := range roleTenantIDs {
+		sortedRoleIDs = append(sortedRoleIDs, roleID)
+	}
+	sort.Slice(sortedRoleIDs, func(i, j int) bool { return sortedRoleIDs[i] < sortedRoleIDs[j] })
+
+	var policies [][]string
+	for _, roleID := range sortedRoleIDs {
+		sub, dom := strconv.FormatInt(roleID, 10), roleTenantIDs[roleID]
+		exists := make(map[string]struct{})
+		var rolePolicies [][]string
+		addPolicies := func(resources schema.MenuResources) {
+			for _, res := range resources {
+				effect := res.Effect
+				if effect == "" {
+					effect = schema.ResourceEffectAllow
+				}
+				key := res.Method + " " + res.Path + " " + effect
+				if _, ok := exists[key]; ok {
+					continue
+				}
+				exists[key] = struct{}{}
+				rolePolicies = append(rolePolicies, []string{sub, dom, res.Path, res.Method, effect})
+			}
+		}
+		for menuID := range roleMenuIDs[roleID] {
+			addPolicies(menuResources[menuID])
+		}
+		addPolicies(ownResources[roleID])
+
+		sort.Slice(rolePolicies, func(i, j int) bool {
+			for k := 2; k < len(rolePolicies[i]); k++ {
+				if rolePolicies[i][k] != rolePolicies[j][k] {
+					return rolePolicies[i][k] < rolePolicies[j][k]
+				}
+			}
+			return false
+		})
+		policies = append(policies, rolePolicies...)
+	}
+	return policies, nil
+}
+
+// QueryGroupingPolicies Query the inheritance (role ID, parent role ID, tenant ID) between the enabled roles, all of them if no
+// role is specified, otherwise the inheritance from or to the roles.
+func (a *CasbinAdapter) QueryGroupingPolicies(ctx context.Context, roleIDs ...int64) ([][]string, error) {
+	roleTable := new(schema.Role).TableName()
+	roleParentTable := new(schema.RoleParent).TableName()
+
+	db := GetRoleParentDB(ctx, a.DB).
+		Select(fmt.Sprintf("%s.role_id, %s.parent_id, r.tenant_id", roleParentTable, roleParentTable)).
+		Joins(fmt.Sprintf("JOIN %s AS r ON r.id = %s.role_id", roleTable, roleParentTable)).
+		Joins(fmt.Sprintf("JOIN %s AS p ON p.id = %s.parent_id", roleTable, roleParentTable)).
+		Where("r.status = ? AND p.status = ?", schema.RoleStatusEnabled, schema.RoleStatusEnabled)
+	if len(roleIDs) > 0 {
+		db = db.Where(fmt.Sprintf("(%s.role_id IN ? OR %s.parent_id IN ?)", roleParentTable, roleParentTable),
+			roleIDs, roleIDs)
+	}
+
+	var roleParents []struct {
+		RoleID   int64
+		ParentID int64
+		TenantID int64
+	}
+	if err := db.Order(fmt.Sprintf("%s.role_id, %s.parent_id", roleParentTable, roleParentTable)).
+		Scan(&roleParents).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	policies := make([][]string, 0, len(roleParents))
+	for _, item := range roleParents {
+		policies = append(policies, []string{
+			strconv.FormatInt(item.RoleID, 10),
+			strconv.FormatInt(item.ParentID, 10),
+			strconv.FormatInt(item.TenantID, 10),
+		})
+	}
+	return policies, nil
+}
+
+// LoadPolicy Load the policies of all the enabled roles.
+func (a *CasbinAdapter) LoadPolicy(m model.Model) error {
+	ctx := context.Background()
+	policies, err := a.QueryPolicies(ctx)
+	if err != nil {
+		return err
+	}
+	for _, rule := range policies {
+		m.AddPolicy("p", "p", rule)
+	}
+
+	groupingPolicies, err := a.QueryGroupingPolicies(ctx)
+	if err != nil {
+		return err
+	}
+	for _, rule := range groupingPolicies {
+		m.AddPolicy("g", "g", rule)
+	}
+	return nil
+}
+
+// SavePolicy Policies are saved by the role and menu management.
+func (a *CasbinAdapter) SavePolicy(model.Model) error {
+	return errNotImplemented
+}
+
+// AddPolicy Policies are saved by the role and menu management.
+func (a *CasbinAdapter) AddPolicy(sec string, ptype string, rule []string) error {
+	return errNotImplemented
+}
+
+// RemovePolicy Policies are saved by the role and menu management.
+func (a *CasbinAdapter) RemovePolicy(sec string, ptype string, rule []string) error {
+	return errNotImplemented
+}
+
+// RemoveFilteredPolicy Policies are saved by the role and menu management.
+func (a *CasbinAdapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
+	return errNotImplemented
+}
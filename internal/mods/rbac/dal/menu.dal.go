@@ -57,6 +57,10 @@ func (a *Menu) Query(ctx context.Context, params schema.MenuQueryParam, opts ...
 		roleMenuQuery := GetRoleMenuDB(ctx, a.DB).Where("role_id = ?", v).Select("menu_id")
 		db = db.Where("id IN (?)", roleMenuQuery)
 	}
+	if v := params.InRoleIDs; len(v) > 0 {
+		roleMenuQuery := GetRoleMenuDB(ctx, a.DB).Where("role_id IN (?)", v).Select("menu_id")
+		db = db.Where("id IN (?)", roleMenuQuery)
+	}
 
 	var list schema.Menus
 	pageResult, err := util.WrapPageQuery(ctx, db, params.PaginationParam, opt.QueryOptions, &list)
@@ -163,6 +167,48 @@ func (a *Menu) UpdateParentPath(ctx context.Context, id int64, parentPath string
 	return errors.WithStack(result.Error)
 }
 
+// QueryIDsByParentID Query the IDs of the children of the specified parent (zero for the root menus) in their order.
+func (a *Menu) QueryIDsByParentID(ctx context.Context, parentID int64) ([]int64, error) {
+	var ids []int64
+	result := GetMenuDB(ctx, a.DB).Where("parent_id=?", parentID).Order("sequence DESC, created_at DESC").Pluck("id", &ids)
+	if err := result.Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ids, nil
+}
+
+// ReplaceParentPath Replaces the prefix of the parent path of all menus whose parent path starts with the old path in
+// a single statement. Only the prefix is replaced, the old path can also occur inside the path (e.g. "5." in "5.15.").
+func (a *Menu) ReplaceParentPath(ctx context.Context, oldPath, newPath string) error {
+	db := GetMenuDB(ctx, a.DB)
+	expr := "? || SUBSTR(parent_path, ?)"
+	if db.Dialector.Name() == "mysql" {
+		expr = "CONCAT(?, SUBSTR(parent_path, ?))"
+	}
+	result := db.Where("parent_path LIKE ?", oldPath+"%").
+		Update("parent_path", gorm.Expr(expr, newPath, len(oldPath)+1))
+	return errors.WithStack(result.Error)
+}
+
+// UpdateSequences Renumbers the sequences of the menus in a single statement, the first menu gets the highest one.
+func (a *Menu) UpdateSequences(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	args := make([]interface{}, 0, len(ids)*2)
+	sb.WriteString("CASE id")
+	for i, id := range ids {
+		sb.WriteString(" WHEN ? THEN ?")
+		args = append(args, id, len(ids)-i)
+	}
+	sb.WriteString(" END")
+
+	result := GetMenuDB(ctx, a.DB).Where("id IN ?", ids).Update("sequence", gorm.Expr(sb.String(), args...))
+	return errors.WithStack(result.Error)
+}
+
 // UpdateStatusByParentPath Updates the status of all menus whose parent path starts with the provided parent path.
 func (a *Menu) UpdateStatusByParentPath(ctx context.Context, parentPath, status string) error {
 	result := GetMenuDB(ctx, a.DB).Where("parent_path like ?", parentPath+"%").Update("status", status)
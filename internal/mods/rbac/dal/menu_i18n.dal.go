@@ -0,0 +1,67 @@
+package dal
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// GetMenuI18nDB Get menu translation storage instance
+func GetMenuI18nDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDB(ctx, defDB).Model(new(schema.MenuI18n))
+}
+
+// MenuI18n Translations of the menus for RBAC
+type MenuI18n struct {
+	DB *gorm.DB
+}
+
+// Query menu translations from the database based on the provided parameters and options.
+func (a *MenuI18n) Query(ctx context.Context, params schema.MenuI18nQueryParam, opts ...schema.MenuI18nQueryOptions) (*schema.MenuI18nQueryResult, error) {
+	var opt schema.MenuI18nQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	db := GetMenuI18nDB(ctx, a.DB)
+	if v := params.MenuIDs; len(v) > 0 {
+		db = db.Where("menu_id IN ?", v)
+	}
+	if v := params.Locale; len(v) > 0 {
+		db = db.Where("locale = ?", v)
+	}
+
+	var list schema.MenuI18ns
+	pageResult, err := util.WrapPageQuery(ctx, db, params.PaginationParam, opt.QueryOptions, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	queryResult := &schema.MenuI18nQueryResult{
+		PageResult: pageResult,
+		Data:       list,
+	}
+	return queryResult, nil
+}
+
+// Create a new menu translation.
+func (a *MenuI18n) Create(ctx context.Context, item *schema.MenuI18n) error {
+	result := GetMenuI18nDB(ctx, a.DB).Create(item)
+	return errors.WithStack(result.Error)
+}
+
+// Update the specified menu translation in the database.
+func (a *MenuI18n) Update(ctx context.Context, item *schema.MenuI18n) error {
+	result := GetMenuI18nDB(ctx, a.DB).Where("id=?", item.ID).Select("*").Omit("created_at").Updates(item)
+	return errors.WithStack(result.Error)
+}
+
+// DeleteByMenuID Deletes the translations of the menu.
+func (a *MenuI18n) DeleteByMenuID(ctx context.Context, menuID int64) error {
+	result := GetMenuI18nDB(ctx, a.DB).Where("menu_id=?", menuID).Delete(new(schema.MenuI18n))
+	return errors.WithStack(result.Error)
+}
@@ -34,6 +34,15 @@ func (a *MenuResource) Query(ctx context.Context, params schema.MenuResourceQuer
 	if v := params.MenuIDs; len(v) > 0 {
 		db = db.Where("menu_id IN ?", v)
 	}
+	if v := params.Method; len(v) > 0 {
+		db = db.Where("method = ?", v)
+	}
+	if v := params.Path; len(v) > 0 {
+		db = db.Where("path = ?", v)
+	}
+	if v := params.Effect; len(v) > 0 {
+		db = db.Where("effect = ?", v)
+	}
 
 	var list schema.MenuResources
 	pageResult, err := util.WrapPageQuery(ctx, db, params.PaginationParam, opt.QueryOptions, &list)
@@ -0,0 +1,57 @@
+package dal
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// GetPasswordHistoryDB Get password history storage instance
+func GetPasswordHistoryDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDB(ctx, defDB).Model(new(schema.PasswordHistory))
+}
+
+// PasswordHistory Previous passwords of users
+type PasswordHistory struct {
+	DB *gorm.DB
+}
+
+// QueryLatest Query the latest password hashes of the specified user, newest first.
+func (a *PasswordHistory) QueryLatest(ctx context.Context, userID int64, limit int) (schema.PasswordHistories, error) {
+	var list schema.PasswordHistories
+	result := GetPasswordHistoryDB(ctx, a.DB).Where("user_id=?", userID).Order("id DESC").Limit(limit).Find(&list)
+	if err := result.Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return list, nil
+}
+
+// Create a new password history.
+func (a *PasswordHistory) Create(ctx context.Context, item *schema.PasswordHistory) error {
+	result := GetPasswordHistoryDB(ctx, a.DB).Create(item)
+	return errors.WithStack(result.Error)
+}
+
+// DeleteOlder Delete the password history of the specified user except the latest ones.
+func (a *PasswordHistory) DeleteOlder(ctx context.Context, userID int64, keep int) error {
+	latest, err := a.QueryLatest(ctx, userID, keep)
+	if err != nil {
+		return err
+	} else if len(latest) < keep {
+		return nil
+	}
+
+	result := GetPasswordHistoryDB(ctx, a.DB).Where("user_id=? AND id<?", userID, latest[len(latest)-1].ID).
+		Delete(new(schema.PasswordHistory))
+	return errors.WithStack(result.Error)
+}
+
+// DeleteByUserID Delete the password history of the specified user.
+func (a *PasswordHistory) DeleteByUserID(ctx context.Context, userID int64) error {
+	result := GetPasswordHistoryDB(ctx, a.DB).Where("user_id=?", userID).Delete(new(schema.PasswordHistory))
+	return errors.WithStack(result.Error)
+}
@@ -31,6 +31,12 @@ func (a *RoleMenu) Query(ctx context.Context, params schema.RoleMenuQueryParam,
 	if v := params.RoleID; v > 0 {
 		db = db.Where("role_id = ?", v)
 	}
+	if v := params.InRoleIDs; len(v) > 0 {
+		db = db.Where("role_id IN (?)", v)
+	}
+	if v := params.InMenuIDs; len(v) > 0 {
+		db = db.Where("menu_id IN (?)", v)
+	}
 
 	var list schema.RoleMenus
 	pageResult, err := util.WrapPageQuery(ctx, db, params.PaginationParam, opt.QueryOptions, &list)
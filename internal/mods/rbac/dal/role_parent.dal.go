@@ -0,0 +1,96 @@
+package dal
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// GetRoleParentDB Get role parent storage instance
+func GetRoleParentDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDB(ctx, defDB).Model(new(schema.RoleParent))
+}
+
+// RoleParent Role inheritance for RBAC
+type RoleParent struct {
+	DB *gorm.DB
+}
+
+// Query role parents from the database based on the provided parameters and options.
+func (a *RoleParent) Query(ctx context.Context, params schema.RoleParentQueryParam, opts ...schema.RoleParentQueryOptions) (*schema.RoleParentQueryResult, error) {
+	var opt schema.RoleParentQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	db := GetRoleParentDB(ctx, a.DB)
+	if v := params.RoleID; v > 0 {
+		db = db.Where("role_id = ?", v)
+	}
+	if v := params.InRoleIDs; len(v) > 0 {
+		db = db.Where("role_id IN (?)", v)
+	}
+
+	var list schema.RoleParents
+	pageResult, err := util.WrapPageQuery(ctx, db, params.PaginationParam, opt.QueryOptions, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	queryResult := &schema.RoleParentQueryResult{
+		PageResult: pageResult,
+		Data:       list,
+	}
+	return queryResult, nil
+}
+
+// QueryAncestorIDs Query the roles inherited by the roles directly or indirectly. If the status is not empty, the
+// inheritance stops at the roles of another status.
+func (a *RoleParent) QueryAncestorIDs(ctx context.Context, roleIDs []int64, status string) ([]int64, error) {
+	var ancestorIDs []int64
+	visited := make(map[int64]struct{})
+	for ids := roleIDs; len(ids) > 0; {
+		db := GetRoleParentDB(ctx, a.DB).Where("role_id IN (?)", ids)
+		if status != "" {
+			db = db.Where("parent_id IN (?)", GetRoleDB(ctx, a.DB).Where("status = ?", status).Select("id"))
+		}
+
+		var parentIDs []int64
+		if err := db.Pluck("parent_id", &parentIDs).Error; err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		ids = nil
+		for _, parentID := range parentIDs {
+			if _, ok := visited[parentID]; ok {
+				continue
+			}
+			visited[parentID] = struct{}{}
+			ancestorIDs = append(ancestorIDs, parentID)
+			ids = append(ids, parentID)
+		}
+	}
+	return ancestorIDs, nil
+}
+
+// Create a new role parent.
+func (a *RoleParent) Create(ctx context.Context, item *schema.RoleParent) error {
+	result := GetRoleParentDB(ctx, a.DB).Create(item)
+	return errors.WithStack(result.Error)
+}
+
+// DeleteByRoleID Deletes the parents of the role.
+func (a *RoleParent) DeleteByRoleID(ctx context.Context, roleID int64) error {
+	result := GetRoleParentDB(ctx, a.DB).Where("role_id=?", roleID).Delete(new(schema.RoleParent))
+	return errors.WithStack(result.Error)
+}
+
+// DeleteByParentID Deletes the inheritance of the parent role.
+func (a *RoleParent) DeleteByParentID(ctx context.Context, parentID int64) error {
+	result := GetRoleParentDB(ctx, a.DB).Where("parent_id=?", parentID).Delete(new(schema.RoleParent))
+	return errors.WithStack(result.Error)
+}
@@ -0,0 +1,70 @@
+package dal
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// GetRoleResourceDB Get role resource storage instance
+func GetRoleResourceDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDB(ctx, defDB).Model(new(schema.RoleResource))
+}
+
+// RoleResource Resources allowed or denied to the roles for RBAC
+type RoleResource struct {
+	DB *gorm.DB
+}
+
+// Query role resources from the database based on the provided parameters and options.
+func (a *RoleResource) Query(ctx context.Context, params schema.RoleResourceQueryParam, opts ...schema.RoleResourceQueryOptions) (*schema.RoleResourceQueryResult, error) {
+	var opt schema.RoleResourceQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	db := GetRoleResourceDB(ctx, a.DB)
+	if v := params.RoleID; v > 0 {
+		db = db.Where("role_id = ?", v)
+	}
+	if v := params.InRoleIDs; len(v) > 0 {
+		db = db.Where("role_id IN (?)", v)
+	}
+	if v := params.Method; len(v) > 0 {
+		db = db.Where("method = ?", v)
+	}
+	if v := params.Path; len(v) > 0 {
+		db = db.Where("path = ?", v)
+	}
+	if v := params.Effect; len(v) > 0 {
+		db = db.Where("effect = ?", v)
+	}
+
+	var list schema.RoleResources
+	pageResult, err := util.WrapPageQuery(ctx, db, params.PaginationParam, opt.QueryOptions, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	queryResult := &schema.RoleResourceQueryResult{
+		PageResult: pageResult,
+		Data:       list,
+	}
+	return queryResult, nil
+}
+
+// Create a new role resource.
+func (a *RoleResource) Create(ctx context.Context, item *schema.RoleResource) error {
+	result := GetRoleResourceDB(ctx, a.DB).Create(item)
+	return errors.WithStack(result.Error)
+}
+
+// DeleteByRoleID Deletes the resources of the role.
+func (a *RoleResource) DeleteByRoleID(ctx context.Context, roleID int64) error {
+	result := GetRoleResourceDB(ctx, a.DB).Where("role_id=?", roleID).Delete(new(schema.RoleResource))
+	return errors.WithStack(result.Error)
+}
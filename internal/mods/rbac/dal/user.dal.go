@@ -2,6 +2,8 @@ package dal
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -15,6 +17,36 @@ func GetUserDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
 	return util.GetDB(ctx, defDB).Model(new(schema.User))
 }
 
+// DataScope Filter the rows by the data scope of the context, a row is visible if one of its owner columns (e.g.
+// created_by) is the user of the scope or a user of its departments. The rows are not filtered without a data scope.
+func DataScope(ctx context.Context, defDB *gorm.DB, ownerColumns ...string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		dataScope, ok := util.FromDataScope(ctx)
+		if !ok || dataScope == nil {
+			return db
+		}
+
+		var (
+			conds []string
+			args  []interface{}
+		)
+		for _, column := range ownerColumns {
+			if dataScope.UserID > 0 {
+				conds = append(conds, column+" = ?")
+				args = append(args, dataScope.UserID)
+			}
+			if len(dataScope.DeptIDs) > 0 {
+				conds = append(conds, column+" IN (?)")
+				args = append(args, GetUserDB(ctx, defDB).Where("dept_id IN (?)", dataScope.DeptIDs).Select("id"))
+			}
+		}
+		if len(conds) == 0 {
+			return db.Where("1 = 0")
+		}
+		return db.Where("("+strings.Join(conds, " OR ")+")", args...)
+	}
+}
+
 // User management for RBAC
 type User struct {
 	DB *gorm.DB
@@ -27,7 +59,7 @@ func (a *User) Query(ctx context.Context, params schema.UserQueryParam, opts ...
 		opt = opts[0]
 	}
 
-	db := GetUserDB(ctx, a.DB)
+	db := GetUserDB(ctx, a.DB).Scopes(DataScope(ctx, a.DB, "id", "created_by"))
 	if v := params.LikeUsername; len(v) > 0 {
 		db = db.Where("username LIKE ?", "%"+v+"%")
 	}
@@ -37,6 +69,9 @@ func (a *User) Query(ctx context.Context, params schema.UserQueryParam, opts ...
 	if v := params.Status; len(v) > 0 {
 		db = db.Where("status = ?", v)
 	}
+	if v := params.Email; len(v) > 0 {
+		db = db.Where("LOWER(email) = ?", strings.ToLower(v))
+	}
 
 	var list schema.Users
 	pageResult, err := util.WrapPageQuery(ctx, db, params.PaginationParam, opt.QueryOptions, &list)
@@ -119,7 +154,22 @@ func (a *User) Delete(ctx context.Context, id int64) error {
 	return errors.WithStack(result.Error)
 }
 
-func (a *User) UpdatePasswordByID(ctx context.Context, id int64, password string) error {
-	result := GetUserDB(ctx, a.DB).Where("id=?", id).Select("password").Updates(schema.User{Password: password})
+// UpdatePasswordByID Set the password of the specified user, an expired password must be changed at next login.
+func (a *User) UpdatePasswordByID(ctx context.Context, id int64, password string, expired bool) error {
+	now := time.Now()
+	result := GetUserDB(ctx, a.DB).Where("id=?", id).Select("password", "password_changed_at", "password_expired").
+		Updates(schema.User{Password: password, PasswordChangedAt: &now, PasswordExpired: expired})
+	return errors.WithStack(result.Error)
+}
+
+// IncrTokenVersion Bump the token version of the specified user, tokens of the previous versions become invalid.
+func (a *User) IncrTokenVersion(ctx context.Context, id int64) error {
+	result := GetUserDB(ctx, a.DB).Where("id=?", id).UpdateColumn("token_version", gorm.Expr("token_version + ?", 1))
+	return errors.WithStack(result.Error)
+}
+
+// UpdateDeptID Set the primary department of the specified user.
+func (a *User) UpdateDeptID(ctx context.Context, id, deptID int64) error {
+	result := GetUserDB(ctx, a.DB).Where("id=?", id).Update("dept_id", deptID)
 	return errors.WithStack(result.Error)
 }
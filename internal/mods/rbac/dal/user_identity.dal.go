@@ -0,0 +1,52 @@
+package dal
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// GetUserIdentityDB Get user identity storage instance
+func GetUserIdentityDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDB(ctx, defDB).Model(new(schema.UserIdentity))
+}
+
+// UserIdentity External identities of users
+type UserIdentity struct {
+	DB *gorm.DB
+}
+
+// GetBySubject Get the identity of the subject at the provider.
+func (a *UserIdentity) GetBySubject(ctx context.Context, provider, subject string) (*schema.UserIdentity, error) {
+	item := new(schema.UserIdentity)
+	ok, err := util.FindOne(ctx, GetUserIdentityDB(ctx, a.DB).Where("provider=? AND subject=?", provider, subject),
+		util.QueryOptions{}, item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+	return item, nil
+}
+
+// Create a new user identity.
+func (a *UserIdentity) Create(ctx context.Context, item *schema.UserIdentity) error {
+	result := GetUserIdentityDB(ctx, a.DB).Create(item)
+	return errors.WithStack(result.Error)
+}
+
+// Delete the specified user identity from the database.
+func (a *UserIdentity) Delete(ctx context.Context, id int64) error {
+	result := GetUserIdentityDB(ctx, a.DB).Where("id=?", id).Delete(new(schema.UserIdentity))
+	return errors.WithStack(result.Error)
+}
+
+// DeleteByUserID Delete the identities of the specified user.
+func (a *UserIdentity) DeleteByUserID(ctx context.Context, userID int64) error {
+	result := GetUserIdentityDB(ctx, a.DB).Where("user_id=?", userID).Delete(new(schema.UserIdentity))
+	return errors.WithStack(result.Error)
+}
@@ -3,6 +3,7 @@ package dal
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -28,7 +29,7 @@ func (a *UserRole) Query(ctx context.Context, params schema.UserRoleQueryParam,
 		opt = opts[0]
 	}
 
-	db := a.DB.Table(fmt.Sprintf("%s AS a", new(schema.UserRole).TableName()))
+	db := util.GetDB(ctx, a.DB).Table(fmt.Sprintf("%s AS a", new(schema.UserRole).TableName()))
 	if opt.JoinRole {
 		db = db.Joins(fmt.Sprintf("left join %s b on a.role_id=b.id", new(schema.Role).TableName()))
 		db = db.Select("a.*,b.name as role_name")
@@ -43,6 +44,10 @@ func (a *UserRole) Query(ctx context.Context, params schema.UserRoleQueryParam,
 	if v := params.RoleID; v > 0 {
 		db = db.Where("a.role_id = ?", v)
 	}
+	if params.Active {
+		now := time.Now()
+		db = db.Where("(a.start_at IS NULL OR a.start_at <= ?) AND (a.expire_at IS NULL OR a.expire_at > ?)", now, now)
+	}
 
 	var list schema.UserRoles
 	pageResult, err := util.WrapPageQuery(ctx, db, params.PaginationParam, opt.QueryOptions, &list)
@@ -107,3 +112,23 @@ func (a *UserRole) DeleteByRoleID(ctx context.Context, roleID int64) error {
 	result := GetUserRoleDB(ctx, a.DB).Where("role_id=?", roleID).Delete(new(schema.UserRole))
 	return errors.WithStack(result.Error)
 }
+
+// QueryExpired Query the assignments (ID and user) expired at the time.
+func (a *UserRole) QueryExpired(ctx context.Context, now time.Time) (schema.UserRoles, error) {
+	var list schema.UserRoles
+	err := GetUserRoleDB(ctx, a.DB).Select("id", "user_id").Where("expire_at <= ?", now).Find(&list).Error
+	return list, errors.WithStack(err)
+}
+
+// QueryStarted Query the assignments (ID and user) started after since and until the time.
+func (a *UserRole) QueryStarted(ctx context.Context, since, now time.Time) (schema.UserRoles, error) {
+	var list schema.UserRoles
+	err := GetUserRoleDB(ctx, a.DB).Select("id", "user_id").
+		Where("start_at > ? AND start_at <= ?", since, now).Find(&list).Error
+	return list, errors.WithStack(err)
+}
+
+func (a *UserRole) DeleteByIDs(ctx context.Context, ids []int64) error {
+	result := GetUserRoleDB(ctx, a.DB).Where("id IN (?)", ids).Delete(new(schema.UserRole))
+	return errors.WithStack(result.Error)
+}
@@ -10,27 +10,46 @@ import (
 
 	"github.com/supermicah/go-framework-admin/internal/config"
 	"github.com/supermicah/go-framework-admin/internal/mods/rbac/api"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/biz"
 	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
 	"github.com/supermicah/go-framework-admin/pkg/logging"
 )
 
 type RBAC struct {
-	DB       *gorm.DB
-	MenuAPI  *api.Menu
-	RoleAPI  *api.Role
-	UserAPI  *api.User
-	LoginAPI *api.Login
-	Casbinx  *Casbinx
+	DB               *gorm.DB
+	MenuAPI          *api.Menu
+	RoleAPI          *api.Role
+	UserAPI          *api.User
+	LoginAPI         *api.Login
+	SessionAPI       *api.Session
+	APIKeyAPI        *api.APIKey
+	TwoFactorAPI     *api.TwoFactor
+	PasswordResetAPI *api.PasswordReset
+	OIDCAPI          *api.OIDC
+	TenantAPI        *api.Tenant
+	PermissionAPI    *api.Permission
+	BundleAPI        *api.Bundle
+	RouteAPI         *api.Route
+	Casbinx          *biz.Casbinx
+	Event            *biz.Event
+	UserRoleSweeper  *biz.UserRoleSweeper
 }
 
 func (a *RBAC) AutoMigrate(ctx context.Context) error {
 	return a.DB.AutoMigrate(
 		new(schema.Menu),
 		new(schema.MenuResource),
+		new(schema.MenuI18n),
 		new(schema.Role),
 		new(schema.RoleMenu),
+		new(schema.RoleParent),
+		new(schema.RoleResource),
 		new(schema.User),
 		new(schema.UserRole),
+		new(schema.APIKey),
+		new(schema.PasswordHistory),
+		new(schema.UserIdentity),
+		new(schema.Tenant),
 	)
 }
 
@@ -41,10 +60,6 @@ func (a *RBAC) Init(ctx context.Context) error {
 		}
 	}
 
-	if err := a.Casbinx.Load(ctx); err != nil {
-		return err
-	}
-
 	if name := config.C.General.MenuFile; name != "" {
 		fullPath := filepath.Join(config.C.General.WorkDir, name)
 		if err := a.MenuAPI.MenuBIZ.InitFromFile(ctx, fullPath); err != nil {
@@ -52,7 +67,14 @@ func (a *RBAC) Init(ctx context.Context) error {
 		}
 	}
 
-	return nil
+	// The policies are loaded after the menu data, which can add resources to the menus of the roles
+	if err := a.Casbinx.Load(ctx); err != nil {
+		return err
+	}
+	if err := a.Event.Subscribe(ctx); err != nil {
+		return err
+	}
+	return a.UserRoleSweeper.Start(ctx)
 }
 
 func (a *RBAC) RegisterV1Routers(ctx context.Context, v1 *gin.RouterGroup) error {
@@ -62,6 +84,12 @@ func (a *RBAC) RegisterV1Routers(ctx context.Context, v1 *gin.RouterGroup) error
 		captcha.GET("image", a.LoginAPI.ResponseCaptcha)
 	}
 	v1.POST("login", a.LoginAPI.Login)
+	v1.POST("login/2fa", a.LoginAPI.LoginTwoFactor)
+	v1.POST("login/2fa/setup", a.LoginAPI.SetupLoginTwoFactor)
+	v1.GET("login/oidc/:provider", a.OIDCAPI.Redirect)
+	v1.GET("login/oidc/:provider/callback", a.OIDCAPI.Callback)
+	v1.POST("password/forgot", a.PasswordResetAPI.Forgot)
+	v1.POST("password/reset", a.PasswordResetAPI.Reset)
 
 	current := v1.Group("current")
 	{
@@ -71,13 +99,29 @@ func (a *RBAC) RegisterV1Routers(ctx context.Context, v1 *gin.RouterGroup) error
 		current.PUT("password", a.LoginAPI.UpdatePassword)
 		current.PUT("user", a.LoginAPI.UpdateUser)
 		current.POST("logout", a.LoginAPI.Logout)
+		current.GET("sessions", a.SessionAPI.QueryCurrent)
+		current.DELETE("sessions", a.SessionAPI.DeleteCurrent)
+		current.GET("api-keys", a.APIKeyAPI.Query)
+		current.GET("api-keys/:id", a.APIKeyAPI.Get)
+		current.POST("api-keys", a.APIKeyAPI.Create)
+		current.PUT("api-keys/:id", a.APIKeyAPI.Update)
+		current.DELETE("api-keys/:id", a.APIKeyAPI.Delete)
+		current.POST("2fa/setup", a.TwoFactorAPI.Setup)
+		current.POST("2fa/enable", a.TwoFactorAPI.Enable)
+		current.POST("2fa/disable", a.TwoFactorAPI.Disable)
+		current.POST("2fa/recovery-codes", a.TwoFactorAPI.RegenerateRecoveryCodes)
 	}
 	menu := v1.Group("menus")
 	{
 		menu.GET("", a.MenuAPI.Query)
 		menu.GET(":id", a.MenuAPI.Get)
 		menu.POST("", a.MenuAPI.Create)
+		menu.POST("reconcile", a.MenuAPI.Reconcile)
+		menu.GET("resources/routes", a.RouteAPI.Query)
+		menu.GET("resources/report", a.RouteAPI.Report)
+		menu.PUT("reorder", a.MenuAPI.Reorder)
 		menu.PUT(":id", a.MenuAPI.Update)
+		menu.POST(":id/move", a.MenuAPI.Move)
 		menu.DELETE(":id", a.MenuAPI.Delete)
 	}
 	role := v1.Group("roles")
@@ -96,6 +140,24 @@ func (a *RBAC) RegisterV1Routers(ctx context.Context, v1 *gin.RouterGroup) error
 		user.PUT(":id", a.UserAPI.Update)
 		user.DELETE(":id", a.UserAPI.Delete)
 		user.PATCH(":id/reset-pwd", a.UserAPI.ResetPassword)
+		user.PATCH(":id/unlock", a.UserAPI.Unlock)
+		user.GET(":id/sessions", a.SessionAPI.Query)
+		user.DELETE(":id/sessions/:sid", a.SessionAPI.Delete)
+		user.GET(":id/permissions", a.PermissionAPI.QueryUserPermissions)
+	}
+	v1.POST("permissions/check", a.PermissionAPI.Check)
+	bundle := v1.Group("bundles")
+	{
+		bundle.GET("export", a.BundleAPI.Export)
+		bundle.POST("import", a.BundleAPI.Import)
+	}
+	tenant := v1.Group("tenants")
+	{
+		tenant.GET("", a.TenantAPI.Query)
+		tenant.GET(":id", a.TenantAPI.Get)
+		tenant.POST("", a.TenantAPI.Create)
+		tenant.PUT(":id", a.TenantAPI.Update)
+		tenant.DELETE(":id", a.TenantAPI.Delete)
 	}
 	return nil
 }
@@ -104,5 +166,8 @@ func (a *RBAC) Release(ctx context.Context) error {
 	if err := a.Casbinx.Release(ctx); err != nil {
 		return err
 	}
+	if err := a.UserRoleSweeper.Release(ctx); err != nil {
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// APIKey Personal access token of a user for machine clients
+type APIKey struct {
+	ID         int64      `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
+	UserID     int64      `json:"user_id" gorm:"size:64;index"`                // From User.ID (owner)
+	Name       string     `json:"name" gorm:"size:128"`                        // Display name of API key
+	Prefix     string     `json:"prefix" gorm:"size:16"`                       // Leading characters of the key (for identification)
+	SecretHash string     `json:"-" gorm:"size:64;uniqueIndex"`                // SHA256 hash of the key
+	RoleIDs    []int64    `json:"role_ids" gorm:"size:1024;serializer:json"`   // Subset of the owner's roles (empty means all roles of the owner)
+	ExpiresAt  *time.Time `json:"expires_at"`                                  // Expired time (empty means never expires)
+	LastUsedAt *time.Time `json:"last_used_at"`                                // Last used time
+	CreatedAt  time.Time  `json:"created_at" gorm:"index;"`                    // Create time
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"index;"`                    // Update time
+	Key        string     `json:"key,omitempty" gorm:"-"`                      // Plain key, only returned once when created
+}
+
+func (a *APIKey) TableName() string {
+	return config.C.FormatTableName("api_key")
+}
+
+// IsExpired Reports whether the key has expired.
+func (a *APIKey) IsExpired() bool {
+	return a.ExpiresAt != nil && !a.ExpiresAt.After(time.Now())
+}
+
+// APIKeyQueryParam Defining the query parameters for the `APIKey` struct.
+type APIKeyQueryParam struct {
+	util.PaginationParam
+	LikeName string `form:"name"` // Display name of API key
+	UserID   int64  `form:"-"`    // From User.ID
+}
+
+// APIKeyQueryOptions Defining the query options for the `APIKey` struct.
+type APIKeyQueryOptions struct {
+	util.QueryOptions
+}
+
+// APIKeyQueryResult Defining the query result for the `APIKey` struct.
+type APIKeyQueryResult struct {
+	Data       APIKeys
+	PageResult *util.PaginationResult
+}
+
+// APIKeys Defining the slice of `APIKey` struct.
+type APIKeys []*APIKey
+
+// APIKeyForm Defining the data structure for creating a `APIKey` struct.
+type APIKeyForm struct {
+	Name      string     `json:"name" binding:"required,max=128"` // Display name of API key
+	RoleIDs   []int64    `json:"role_ids"`                        // Subset of the owner's roles (empty means all roles of the owner)
+	ExpiresAt *time.Time `json:"expires_at"`                      // Expired time (empty means never expires)
+}
+
+// Validate A validation function for the `APIKeyForm` struct.
+func (a *APIKeyForm) Validate() error {
+	if a.ExpiresAt != nil && !a.ExpiresAt.After(time.Now()) {
+		return errors.BadRequest("", "Expired time must be in the future")
+	}
+	return nil
+}
+
+func (a *APIKeyForm) FillTo(apiKey *APIKey) error {
+	apiKey.Name = a.Name
+	apiKey.RoleIDs = a.RoleIDs
+	apiKey.ExpiresAt = a.ExpiresAt
+	return nil
+}
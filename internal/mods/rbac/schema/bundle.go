@@ -0,0 +1,171 @@
+package schema
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/supermicah/go-framework-admin/pkg/encoding/json"
+	"github.com/supermicah/go-framework-admin/pkg/encoding/yaml"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+)
+
+const (
+	BundleVersion    = 1 // Version of the bundles written by this release
+	BundleFormatJSON = "json"
+	BundleFormatYAML = "yaml"
+)
+
+// Bundle Portable RBAC configuration, promoted from a database to another. The menus, roles and departments are
+// referenced by code (path) instead of ID, so the bundle does not depend on the auto-increment IDs.
+type Bundle struct {
+	Version    int         `json:"version" yaml:"version"`                 // Version of the bundle format
+	ExportedAt time.Time   `json:"exported_at" yaml:"exported_at"`         // Export time
+	Menus      BundleMenus `json:"menus" yaml:"menus"`                     // Menu tree with resources
+	Roles      BundleRoles `json:"roles" yaml:"roles"`                     // Roles with menus, parents and resources
+	Users      BundleUsers `json:"users,omitempty" yaml:"users,omitempty"` // Role bindings of the users (optional)
+}
+
+// BundleMenu Menu of a bundle, matched by its code under its parent
+type BundleMenu struct {
+	Code        string          `json:"code" yaml:"code"`
+	Name        string          `json:"name" yaml:"name"`
+	Description string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Sequence    int             `json:"sequence" yaml:"sequence"`
+	Type        string          `json:"type" yaml:"type"`
+	Path        string          `json:"path,omitempty" yaml:"path,omitempty"`
+	Properties  string          `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Status      string          `json:"status" yaml:"status"`
+	Resources   BundleResources `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Children    BundleMenus     `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// BundleMenus Defining the slice of `BundleMenu` struct.
+type BundleMenus []*BundleMenu
+
+// BundleResource API resource of a menu or a role in a bundle
+type BundleResource struct {
+	Method string `json:"method" yaml:"method"`
+	Path   string `json:"path" yaml:"path"`
+	Effect string `json:"effect,omitempty" yaml:"effect,omitempty"` // allow (default), deny
+}
+
+// BundleResources Defining the slice of `BundleResource` struct.
+type BundleResources []*BundleResource
+
+// BundleRole Role of a bundle, matched by its code
+type BundleRole struct {
+	Code             string          `json:"code" yaml:"code"`
+	Name             string          `json:"name" yaml:"name"`
+	Description      string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Sequence         int             `json:"sequence" yaml:"sequence"`
+	Status           string          `json:"status" yaml:"status"`
+	RequireTwoFactor bool            `json:"require_two_factor,omitempty" yaml:"require_two_factor,omitempty"`
+	DataScope        string          `json:"data_scope,omitempty" yaml:"data_scope,omitempty"`
+	DataScopeDepts   []string        `json:"data_scope_depts,omitempty" yaml:"data_scope_depts,omitempty"` // Code paths of the departments of the custom data scope
+	Menus            []string        `json:"menus,omitempty" yaml:"menus,omitempty"`                       // Code paths of the menus (like xxx.xxx.xxx)
+	Parents          []string        `json:"parents,omitempty" yaml:"parents,omitempty"`                   // Codes of the inherited roles
+	Resources        BundleResources `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// BundleRoles Defining the slice of `BundleRole` struct.
+type BundleRoles []*BundleRole
+
+// BundleUser Role bindings of a user, matched by its username
+type BundleUser struct {
+	Username string          `json:"username" yaml:"username"`
+	Roles    BundleUserRoles `json:"roles" yaml:"roles"`
+}
+
+// BundleUsers Defining the slice of `BundleUser` struct.
+type BundleUsers []*BundleUser
+
+// BundleUserRole Role binding of a user in a bundle
+type BundleUserRole struct {
+	Role     string     `json:"role" yaml:"role"` // Code of the role
+	StartAt  *time.Time `json:"start_at,omitempty" yaml:"start_at,omitempty"`
+	ExpireAt *time.Time `json:"expire_at,omitempty" yaml:"expire_at,omitempty"`
+}
+
+// BundleUserRoles Defining the slice of `BundleUserRole` struct.
+type BundleUserRoles []*BundleUserRole
+
+// BundleExportParam Defining the parameters to export a bundle.
+type BundleExportParam struct {
+	Users  bool   `form:"users"`  // Include the role bindings of the users
+	Format string `form:"format"` // Format of the bundle (json, yaml), default json
+}
+
+// BundleImportResult Changes of the import of a bundle
+type BundleImportResult struct {
+	Menus        *MenuDiff `json:"menus"`                   // Changes of the menus
+	CreatedRoles []string  `json:"created_roles,omitempty"` // Codes of the created roles
+	UpdatedRoles []string  `json:"updated_roles,omitempty"` // Codes of the updated roles
+	BoundUsers   []string  `json:"bound_users,omitempty"`   // Usernames of the users whose roles are replaced
+	SkippedUsers []string  `json:"skipped_users,omitempty"` // Usernames of the users not found
+}
+
+// BundleFormatOf The format of the bundle file by its extension, YAML for .yaml and .yml files, else JSON.
+func BundleFormatOf(name string) string {
+	if ext := strings.ToLower(filepath.Ext(name)); ext == ".yaml" || ext == ".yml" {
+		return BundleFormatYAML
+	}
+	return BundleFormatJSON
+}
+
+// ParseBundle Decode a JSON or YAML bundle and check its version.
+func ParseBundle(data []byte, format string) (*Bundle, error) {
+	bundle := new(Bundle)
+	if format == BundleFormatYAML {
+		if err := yaml.Unmarshal(data, bundle); err != nil {
+			return nil, errors.BadRequest("", "Invalid YAML bundle: %s", err.Error())
+		}
+	} else if err := json.Unmarshal(data, bundle); err != nil {
+		return nil, errors.BadRequest("", "Invalid JSON bundle: %s", err.Error())
+	}
+
+	if bundle.Version < 1 || bundle.Version > BundleVersion {
+		return nil, errors.BadRequest("", "Unsupported bundle version %d", bundle.Version)
+	}
+	return bundle, nil
+}
+
+// Marshal Encode the bundle as JSON or YAML.
+func (a *Bundle) Marshal(format string) ([]byte, error) {
+	if format == BundleFormatYAML {
+		b, err := yaml.Marshal(a)
+		return b, errors.WithStack(err)
+	}
+	b, err := json.MarshalIndent(a, "", "  ")
+	return b, errors.WithStack(err)
+}
+
+// ToMenus Convert the menus of the bundle to a menu tree without IDs.
+func (a BundleMenus) ToMenus() Menus {
+	menus := make(Menus, 0, len(a))
+	for _, item := range a {
+		menu := &Menu{
+			Code:        item.Code,
+			Name:        item.Name,
+			Description: item.Description,
+			Sequence:    item.Sequence,
+			Type:        item.Type,
+			Path:        item.Path,
+			Properties:  item.Properties,
+			Status:      item.Status,
+		}
+		for _, res := range item.Resources {
+			menu.Resources = append(menu.Resources, &MenuResource{
+				Method: res.Method,
+				Path:   res.Path,
+				Effect: res.Effect,
+			})
+		}
+		if len(item.Children) > 0 {
+			children := item.Children.ToMenus()
+			menu.Children = &children
+		}
+		menus = append(menus, menu)
+	}
+	return menus
+}
@@ -0,0 +1,23 @@
+package schema
+
+const (
+	EventRoleChanged = "rbac.role-changed" // Roles were created, updated or deleted
+	EventMenuChanged = "rbac.menu-changed" // Menus or their resources were updated or deleted
+	EventUserChanged = "rbac.user-changed" // Roles, status or tokens of users were changed
+)
+
+// RoleChangedEvent Payload of the `EventRoleChanged` event
+type RoleChangedEvent struct {
+	RoleIDs []int64 `json:"role_ids"`
+}
+
+// MenuChangedEvent Payload of the `EventMenuChanged` event
+type MenuChangedEvent struct {
+	MenuIDs []int64 `json:"menu_ids"`
+	RoleIDs []int64 `json:"role_ids"` // Roles granted the menus or their children, found before the menus are deleted
+}
+
+// UserChangedEvent Payload of the `EventUserChanged` event
+type UserChangedEvent struct {
+	UserIDs []int64 `json:"user_ids"`
+}
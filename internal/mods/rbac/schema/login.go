@@ -25,14 +25,28 @@ type UpdateLoginPassword struct {
 }
 
 type LoginToken struct {
-	AccessToken string `json:"access_token"` // Access token (JWT)
-	TokenType   string `json:"token_type"`   // Token type (Usage: Authorization=${token_type} ${access_token})
-	ExpiresAt   int64  `json:"expires_at"`   // Expired time (Unit: second)
+	AccessToken      string `json:"access_token"`       // Access token (JWT)
+	TokenType        string `json:"token_type"`         // Token type (Usage: Authorization=${token_type} ${access_token})
+	ExpiresAt        int64  `json:"expires_at"`         // Expired time (Unit: second)
+	RefreshToken     string `json:"refresh_token"`      // Refresh token (JWT, can only be used once)
+	RefreshExpiresAt int64  `json:"refresh_expires_at"` // Refresh token expired time (Unit: second)
+
+	ChallengeToken     string   `json:"challenge_token,omitempty"`      // Two-factor challenge token, exchanged for the tokens above by /api/v1/login/2fa
+	ChallengeExpiresAt int64    `json:"challenge_expires_at,omitempty"` // Challenge token expired time (Unit: second)
+	TwoFactorSetup     bool     `json:"two_factor_setup,omitempty"`     // Two-factor must be set up by /api/v1/login/2fa/setup before verifying the challenge
+	RecoveryCodes      []string `json:"recovery_codes,omitempty"`       // Recovery codes, only returned when two-factor is set up during login
+
+	PasswordExpired bool `json:"password_expired,omitempty"` // The password has expired, the access token only allows changing it by /api/v1/current/password
+}
+
+type RefreshTokenForm struct {
+	RefreshToken string `json:"refresh_token" binding:"required"` // Refresh token
 }
 
 type UpdateCurrentUser struct {
-	Name   string `json:"name" binding:"required,max=64"` // Name of user
-	Phone  string `json:"phone" binding:"max=32"`         // Phone number of user
-	Email  string `json:"email" binding:"max=128"`        // Email of user
-	Remark string `json:"remark" binding:"max=1024"`      // Remark of user
+	Name   string  `json:"name" binding:"required,max=64"`              // Name of user
+	Phone  string  `json:"phone" binding:"max=32"`                      // Phone number of user
+	Email  string  `json:"email" binding:"max=128"`                     // Email of user
+	Remark string  `json:"remark" binding:"max=1024"`                   // Remark of user
+	Locale *string `json:"locale,omitempty" binding:"omitempty,max=20"` // Preferred locale of user (e.g. en-US), unchanged if absent
 }
@@ -63,6 +63,7 @@ type MenuQueryParam struct {
 	Code             string  `form:"-"`                // Code (like xxx)
 	UserID           int64   `form:"-"`                // User ID
 	RoleID           int64   `form:"-"`                // Role ID
+	InRoleIDs        []int64 `form:"-"`                // Role ID list
 }
 
 // MenuQueryOptions Defining the query options for the `Menu` struct.
@@ -172,7 +173,7 @@ func (a *MenuForm) Validate() error {
 			return errors.BadRequest("", "invalid properties")
 		}
 	}
-	return nil
+	return a.Resources.Validate()
 }
 
 func (a *MenuForm) FillTo(menu *Menu) error {
@@ -187,3 +188,14 @@ func (a *MenuForm) FillTo(menu *Menu) error {
 	menu.ParentID = a.ParentID
 	return nil
 }
+
+// MenuMoveForm Defining the data structure for moving a `Menu` struct.
+type MenuMoveForm struct {
+	ParentID int64 `json:"parent_id"`                // New parent ID (From Menu.ID), zero to move to the root
+	Position int   `json:"position" binding:"min=0"` // Position among the new siblings (zero for the first), the last if beyond
+}
+
+// MenuReorderForm Defining the data structure for reordering the menus of a parent.
+type MenuReorderForm struct {
+	IDs []int64 `json:"ids" binding:"required,min=1"` // All the menus of a parent (From Menu.ID) in their new order
+}
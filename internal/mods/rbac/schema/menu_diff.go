@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	MenuSyncModeInsert    = "insert"    // Only create the missing menus and resources
+	MenuSyncModeReconcile = "reconcile" // Apply the full diff with the menu file
+	MenuSyncModeDryRun    = "dry-run"   // Only log the diff with the menu file
+
+	MenuChangeCreate = "create"
+	MenuChangeUpdate = "update"
+	MenuChangeMove   = "move"
+	MenuChangeDelete = "delete"
+)
+
+// MenuReconcileParam Defining the parameters to reconcile the menus with the menu file.
+type MenuReconcileParam struct {
+	DryRun bool `form:"dryRun"` // Only return the diff without applying it
+}
+
+// MenuChange Change of a menu or of one of its resources to reconcile the database with the menu file
+type MenuChange struct {
+	Action   string   `json:"action"`             // Change of the menu (create, update, move, delete)
+	MenuID   int64    `json:"menu_id,omitempty"`  // From Menu.ID (empty for the menus to create)
+	CodePath string   `json:"code_path"`          // Code path of the menu (like xxx.xxx.xxx)
+	Fields   []string `json:"fields,omitempty"`   // Updated fields of the menu
+	From     string   `json:"from,omitempty"`     // Code path of the previous parent of the moved menu
+	Resource string   `json:"resource,omitempty"` // Method and path of the changed resource (empty for the menu)
+	Effect   string   `json:"effect,omitempty"`   // Effect of the changed resource (allow, deny)
+	Skipped  bool     `json:"skipped,omitempty"`  // Not applied as the deletion of the menus is denied
+}
+
+func (a *MenuChange) String() string {
+	var sb strings.Builder
+	sb.WriteString(a.Action)
+	if a.Resource != "" {
+		sb.WriteString(" resource ")
+		sb.WriteString(a.Resource)
+		if a.Effect != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", a.Effect))
+		}
+		sb.WriteString(" of")
+	}
+	sb.WriteString(" menu ")
+	sb.WriteString(a.CodePath)
+	if a.MenuID > 0 {
+		sb.WriteString(fmt.Sprintf(" #%d", a.MenuID))
+	}
+	if len(a.Fields) > 0 {
+		sb.WriteString(fmt.Sprintf(" [%s]", strings.Join(a.Fields, ", ")))
+	}
+	if a.Action == MenuChangeMove {
+		from := a.From
+		if from == "" {
+			from = "(root)"
+		}
+		sb.WriteString(" from ")
+		sb.WriteString(from)
+	}
+	if a.Skipped {
+		sb.WriteString(" (skipped)")
+	}
+	return sb.String()
+}
+
+// MenuDiff Changes to reconcile the database with the menu file, they are not applied in dry-run mode
+type MenuDiff struct {
+	DryRun  bool          `json:"dry_run"`
+	Changes []*MenuChange `json:"changes"`
+}
+
+// Add a change to the diff.
+func (a *MenuDiff) Add(action string, menu *Menu, codePath string) *MenuChange {
+	change := &MenuChange{Action: action, MenuID: menu.ID, CodePath: codePath}
+	a.Changes = append(a.Changes, change)
+	return change
+}
+
+// AddResource Add a change of a resource of the menu to the diff.
+func (a *MenuDiff) AddResource(action string, menu *Menu, codePath string, res *MenuResource) *MenuChange {
+	change := &MenuChange{
+		Action:   action,
+		MenuID:   menu.ID,
+		CodePath: codePath,
+		Resource: fmt.Sprintf("%s %s", res.Method, res.Path),
+		Effect:   res.Effect,
+	}
+	a.Changes = append(a.Changes, change)
+	return change
+}
+
+func (a *MenuDiff) String() string {
+	if len(a.Changes) == 0 {
+		return "menus are up to date"
+	}
+	lines := make([]string, 0, len(a.Changes))
+	for _, change := range a.Changes {
+		lines = append(lines, change.String())
+	}
+	return strings.Join(lines, "\n")
+}
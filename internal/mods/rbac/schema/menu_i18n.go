@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// MenuI18n Translation of the name of a menu for RBAC
+type MenuI18n struct {
+	ID        int64     `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
+	MenuID    int64     `json:"menu_id" gorm:"size:64;index"`                // From Menu.ID
+	Locale    string    `json:"locale" gorm:"size:20;index"`                 // Locale of the translation (e.g. en-US)
+	Name      string    `json:"name" gorm:"size:128"`                        // Translated display name of menu
+	CreatedAt time.Time `json:"created_at" gorm:"index;"`                    // Create time
+	UpdatedAt time.Time `json:"updated_at" gorm:"index;"`                    // Update time
+}
+
+func (a *MenuI18n) TableName() string {
+	return config.C.FormatTableName("menu_i18n")
+}
+
+// MenuI18nQueryParam Defining the query parameters for the `MenuI18n` struct.
+type MenuI18nQueryParam struct {
+	util.PaginationParam
+	MenuIDs []int64 `form:"-"` // From Menu.ID
+	Locale  string  `form:"-"` // Locale of the translation
+}
+
+// MenuI18nQueryOptions Defining the query options for the `MenuI18n` struct.
+type MenuI18nQueryOptions struct {
+	util.QueryOptions
+}
+
+// MenuI18nQueryResult Defining the query result for the `MenuI18n` struct.
+type MenuI18nQueryResult struct {
+	Data       MenuI18ns
+	PageResult *util.PaginationResult
+}
+
+// MenuI18ns Defining the slice of `MenuI18n` struct.
+type MenuI18ns []*MenuI18n
+
+// ToMenuIDMap The translations by menu ID, of a single locale.
+func (a MenuI18ns) ToMenuIDMap() map[int64]*MenuI18n {
+	m := make(map[int64]*MenuI18n, len(a))
+	for _, item := range a {
+		m[item.MenuID] = item
+	}
+	return m
+}
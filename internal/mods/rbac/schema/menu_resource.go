@@ -4,15 +4,34 @@ import (
 	"time"
 
 	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
 	"github.com/supermicah/go-framework-admin/pkg/util"
 )
 
+const (
+	ResourceEffectAllow = "allow" // The resource is allowed
+	ResourceEffectDeny  = "deny"  // The resource is denied, whatever the other roles and menus allow
+)
+
+// checkResourceEffect The effect of a resource defaults to allow.
+func checkResourceEffect(effect *string) error {
+	switch *effect {
+	case "":
+		*effect = ResourceEffectAllow
+	case ResourceEffectAllow, ResourceEffectDeny:
+	default:
+		return errors.BadRequest("", "Invalid resource effect '%s'", *effect)
+	}
+	return nil
+}
+
 // MenuResource Menu resource management for RBAC
 type MenuResource struct {
 	ID        int64     `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
 	MenuID    int64     `json:"menu_id" gorm:"size:64;index"`                // From Menu.ID
 	Method    string    `json:"method" gorm:"size:64;"`                      // HTTP method
 	Path      string    `json:"path" gorm:"size:255;"`                       // API request path (e.g. /api/v1/users/:id)
+	Effect    string    `json:"effect" gorm:"size:20;default:allow"`         // Effect of resource (allow, deny)
 	CreatedAt time.Time `json:"created_at" gorm:"index;"`                    // Create time
 	UpdatedAt time.Time `json:"updated_at" gorm:"index;"`                    // Update time
 }
@@ -26,6 +45,9 @@ type MenuResourceQueryParam struct {
 	util.PaginationParam
 	MenuID  int64   `form:"-"` // From Menu.ID
 	MenuIDs []int64 `form:"-"` // From Menu.ID
+	Method  string  `form:"-"` // HTTP method
+	Path    string  `form:"-"` // API request path
+	Effect  string  `form:"-"` // Effect of resource (allow, deny)
 }
 
 // MenuResourceQueryOptions Defining the query options for the `MenuResource` struct.
@@ -42,6 +64,16 @@ type MenuResourceQueryResult struct {
 // MenuResources Defining the slice of `MenuResource` struct.
 type MenuResources []*MenuResource
 
+// Validate Check the effects of the resources.
+func (a MenuResources) Validate() error {
+	for _, item := range a {
+		if err := checkResourceEffect(&item.Effect); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // MenuResourceForm Defining the data structure for creating a `MenuResource` struct.
 type MenuResourceForm struct {
 }
@@ -0,0 +1,22 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+)
+
+// PasswordHistory Previous password hashes of a user, to prevent reusing recent passwords
+type PasswordHistory struct {
+	ID        int64     `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
+	UserID    int64     `json:"user_id" gorm:"size:64;index"`                // From User.ID
+	Password  string    `json:"-" gorm:"size:64;"`                           // Password hash (bcrypt)
+	CreatedAt time.Time `json:"created_at" gorm:"index;"`                    // Create time
+}
+
+func (a *PasswordHistory) TableName() string {
+	return config.C.FormatTableName("password_history")
+}
+
+// PasswordHistories Defining the slice of `PasswordHistory` struct.
+type PasswordHistories []*PasswordHistory
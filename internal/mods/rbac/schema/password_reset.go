@@ -0,0 +1,27 @@
+package schema
+
+import "strings"
+
+// PasswordForgotForm Defining the data structure for requesting a password reset email.
+type PasswordForgotForm struct {
+	Email       string `json:"email" binding:"required,email,max=128"` // Email of user
+	CaptchaID   string `json:"captcha_id" binding:"required"`          // Captcha verify id
+	CaptchaCode string `json:"captcha_code" binding:"required"`        // Captcha verify code
+}
+
+func (a *PasswordForgotForm) Trim() *PasswordForgotForm {
+	a.Email = strings.TrimSpace(a.Email)
+	a.CaptchaCode = strings.TrimSpace(a.CaptchaCode)
+	return a
+}
+
+// PasswordResetForm Defining the data structure for resetting the password with the emailed token.
+type PasswordResetForm struct {
+	Token    string `json:"token" binding:"required"`           // Reset token from the email (can only be used once)
+	Password string `json:"password" binding:"required,max=64"` // New password
+}
+
+func (a *PasswordResetForm) Trim() *PasswordResetForm {
+	a.Token = strings.TrimSpace(a.Token)
+	return a
+}
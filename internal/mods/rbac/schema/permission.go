@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"strings"
+
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+)
+
+// PermissionCheckForm Defining the data structure for checking a permission, of a user or of roles.
+type PermissionCheckForm struct {
+	UserID  int64   `json:"user_id"`                   // From User.ID (the roles of the user are checked)
+	RoleIDs []int64 `json:"role_ids"`                  // From Role.ID (checked if no user is given)
+	Method  string  `json:"method" binding:"required"` // HTTP method
+	Path    string  `json:"path" binding:"required"`   // API request path (e.g. /api/v1/users/1)
+}
+
+// Validate A validation function for the `PermissionCheckForm` struct.
+func (a *PermissionCheckForm) Validate() error {
+	if (a.UserID == 0) == (len(a.RoleIDs) == 0) {
+		return errors.BadRequest("", "Either the user or the roles must be given")
+	}
+	a.Method = strings.ToUpper(a.Method)
+	return nil
+}
+
+// PermissionCheck Result of a permission check, explained by the policy deciding it. A denied permission without a
+// policy is not granted by any role.
+type PermissionCheck struct {
+	Allowed      bool          `json:"allowed"`                 // Whether the request is allowed
+	Root         bool          `json:"root,omitempty"`          // The root user is allowed everything
+	Effect       string        `json:"effect,omitempty"`        // Effect of the deciding policy (allow, deny)
+	Policy       []string      `json:"policy,omitempty"`        // Deciding policy (role, tenant, path, method, effect)
+	SubjectID    int64         `json:"subject_id,omitempty"`    // Checked role granted or denied the request (From Role.ID)
+	Role         *Role         `json:"role,omitempty"`          // Role of the deciding policy, the checked role or one of its ancestors
+	Menu         *Menu         `json:"menu,omitempty"`          // Menu of the deciding resource
+	MenuResource *MenuResource `json:"menu_resource,omitempty"` // Menu resource of the deciding policy
+	RoleResource *RoleResource `json:"role_resource,omitempty"` // Role resource of the deciding policy
+}
+
+// Permission Method and path allowed to a user
+type Permission struct {
+	Method  string  `json:"method"`   // HTTP method
+	Path    string  `json:"path"`     // API request path pattern (e.g. /api/v1/users/:id)
+	RoleIDs []int64 `json:"role_ids"` // Roles granting the permission (From Role.ID)
+}
+
+// Permissions Defining the slice of `Permission` struct.
+type Permissions []*Permission
@@ -12,19 +12,32 @@ const (
 	RoleStatusDisabled = "disabled" // Disabled
 
 	RoleResultTypeSelect = "select" // Select
+
+	RoleDataScopeAll             = "all"               // All the rows
+	RoleDataScopeDept            = "dept"              // Rows of the department of the user
+	RoleDataScopeDeptAndChildren = "dept_and_children" // Rows of the department of the user and of its children
+	RoleDataScopeSelf            = "self"              // Rows of the user
+	RoleDataScopeCustom          = "custom"            // Rows of the departments of the role
 )
 
 // Role management for RBAC
 type Role struct {
-	ID          int64     `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
-	Code        string    `json:"code" gorm:"size:32;index;"`                  // Code of role (unique)
-	Name        string    `json:"name" gorm:"size:128;index"`                  // Display name of role
-	Description string    `json:"description" gorm:"size:1024"`                // Details about role
-	Sequence    int       `json:"sequence" gorm:"index"`                       // Sequence for sorting
-	Status      string    `json:"status" gorm:"size:20;index"`                 // Status of role (disabled, enabled)
-	CreatedAt   time.Time `json:"created_at" gorm:"index;"`                    // Create time
-	UpdatedAt   time.Time `json:"updated_at" gorm:"index;"`                    // Update time
-	Menus       RoleMenus `json:"menus" gorm:"-"`                              // Role menu list
+	ID               int64         `json:"id" gorm:"size:64;primarykey;autoIncrement;"`          // Unique ID
+	TenantID         int64         `json:"tenant_id" gorm:"size:64;index"`                       // From Tenant.ID
+	Code             string        `json:"code" gorm:"size:32;index;"`                           // Code of role (unique)
+	Name             string        `json:"name" gorm:"size:128;index"`                           // Display name of role
+	Description      string        `json:"description" gorm:"size:1024"`                         // Details about role
+	Sequence         int           `json:"sequence" gorm:"index"`                                // Sequence for sorting
+	Status           string        `json:"status" gorm:"size:20;index"`                          // Status of role (disabled, enabled)
+	RequireTwoFactor bool          `json:"require_two_factor"`                                   // Users of the role must sign in with two-factor authentication
+	DataScope        string        `json:"data_scope" gorm:"size:20;default:all"`                // Rows visible to the users of role (all, dept, dept_and_children, self, custom)
+	DataScopeDeptIDs []int64       `json:"data_scope_dept_ids" gorm:"size:1024;serializer:json"` // Departments of the custom data scope
+	CreatedAt        time.Time     `json:"created_at" gorm:"index;"`                             // Create time
+	UpdatedAt        time.Time     `json:"updated_at" gorm:"index;"`                             // Update time
+	Menus            RoleMenus     `json:"menus" gorm:"-"`                                       // Role menu list
+	ParentIDs        []int64       `json:"parent_ids" gorm:"-"`                                  // Inherited roles (From Role.ID)
+	EffectiveMenus   RoleMenus     `json:"effective_menus,omitempty" gorm:"-"`                   // Menus of the role and of the inherited roles
+	Resources        RoleResources `json:"resources" gorm:"-"`                                   // Resources allowed or denied to the role, whatever its menus
 }
 
 func (a *Role) TableName() string {
@@ -39,6 +52,7 @@ type RoleQueryParam struct {
 	Status      string     `form:"status" binding:"oneof=disabled enabled ''"` // Status of role (disabled, enabled)
 	ResultType  string     `form:"resultType"`                                 // Result type (options: select)
 	InIDs       []int64    `form:"-"`                                          // ID list
+	InCodes     []string   `form:"-"`                                          // Code list
 	GtUpdatedAt *time.Time `form:"-"`                                          // Update time is greater than
 }
 
@@ -58,17 +72,33 @@ type Roles []*Role
 
 // RoleForm Defining the data structure for creating a `Role` struct.
 type RoleForm struct {
-	Code        string    `json:"code" binding:"required,max=32"`                   // Code of role (unique)
-	Name        string    `json:"name" binding:"required,max=128"`                  // Display name of role
-	Description string    `json:"description"`                                      // Details about role
-	Sequence    int       `json:"sequence"`                                         // Sequence for sorting
-	Status      string    `json:"status" binding:"required,oneof=disabled enabled"` // Status of role (enabled, disabled)
-	Menus       RoleMenus `json:"menus"`                                            // Role menu list
+	Code             string        `json:"code" binding:"required,max=32"`                                              // Code of role (unique)
+	Name             string        `json:"name" binding:"required,max=128"`                                             // Display name of role
+	Description      string        `json:"description"`                                                                 // Details about role
+	Sequence         int           `json:"sequence"`                                                                    // Sequence for sorting
+	Status           string        `json:"status" binding:"required,oneof=disabled enabled"`                            // Status of role (enabled, disabled)
+	RequireTwoFactor bool          `json:"require_two_factor"`                                                          // Users of the role must sign in with two-factor authentication
+	DataScope        string        `json:"data_scope" binding:"omitempty,oneof=all dept dept_and_children self custom"` // Rows visible to the users of role (default all)
+	DataScopeDeptIDs []int64       `json:"data_scope_dept_ids"`                                                         // Departments of the custom data scope
+	Menus            RoleMenus     `json:"menus"`                                                                       // Role menu list
+	ParentIDs        []int64       `json:"parent_ids"`                                                                  // Inherited roles (From Role.ID)
+	Resources        RoleResources `json:"resources"`                                                                   // Resources allowed or denied to the role, whatever its menus
 }
 
 // Validate A validation function for the `RoleForm` struct.
 func (a *RoleForm) Validate() error {
-	return nil
+	// Duplicated parents are ignored
+	parentIDs := make([]int64, 0, len(a.ParentIDs))
+	exists := make(map[int64]struct{}, len(a.ParentIDs))
+	for _, id := range a.ParentIDs {
+		if _, ok := exists[id]; ok {
+			continue
+		}
+		exists[id] = struct{}{}
+		parentIDs = append(parentIDs, id)
+	}
+	a.ParentIDs = parentIDs
+	return a.Resources.Validate()
 }
 
 func (a *RoleForm) FillTo(role *Role) error {
@@ -77,5 +107,14 @@ func (a *RoleForm) FillTo(role *Role) error {
 	role.Description = a.Description
 	role.Sequence = a.Sequence
 	role.Status = a.Status
+	role.RequireTwoFactor = a.RequireTwoFactor
+	role.DataScope = a.DataScope
+	if role.DataScope == "" {
+		role.DataScope = RoleDataScopeAll
+	}
+	role.DataScopeDeptIDs = nil
+	if role.DataScope == RoleDataScopeCustom {
+		role.DataScopeDeptIDs = a.DataScopeDeptIDs
+	}
 	return nil
 }
@@ -10,6 +10,7 @@ import (
 // RoleMenu Role permissions for RBAC
 type RoleMenu struct {
 	ID        int64     `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
+	TenantID  int64     `json:"tenant_id" gorm:"size:64;index"`              // From Tenant.ID
 	RoleID    int64     `json:"role_id" gorm:"size:64;index"`                // From Role.ID
 	MenuID    int64     `json:"menu_id" gorm:"size:64;index"`                // From Menu.ID
 	CreatedAt time.Time `json:"created_at" gorm:"index;"`                    // Create time
@@ -23,7 +24,9 @@ func (a *RoleMenu) TableName() string {
 // RoleMenuQueryParam Defining the query parameters for the `RoleMenu` struct.
 type RoleMenuQueryParam struct {
 	util.PaginationParam
-	RoleID int64 `form:"-"` // From Role.ID
+	RoleID    int64   `form:"-"` // From Role.ID
+	InRoleIDs []int64 `form:"-"` // From Role.ID
+	InMenuIDs []int64 `form:"-"` // From Menu.ID
 }
 
 // RoleMenuQueryOptions Defining the query options for the `RoleMenu` struct.
@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// RoleParent Role inheritance for RBAC, the role is granted the permissions of the parent role
+type RoleParent struct {
+	ID        int64     `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
+	RoleID    int64     `json:"role_id" gorm:"size:64;index"`                // From Role.ID
+	ParentID  int64     `json:"parent_id" gorm:"size:64;index"`              // From Role.ID
+	CreatedAt time.Time `json:"created_at" gorm:"index;"`                    // Create time
+}
+
+func (a *RoleParent) TableName() string {
+	return config.C.FormatTableName("role_parent")
+}
+
+// RoleParentQueryParam Defining the query parameters for the `RoleParent` struct.
+type RoleParentQueryParam struct {
+	util.PaginationParam
+	RoleID    int64   `form:"-"` // From Role.ID
+	InRoleIDs []int64 `form:"-"` // From Role.ID
+}
+
+// RoleParentQueryOptions Defining the query options for the `RoleParent` struct.
+type RoleParentQueryOptions struct {
+	util.QueryOptions
+}
+
+// RoleParentQueryResult Defining the query result for the `RoleParent` struct.
+type RoleParentQueryResult struct {
+	Data       RoleParents
+	PageResult *util.PaginationResult
+}
+
+// RoleParents Defining the slice of `RoleParent` struct.
+type RoleParents []*RoleParent
+
+func (a RoleParents) ToParentIDs() []int64 {
+	var ids []int64
+	for _, item := range a {
+		ids = append(ids, item.ParentID)
+	}
+	return ids
+}
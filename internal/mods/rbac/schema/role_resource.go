@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// RoleResource Resource allowed or denied to a role, it overrides the resources of the menus of the role (e.g. deny a
+// single method of the resources of a menu)
+type RoleResource struct {
+	ID        int64     `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
+	TenantID  int64     `json:"tenant_id" gorm:"size:64;index"`              // From Tenant.ID
+	RoleID    int64     `json:"role_id" gorm:"size:64;index"`                // From Role.ID
+	Method    string    `json:"method" gorm:"size:64;"`                      // HTTP method
+	Path      string    `json:"path" gorm:"size:255;"`                       // API request path (e.g. /api/v1/users/:id)
+	Effect    string    `json:"effect" gorm:"size:20;default:allow"`         // Effect of resource (allow, deny)
+	CreatedAt time.Time `json:"created_at" gorm:"index;"`                    // Create time
+}
+
+func (a *RoleResource) TableName() string {
+	return config.C.FormatTableName("role_resource")
+}
+
+// RoleResourceQueryParam Defining the query parameters for the `RoleResource` struct.
+type RoleResourceQueryParam struct {
+	util.PaginationParam
+	RoleID    int64   `form:"-"` // From Role.ID
+	InRoleIDs []int64 `form:"-"` // From Role.ID
+	Method    string  `form:"-"` // HTTP method
+	Path      string  `form:"-"` // API request path
+	Effect    string  `form:"-"` // Effect of resource (allow, deny)
+}
+
+// RoleResourceQueryOptions Defining the query options for the `RoleResource` struct.
+type RoleResourceQueryOptions struct {
+	util.QueryOptions
+}
+
+// RoleResourceQueryResult Defining the query result for the `RoleResource` struct.
+type RoleResourceQueryResult struct {
+	Data       RoleResources
+	PageResult *util.PaginationResult
+}
+
+// RoleResources Defining the slice of `RoleResource` struct.
+type RoleResources []*RoleResource
+
+// Validate Check the effects of the resources.
+func (a RoleResources) Validate() error {
+	for _, item := range a {
+		if item.Method == "" || item.Path == "" {
+			return errors.BadRequest("", "Method and path of role resources are required")
+		}
+		if err := checkResourceEffect(&item.Effect); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Route API route registered in the HTTP server
+type Route struct {
+	Method    string `json:"method"`    // HTTP method
+	Path      string `json:"path"`      // Route path (e.g. /api/v1/users/:id)
+	Handler   string `json:"handler"`   // Name of the handler (e.g. api.(*User).Get)
+	Protected bool   `json:"protected"` // Checked by casbin, the route must be granted by a menu resource
+}
+
+// Routes Defining the slice of `Route` struct.
+type Routes []*Route
+
+// RouteQueryParam Defining the query parameters for the `Route` struct.
+type RouteQueryParam struct {
+	LikePath  string `form:"path"`      // Route path
+	Protected bool   `form:"protected"` // Only the routes checked by casbin
+}
+
+// RouteReportResource Menu resource matching no registered route
+type RouteReportResource struct {
+	MenuID   int64  `json:"menu_id"`   // From Menu.ID
+	CodePath string `json:"code_path"` // Code path of the menu (like xxx.xxx.xxx)
+	Method   string `json:"method"`    // HTTP method
+	Path     string `json:"path"`      // API request path
+	Effect   string `json:"effect"`    // Effect of resource (allow, deny)
+}
+
+// RouteReport Validation of the menu resources against the registered routes
+type RouteReport struct {
+	Routes          int                    `json:"routes"`                     // Number of registered routes
+	StaleResources  []*RouteReportResource `json:"stale_resources,omitempty"`  // Menu resources matching no route
+	UncoveredRoutes Routes                 `json:"uncovered_routes,omitempty"` // Protected routes allowed by no menu resource
+}
+
+// Valid Whether every menu resource matches a route and every protected route is allowed by a menu resource.
+func (a *RouteReport) Valid() bool {
+	return len(a.StaleResources) == 0 && len(a.UncoveredRoutes) == 0
+}
+
+func (a *RouteReport) String() string {
+	lines := []string{fmt.Sprintf("%d routes, %d stale resources, %d uncovered routes",
+		a.Routes, len(a.StaleResources), len(a.UncoveredRoutes))}
+	for _, res := range a.StaleResources {
+		lines = append(lines, fmt.Sprintf("stale resource %s %s (%s) of menu %s #%d",
+			res.Method, res.Path, res.Effect, res.CodePath, res.MenuID))
+	}
+	for _, route := range a.UncoveredRoutes {
+		lines = append(lines, fmt.Sprintf("uncovered route %s %s (%s)", route.Method, route.Path, route.Handler))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,47 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/supermicah/go-framework-admin/pkg/encoding/json"
+)
+
+// Session An active login of a user, it is shared by all tokens rotated from the same login
+type Session struct {
+	ID          string    `json:"id"`           // Session ID (token family ID)
+	UserID      int64     `json:"user_id"`      // From User.ID
+	TokenID     string    `json:"token_id"`     // ID of the latest access token (jti)
+	ClientIP    string    `json:"client_ip"`    // Client IP of the login
+	UserAgent   string    `json:"user_agent"`   // User agent of the login
+	IssuedAt    time.Time `json:"issued_at"`    // Login time
+	RefreshedAt time.Time `json:"refreshed_at"` // Last token refresh time
+	ExpiresAt   time.Time `json:"expires_at"`   // Expired time of the session (refresh token)
+	Current     bool      `json:"current"`      // Whether it is the session of the request
+}
+
+func (a *Session) String() string {
+	return json.MarshalToString(a)
+}
+
+func ParseSession(s string) (*Session, error) {
+	item := new(Session)
+	if err := json.Unmarshal([]byte(s), item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Sessions Defining the slice of `Session` struct, sorted by login time (newest first).
+type Sessions []*Session
+
+func (a Sessions) Len() int {
+	return len(a)
+}
+
+func (a Sessions) Less(i, j int) bool {
+	return a[i].IssuedAt.After(a[j].IssuedAt)
+}
+
+func (a Sessions) Swap(i, j int) {
+	a[i], a[j] = a[j], a[i]
+}
@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+const (
+	TenantStatusEnabled  = "enabled"  // Enabled
+	TenantStatusDisabled = "disabled" // Disabled
+)
+
+// tenantCodeRegexp The code of a tenant is used as a subdomain
+var tenantCodeRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// Tenant Isolated organization, the users, roles and role permissions belong to a tenant
+type Tenant struct {
+	ID          int64     `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
+	Code        string    `json:"code" gorm:"size:32;uniqueIndex;"`            // Code of tenant (unique, used by the header and the subdomain)
+	Name        string    `json:"name" gorm:"size:128;index"`                  // Display name of tenant
+	Description string    `json:"description" gorm:"size:1024"`                // Details about tenant
+	Status      string    `json:"status" gorm:"size:20;index"`                 // Status of tenant (disabled, enabled)
+	CreatedAt   time.Time `json:"created_at" gorm:"index;"`                    // Create time
+	UpdatedAt   time.Time `json:"updated_at" gorm:"index;"`                    // Update time
+}
+
+func (a *Tenant) TableName() string {
+	return config.C.FormatTableName("tenant")
+}
+
+// TenantQueryParam Defining the query parameters for the `Tenant` struct.
+type TenantQueryParam struct {
+	util.PaginationParam
+	LikeName string `form:"name"`                                       // Display name of tenant
+	LikeCode string `form:"code"`                                       // Code of tenant
+	Status   string `form:"status" binding:"oneof=disabled enabled ''"` // Status of tenant (disabled, enabled)
+}
+
+// TenantQueryOptions Defining the query options for the `Tenant` struct.
+type TenantQueryOptions struct {
+	util.QueryOptions
+}
+
+// TenantQueryResult Defining the query result for the `Tenant` struct.
+type TenantQueryResult struct {
+	Data       Tenants
+	PageResult *util.PaginationResult
+}
+
+// Tenants Defining the slice of `Tenant` struct.
+type Tenants []*Tenant
+
+// TenantForm Defining the data structure for creating a `Tenant` struct.
+type TenantForm struct {
+	Code        string `json:"code" binding:"required,max=32"`                   // Code of tenant (unique, lowercase letters, digits and hyphens)
+	Name        string `json:"name" binding:"required,max=128"`                  // Display name of tenant
+	Description string `json:"description"`                                      // Details about tenant
+	Status      string `json:"status" binding:"required,oneof=disabled enabled"` // Status of tenant (disabled, enabled)
+}
+
+// Validate A validation function for the `TenantForm` struct.
+func (a *TenantForm) Validate() error {
+	if !tenantCodeRegexp.MatchString(a.Code) {
+		return errors.BadRequest("", "Tenant code must only contain lowercase letters, digits and hyphens")
+	}
+	return nil
+}
+
+func (a *TenantForm) FillTo(tenant *Tenant) error {
+	tenant.Code = a.Code
+	tenant.Name = a.Name
+	tenant.Description = a.Description
+	tenant.Status = a.Status
+	return nil
+}
@@ -0,0 +1,37 @@
+package schema
+
+import "strings"
+
+// TwoFactorSetup Pending TOTP secret to be added to an authenticator app
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`  // Base32 encoded secret (for manual entry)
+	URI    string `json:"uri"`     // otpauth URI
+	QRCode string `json:"qr_code"` // QR code of the URI (PNG data URL)
+}
+
+// TwoFactorRecoveryCodes One-time recovery codes, only returned when generated
+type TwoFactorRecoveryCodes struct {
+	Codes []string `json:"codes"` // Recovery codes (each can be used once instead of a TOTP code)
+}
+
+// TwoFactorCodeForm Defining the data structure for verifying a two-factor code.
+type TwoFactorCodeForm struct {
+	Code string `json:"code" binding:"required,max=16"` // TOTP code or recovery code
+}
+
+func (a *TwoFactorCodeForm) Trim() *TwoFactorCodeForm {
+	a.Code = strings.TrimSpace(a.Code)
+	return a
+}
+
+// TwoFactorChallengeForm Defining the data structure for the second step of login.
+type TwoFactorChallengeForm struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"` // Challenge token returned by login
+	Code           string `json:"code" binding:"max=16"`              // TOTP code or recovery code (required except for setup)
+}
+
+func (a *TwoFactorChallengeForm) Trim() *TwoFactorChallengeForm {
+	a.ChallengeToken = strings.TrimSpace(a.ChallengeToken)
+	a.Code = strings.TrimSpace(a.Code)
+	return a
+}
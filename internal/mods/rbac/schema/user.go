@@ -18,29 +18,58 @@ const (
 
 // User management for RBAC
 type User struct {
-	ID        int64     `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
-	Username  string    `json:"username" gorm:"size:64;index"`               // Username for login
-	Name      string    `json:"name" gorm:"size:64;index"`                   // Name of user
-	Password  string    `json:"-" gorm:"size:64;"`                           // Password for login (encrypted)
-	Phone     string    `json:"phone" gorm:"size:32;"`                       // Phone number of user
-	Email     string    `json:"email" gorm:"size:128;"`                      // Email of user
-	Remark    string    `json:"remark" gorm:"size:1024;"`                    // Remark of user
-	Status    string    `json:"status" gorm:"size:20;index"`                 // Status of user (activated, freezed)
-	CreatedAt time.Time `json:"created_at" gorm:"index;"`                    // Create time
-	UpdatedAt time.Time `json:"updated_at" gorm:"index;"`                    // Update time
-	Roles     UserRoles `json:"roles" gorm:"-"`                              // Roles of user
+	ID                int64      `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
+	TenantID          int64      `json:"tenant_id" gorm:"size:64;index"`              // From Tenant.ID
+	DeptID            int64      `json:"dept_id" gorm:"size:64;index"`                // Primary department of user (From Dept.ID, managed by the department members)
+	Username          string     `json:"username" gorm:"size:64;index"`               // Username for login
+	Name              string     `json:"name" gorm:"size:64;index"`                   // Name of user
+	Password          string     `json:"-" gorm:"size:64;"`                           // Password for login (encrypted)
+	Phone             string     `json:"phone" gorm:"size:32;"`                       // Phone number of user
+	Email             string     `json:"email" gorm:"size:128;"`                      // Email of user
+	Remark            string     `json:"remark" gorm:"size:1024;"`                    // Remark of user
+	Locale            string     `json:"locale" gorm:"size:20;"`                      // Preferred locale of user (e.g. en-US), else negotiated from Accept-Language
+	Status            string     `json:"status" gorm:"size:20;index"`                 // Status of user (activated, freezed)
+	TokenVersion      int64      `json:"-" gorm:"default:0;"`                         // Version of credentials, bumped to invalidate all issued tokens
+	TwoFactorSecret   string     `json:"-" gorm:"size:256;"`                          // TOTP secret (AES encrypted), pending until two-factor is enabled
+	TwoFactorEnabled  bool       `json:"two_factor_enabled"`                          // Whether TOTP two-factor authentication is enabled
+	RecoveryCodes     []string   `json:"-" gorm:"size:2048;serializer:json"`          // SHA256 hashes of unused recovery codes
+	PasswordChangedAt *time.Time `json:"password_changed_at"`                         // Last time the password was set
+	PasswordExpired   bool       `json:"password_expired"`                            // Whether the password must be changed at next login (set by reset)
+	CreatedBy         int64      `json:"created_by" gorm:"size:64;index"`             // From User.ID (creator)
+	CreatedAt         time.Time  `json:"created_at" gorm:"index;"`                    // Create time
+	UpdatedAt         time.Time  `json:"updated_at" gorm:"index;"`                    // Update time
+	Roles             UserRoles  `json:"roles" gorm:"-"`                              // Roles of user
 }
 
 func (a *User) TableName() string {
 	return config.C.FormatTableName("user")
 }
 
+// IsPasswordExpired Reports whether the password was marked as expired or is older than the maximum age of the
+// password policy, users without a change time are measured from their creation.
+func (a *User) IsPasswordExpired() bool {
+	if a.PasswordExpired {
+		return true
+	}
+
+	maxAge := config.C.General.PasswordPolicy.MaxAge
+	if maxAge <= 0 {
+		return false
+	}
+	changedAt := a.CreatedAt
+	if a.PasswordChangedAt != nil {
+		changedAt = *a.PasswordChangedAt
+	}
+	return time.Since(changedAt) > time.Duration(maxAge)*24*time.Hour
+}
+
 // UserQueryParam Defining the query parameters for the `User` struct.
 type UserQueryParam struct {
 	util.PaginationParam
 	LikeUsername string `form:"username"`                                    // Username for login
 	LikeName     string `form:"name"`                                        // Name of user
 	Status       string `form:"status" binding:"oneof=activated freezed ''"` // Status of user (activated, freezed)
+	Email        string `form:"-"`                                           // Email of user (case-insensitive)
 }
 
 // UserQueryOptions Defining the query options for the `User` struct.
@@ -82,7 +111,7 @@ func (a *UserForm) Validate() error {
 	if a.Email != "" && validator.New().Var(a.Email, "email") != nil {
 		return errors.BadRequest("", "Invalid email address")
 	}
-	return nil
+	return a.Roles.Validate()
 }
 
 // FillTo Convert `UserForm` to `User` object.
@@ -104,3 +133,8 @@ func (a *UserForm) FillTo(user *User) error {
 
 	return nil
 }
+
+// ResetPasswordForm Defining the data structure for resetting the password of a `User` struct.
+type ResetPasswordForm struct {
+	Password string `json:"password" binding:"max=64"` // New password (empty means the default password), must be changed at next login
+}
@@ -0,0 +1,28 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+)
+
+// UserIdentity External identity of a user, linking the subject of a single sign-on provider to the user
+type UserIdentity struct {
+	ID        int64     `json:"id" gorm:"size:64;primarykey;autoIncrement;"`              // Unique ID
+	UserID    int64     `json:"user_id" gorm:"size:64;index"`                             // From User.ID
+	Provider  string    `json:"provider" gorm:"size:64;uniqueIndex:idx_identity_subject"` // Name of the provider
+	Subject   string    `json:"subject" gorm:"size:255;uniqueIndex:idx_identity_subject"` // Subject (sub claim) at the provider
+	CreatedAt time.Time `json:"created_at" gorm:"index;"`                                 // Create time
+}
+
+func (a *UserIdentity) TableName() string {
+	return config.C.FormatTableName("user_identity")
+}
+
+// OIDCCallbackForm Defining the parameters of the redirect back from the provider.
+type OIDCCallbackForm struct {
+	Code             string `form:"code"`              // Authorization code
+	State            string `form:"state"`             // State of the login request
+	Error            string `form:"error"`             // Error code of the provider
+	ErrorDescription string `form:"error_description"` // Error description of the provider
+}
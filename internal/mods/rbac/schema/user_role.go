@@ -4,29 +4,42 @@ import (
 	"time"
 
 	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
 	"github.com/supermicah/go-framework-admin/pkg/util"
 )
 
 // UserRole User roles for RBAC
 type UserRole struct {
-	ID        int64     `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
-	UserID    int64     `json:"user_id" gorm:"size:64;index"`                // From User.ID
-	RoleID    int64     `json:"role_id" gorm:"size:64;index"`                // From Role.ID
-	CreatedAt time.Time `json:"created_at" gorm:"index;"`                    // Create time
-	UpdatedAt time.Time `json:"updated_at" gorm:"index;"`                    // Update time
-	RoleName  string    `json:"role_name" gorm:"<-:false;-:migration;"`      // From Role.Name
+	ID        int64      `json:"id" gorm:"size:64;primarykey;autoIncrement;"` // Unique ID
+	TenantID  int64      `json:"tenant_id" gorm:"size:64;index"`              // From Tenant.ID
+	UserID    int64      `json:"user_id" gorm:"size:64;index"`                // From User.ID
+	RoleID    int64      `json:"role_id" gorm:"size:64;index"`                // From Role.ID
+	StartAt   *time.Time `json:"start_at" gorm:"index;"`                      // Granted from this time (empty means immediately)
+	ExpireAt  *time.Time `json:"expire_at" gorm:"index;"`                     // Granted until this time (empty means never expires)
+	CreatedAt time.Time  `json:"created_at" gorm:"index;"`                    // Create time
+	UpdatedAt time.Time  `json:"updated_at" gorm:"index;"`                    // Update time
+	RoleName  string     `json:"role_name" gorm:"<-:false;-:migration;"`      // From Role.Name
 }
 
 func (a *UserRole) TableName() string {
 	return config.C.FormatTableName("user_role")
 }
 
+// IsActive Whether the role is granted at the time, that is within the validity window of the assignment.
+func (a *UserRole) IsActive(now time.Time) bool {
+	if a.StartAt != nil && a.StartAt.After(now) {
+		return false
+	}
+	return a.ExpireAt == nil || a.ExpireAt.After(now)
+}
+
 // UserRoleQueryParam Defining the query parameters for the `UserRole` struct.
 type UserRoleQueryParam struct {
 	util.PaginationParam
 	InUserIDs []int64 `form:"-"` // From User.ID
 	UserID    int64   `form:"-"` // From User.ID
 	RoleID    int64   `form:"-"` // From Role.ID
+	Active    bool    `form:"-"` // Only the assignments within their validity window
 }
 
 // UserRoleQueryOptions Defining the query options for the `UserRole` struct.
@@ -52,6 +65,40 @@ func (a UserRoles) ToUserIDMap() map[int64]UserRoles {
 	return m
 }
 
+// Active The assignments within their validity window at the time.
+func (a UserRoles) Active(now time.Time) UserRoles {
+	list := make(UserRoles, 0, len(a))
+	for _, item := range a {
+		if item.IsActive(now) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// NextChangeAt The earliest time after now an assignment starts or expires, zero if the granted roles never change.
+func (a UserRoles) NextChangeAt(now time.Time) time.Time {
+	var next time.Time
+	for _, item := range a {
+		for _, t := range []*time.Time{item.StartAt, item.ExpireAt} {
+			if t != nil && t.After(now) && (next.IsZero() || t.Before(next)) {
+				next = *t
+			}
+		}
+	}
+	return next
+}
+
+// Validate The validity window of each assignment must not end before it starts.
+func (a UserRoles) Validate() error {
+	for _, item := range a {
+		if item.StartAt != nil && item.ExpireAt != nil && !item.ExpireAt.After(*item.StartAt) {
+			return errors.BadRequest("", "The role must expire after it starts")
+		}
+	}
+	return nil
+}
+
 func (a UserRoles) ToRoleIDs() []int64 {
 	var ids []int64
 	for _, item := range a {
@@ -11,19 +11,53 @@ import (
 // Set Collection of wire providers
 var Set = wire.NewSet(
 	wire.Struct(new(RBAC), "*"),
-	wire.Struct(new(Casbinx), "*"),
+	wire.Struct(new(biz.Casbinx), "*"),
+	wire.Struct(new(dal.CasbinAdapter), "*"),
+	wire.Struct(new(biz.Event), "*"),
 	wire.Struct(new(dal.Menu), "*"),
 	wire.Struct(new(biz.Menu), "*"),
 	wire.Struct(new(api.Menu), "*"),
 	wire.Struct(new(dal.MenuResource), "*"),
+	wire.Struct(new(dal.MenuI18n), "*"),
 	wire.Struct(new(dal.Role), "*"),
 	wire.Struct(new(biz.Role), "*"),
 	wire.Struct(new(api.Role), "*"),
 	wire.Struct(new(dal.RoleMenu), "*"),
+	wire.Struct(new(dal.RoleParent), "*"),
+	wire.Struct(new(dal.RoleResource), "*"),
 	wire.Struct(new(dal.User), "*"),
 	wire.Struct(new(biz.User), "*"),
 	wire.Struct(new(api.User), "*"),
 	wire.Struct(new(dal.UserRole), "*"),
 	wire.Struct(new(biz.Login), "*"),
 	wire.Struct(new(api.Login), "*"),
+	wire.Struct(new(biz.Session), "*"),
+	wire.Struct(new(api.Session), "*"),
+	wire.Struct(new(dal.APIKey), "*"),
+	wire.Struct(new(biz.APIKey), "*"),
+	wire.Struct(new(api.APIKey), "*"),
+	wire.Struct(new(biz.TwoFactor), "*"),
+	wire.Struct(new(api.TwoFactor), "*"),
+	wire.Struct(new(biz.Lockout), "*"),
+	wire.Struct(new(dal.PasswordHistory), "*"),
+	wire.Struct(new(biz.Password), "*"),
+	wire.Struct(new(biz.PasswordReset), "*"),
+	wire.Struct(new(api.PasswordReset), "*"),
+	wire.Struct(new(dal.UserIdentity), "*"),
+	wire.Struct(new(biz.Identity), "*"),
+	wire.Struct(new(biz.OIDC), "*"),
+	wire.Struct(new(biz.LocalAuth), "*"),
+	wire.Struct(new(biz.LDAPAuth), "*"),
+	wire.Struct(new(api.OIDC), "*"),
+	wire.Struct(new(dal.Tenant), "*"),
+	wire.Struct(new(biz.Tenant), "*"),
+	wire.Struct(new(api.Tenant), "*"),
+	wire.Struct(new(biz.DataScope), "*"),
+	wire.Struct(new(biz.Permission), "*"),
+	wire.Struct(new(api.Permission), "*"),
+	wire.Struct(new(biz.UserRoleSweeper), "*"),
+	wire.Struct(new(biz.Bundle), "*"),
+	wire.Struct(new(api.Bundle), "*"),
+	wire.Struct(new(biz.Route), "*"),
+	wire.Struct(new(api.Route), "*"),
 )
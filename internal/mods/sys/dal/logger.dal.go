@@ -6,6 +6,7 @@ import (
 
 	"gorm.io/gorm"
 
+	rbacDAL "github.com/supermicah/go-framework-admin/internal/mods/rbac/dal"
 	rbacSchema "github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
 	"github.com/supermicah/go-framework-admin/internal/mods/sys/schema"
 	"github.com/supermicah/go-framework-admin/pkg/errors"
@@ -32,6 +33,7 @@ func (a *Logger) Query(ctx context.Context, params schema.LoggerQueryParam, opts
 	db := a.DB.Table(fmt.Sprintf("%s AS a", new(schema.Logger).TableName()))
 	db = db.Joins(fmt.Sprintf("left join %s b on a.user_id=b.id", new(rbacSchema.User).TableName()))
 	db = db.Select("a.*,b.name as user_name,b.username as login_name")
+	db = db.Scopes(rbacDAL.DataScope(ctx, a.DB, "a.user_id"))
 
 	if v := params.Level; v != "" {
 		db = db.Where("a.level = ?", v)
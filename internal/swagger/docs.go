@@ -15,6 +15,135 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/v1/bundles/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml"
+                ],
+                "tags": [
+                    "BundleAPI"
+                ],
+                "summary": "Export the menus, roles and optionally the role bindings of the users as a JSON or YAML bundle",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Include the role bindings of the users",
+                        "name": "users",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format of the bundle (json, yaml)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Bundle"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/bundles/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json",
+                    "application/yaml"
+                ],
+                "tags": [
+                    "BundleAPI"
+                ],
+                "summary": "Import a JSON or YAML bundle, creating or updating the menus and roles by code (YAML if the content type is YAML)",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.Bundle"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format of the bundle (json, yaml), default from the content type",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.BundleImportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/captcha/id": {
             "get": {
                 "tags": [
@@ -80,7 +209,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/current/logout": {
+        "/api/v1/current/2fa/disable": {
             "post": {
                 "security": [
                     {
@@ -88,9 +217,20 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "LoginAPI"
+                    "TwoFactorAPI"
+                ],
+                "summary": "Disable two-factor with a TOTP or recovery code",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.TwoFactorCodeForm"
+                        }
+                    }
                 ],
-                "summary": "Logout system",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -98,6 +238,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/util.ResponseResult"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -107,17 +259,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/current/menus": {
-            "get": {
+        "/api/v1/current/2fa/enable": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "LoginAPI"
+                    "TwoFactorAPI"
+                ],
+                "summary": "Enable two-factor by verifying the first code of the pending secret (recovery codes are only returned once)",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.TwoFactorCodeForm"
+                        }
+                    }
                 ],
-                "summary": "Query current user menus based on the current user role",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -130,16 +293,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/schema.Menu"
-                                            }
+                                            "$ref": "#/definitions/schema.TwoFactorRecoveryCodes"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -155,17 +321,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/current/password": {
-            "put": {
+        "/api/v1/current/2fa/recovery-codes": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "LoginAPI"
+                    "TwoFactorAPI"
                 ],
-                "summary": "Change current user password",
+                "summary": "Replace the recovery codes (the previous codes become invalid)",
                 "parameters": [
                     {
                         "description": "Request body",
@@ -173,7 +339,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.UpdateLoginPassword"
+                            "$ref": "#/definitions/schema.TwoFactorCodeForm"
                         }
                     }
                 ],
@@ -181,7 +347,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/util.ResponseResult"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.TwoFactorRecoveryCodes"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -205,7 +383,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/current/refresh-token": {
+        "/api/v1/current/2fa/setup": {
             "post": {
                 "security": [
                     {
@@ -213,9 +391,9 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "LoginAPI"
+                    "TwoFactorAPI"
                 ],
-                "summary": "Refresh current access token",
+                "summary": "Generate a pending TOTP secret with its otpauth URI and QR code",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -228,13 +406,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/schema.LoginToken"
+                                            "$ref": "#/definitions/schema.TwoFactorSetup"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -250,7 +434,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/current/user": {
+        "/api/v1/current/api-keys": {
             "get": {
                 "security": [
                     {
@@ -258,9 +442,33 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "LoginAPI"
+                    "APIKeyAPI"
+                ],
+                "summary": "Query API keys of the current user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "pagination index",
+                        "name": "current",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "pagination size",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display name of API key",
+                        "name": "name",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get current user info",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -273,7 +481,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/schema.User"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.APIKey"
+                                            }
                                         }
                                     }
                                 }
@@ -294,16 +505,16 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "LoginAPI"
+                    "APIKeyAPI"
                 ],
-                "summary": "Update current user info",
+                "summary": "Create API key record (the key is only returned once)",
                 "parameters": [
                     {
                         "description": "Request body",
@@ -311,7 +522,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.UpdateCurrentUser"
+                            "$ref": "#/definitions/schema.APIKeyForm"
                         }
                     }
                 ],
@@ -319,7 +530,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/util.ResponseResult"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.APIKey"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -343,7 +566,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/loggers": {
+        "/api/v1/current/api-keys/{id}": {
             "get": {
                 "security": [
                     {
@@ -351,67 +574,16 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "LoggerAPI"
+                    "APIKeyAPI"
                 ],
-                "summary": "Query logger list",
+                "summary": "Get API key record by ID",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "pagination index",
-                        "name": "current",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "pagination size",
-                        "name": "pageSize",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
-                        "description": "log level",
-                        "name": "level",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "trace ID",
-                        "name": "traceID",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "user name",
-                        "name": "userName",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "log tag",
-                        "name": "tag",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "log message",
-                        "name": "message",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "start time",
-                        "name": "startTime",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "end time",
-                        "name": "endTime",
-                        "in": "query"
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -426,10 +598,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/schema.Logger"
-                                            }
+                                            "$ref": "#/definitions/schema.APIKey"
                                         }
                                     }
                                 }
@@ -449,22 +618,32 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/login": {
-            "post": {
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "tags": [
-                    "LoginAPI"
+                    "APIKeyAPI"
                 ],
-                "summary": "Login system with username and password",
+                "summary": "Update API key record by ID",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "Request body",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.LoginForm"
+                            "$ref": "#/definitions/schema.APIKeyForm"
                         }
                     }
                 ],
@@ -472,19 +651,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/util.ResponseResult"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/schema.LoginToken"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/util.ResponseResult"
                         }
                     },
                     "400": {
@@ -493,6 +660,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/util.ResponseResult"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -500,59 +673,31 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/menus": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "MenuAPI"
+                    "APIKeyAPI"
                 ],
-                "summary": "Query menu tree data",
+                "summary": "Delete API key record by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Code path of menu (like xxx.xxx.xxx)",
-                        "name": "code",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Name of menu",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Whether to include menu resources",
-                        "name": "includeResources",
-                        "in": "query"
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/util.ResponseResult"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/schema.Menu"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/util.ResponseResult"
                         }
                     },
                     "401": {
@@ -568,7 +713,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/current/logout": {
             "post": {
                 "security": [
                     {
@@ -576,47 +723,12 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "MenuAPI"
-                ],
-                "summary": "Create menu record",
-                "parameters": [
-                    {
-                        "description": "Request body",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/schema.MenuForm"
-                        }
-                    }
+                    "LoginAPI"
                 ],
+                "summary": "Logout system",
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/util.ResponseResult"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/schema.Menu"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/util.ResponseResult"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/util.ResponseResult"
                         }
@@ -630,7 +742,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/menus/{id}": {
+        "/api/v1/current/menus": {
             "get": {
                 "security": [
                     {
@@ -638,16 +750,15 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "MenuAPI"
+                    "LoginAPI"
                 ],
-                "summary": "Get menu record by ID",
+                "summary": "Query current user menus based on the current user role",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "unique id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Locale of the menu names, unless the user has a preferred locale",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -662,7 +773,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/schema.Menu"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.Menu"
+                                            }
                                         }
                                     }
                                 }
@@ -682,7 +796,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/current/password": {
             "put": {
                 "security": [
                     {
@@ -690,24 +806,17 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "MenuAPI"
+                    "LoginAPI"
                 ],
-                "summary": "Update menu record by ID",
+                "summary": "Change current user password",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "unique id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "Request body",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.MenuForm"
+                            "$ref": "#/definitions/schema.UpdateLoginPassword"
                         }
                     }
                 ],
@@ -737,29 +846,46 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
+            }
+        },
+        "/api/v1/current/refresh-token": {
+            "post": {
                 "tags": [
-                    "MenuAPI"
+                    "LoginAPI"
                 ],
-                "summary": "Delete menu record by ID",
+                "summary": "Exchange a refresh token for a new token pair",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "unique id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.RefreshTokenForm"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.LoginToken"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/util.ResponseResult"
                         }
@@ -779,7 +905,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/roles": {
+        "/api/v1/current/sessions": {
             "get": {
                 "security": [
                     {
@@ -787,39 +913,9 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "RoleAPI"
-                ],
-                "summary": "Query role list",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "pagination index",
-                        "name": "current",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "pagination size",
-                        "name": "pageSize",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Display name of role",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Status of role (disabled, enabled)",
-                        "name": "status",
-                        "in": "query"
-                    }
+                    "SessionAPI"
                 ],
+                "summary": "Query active sessions of the current user",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -834,7 +930,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/schema.Role"
+                                                "$ref": "#/definitions/schema.Session"
                                             }
                                         }
                                     }
@@ -856,48 +952,19 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "RoleAPI"
-                ],
-                "summary": "Create role record",
-                "parameters": [
-                    {
-                        "description": "Request body",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/schema.RoleForm"
-                        }
-                    }
+                    "SessionAPI"
                 ],
+                "summary": "Revoke all other sessions of the current user (sign out other devices)",
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/util.ResponseResult"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/schema.Role"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/util.ResponseResult"
                         }
@@ -917,7 +984,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/roles/{id}": {
+        "/api/v1/current/user": {
             "get": {
                 "security": [
                     {
@@ -925,18 +992,9 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "RoleAPI"
-                ],
-                "summary": "Get role record by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "unique id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "LoginAPI"
                 ],
+                "summary": "Get current user info",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -949,7 +1007,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/schema.Role"
+                                            "$ref": "#/definitions/schema.User"
                                         }
                                     }
                                 }
@@ -977,24 +1035,17 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "RoleAPI"
+                    "LoginAPI"
                 ],
-                "summary": "Update role record by ID",
+                "summary": "Update current user info",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "unique id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "Request body",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.RoleForm"
+                            "$ref": "#/definitions/schema.UpdateCurrentUser"
                         }
                     }
                 ],
@@ -1024,49 +1075,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "tags": [
-                    "RoleAPI"
-                ],
-                "summary": "Delete role record by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "unique id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/util.ResponseResult"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/util.ResponseResult"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/util.ResponseResult"
-                        }
-                    }
-                }
             }
         },
-        "/api/v1/users": {
+        "/api/v1/depts": {
             "get": {
                 "security": [
                     {
@@ -1074,42 +1085,26 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "UserAPI"
+                    "DeptAPI"
                 ],
-                "summary": "Query user list",
+                "summary": "Query department tree data",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "pagination index",
-                        "name": "current",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "pagination size",
-                        "name": "pageSize",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
-                        "description": "Username for login",
-                        "name": "username",
+                        "description": "Display name of department",
+                        "name": "name",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Name of user",
-                        "name": "name",
+                        "description": "Status of department (disabled, enabled)",
+                        "name": "status",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Status of user (activated, freezed)",
-                        "name": "status",
+                        "type": "integer",
+                        "description": "Only the tree of the department",
+                        "name": "root",
                         "in": "query"
                     }
                 ],
@@ -1127,7 +1122,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/schema.User"
+                                                "$ref": "#/definitions/schema.Dept"
                                             }
                                         }
                                     }
@@ -1156,9 +1151,9 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "UserAPI"
+                    "DeptAPI"
                 ],
-                "summary": "Create user record",
+                "summary": "Create department record",
                 "parameters": [
                     {
                         "description": "Request body",
@@ -1166,7 +1161,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.UserForm"
+                            "$ref": "#/definitions/schema.DeptForm"
                         }
                     }
                 ],
@@ -1182,7 +1177,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/schema.User"
+                                            "$ref": "#/definitions/schema.Dept"
                                         }
                                     }
                                 }
@@ -1210,7 +1205,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users/{id}": {
+        "/api/v1/depts/{id}": {
             "get": {
                 "security": [
                     {
@@ -1218,9 +1213,9 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "UserAPI"
+                    "DeptAPI"
                 ],
-                "summary": "Get user record by ID",
+                "summary": "Get department record by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -1242,7 +1237,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/schema.User"
+                                            "$ref": "#/definitions/schema.Dept"
                                         }
                                     }
                                 }
@@ -1270,9 +1265,9 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "UserAPI"
+                    "DeptAPI"
                 ],
-                "summary": "Update user record by ID",
+                "summary": "Update department record by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -1287,7 +1282,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.UserForm"
+                            "$ref": "#/definitions/schema.DeptForm"
                         }
                     }
                 ],
@@ -1325,9 +1320,9 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "UserAPI"
+                    "DeptAPI"
                 ],
-                "summary": "Delete user record by ID",
+                "summary": "Delete department record by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -1344,6 +1339,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/util.ResponseResult"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -1359,17 +1360,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users/{id}/reset-pwd": {
-            "patch": {
+        "/api/v1/depts/{id}/move": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "UserAPI"
+                    "DeptAPI"
                 ],
-                "summary": "Reset user password by ID",
+                "summary": "Move department and its children under a new parent",
                 "parameters": [
                     {
                         "type": "string",
@@ -1377,6 +1378,15 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.DeptMoveForm"
+                        }
                     }
                 ],
                 "responses": {
@@ -1386,6 +1396,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/util.ResponseResult"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -1400,91 +1416,3372 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "errors.Error": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "detail": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                }
-            }
-        },
-        "schema.Captcha": {
-            "type": "object",
-            "properties": {
-                "captcha_id": {
-                    "description": "Captcha ID",
-                    "type": "string"
-                }
-            }
         },
-        "schema.Logger": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "description": "Create time",
-                    "type": "string"
-                },
-                "data": {
-                    "description": "Log data",
-                    "type": "string"
-                },
-                "id": {
-                    "description": "Unique ID",
-                    "type": "string"
-                },
-                "level": {
-                    "description": "Log level",
-                    "type": "string"
-                },
-                "login_name": {
-                    "description": "From User.Username",
-                    "type": "string"
-                },
-                "message": {
-                    "description": "Log message",
-                    "type": "string"
-                },
-                "stack": {
-                    "description": "Error stack",
-                    "type": "string"
-                },
-                "tag": {
+        "/api/v1/depts/{id}/users": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "DeptAPI"
+                ],
+                "summary": "Query members of department",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "pagination index",
+                        "name": "current",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "pagination size",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Username of user",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only the leaders (true) or the other members (false)",
+                        "name": "leader",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.DeptUser"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "DeptAPI"
+                ],
+                "summary": "Add user to department or update its membership",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.DeptUserForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.DeptUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/depts/{id}/users/{userID}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "DeptAPI"
+                ],
+                "summary": "Remove user from department",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/loggers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "LoggerAPI"
+                ],
+                "summary": "Query logger list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "pagination index",
+                        "name": "current",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "pagination size",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "log level",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "trace ID",
+                        "name": "traceID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "user name",
+                        "name": "userName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "log tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "log message",
+                        "name": "message",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "start time",
+                        "name": "startTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end time",
+                        "name": "endTime",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.Logger"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/login": {
+            "post": {
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "Login system with username and password",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.LoginForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.LoginToken"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/login/2fa": {
+            "post": {
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "Exchange the challenge token of login and a TOTP or recovery code for the access token",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.TwoFactorChallengeForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.LoginToken"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/login/2fa/setup": {
+            "post": {
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "Set up two-factor with the challenge token of login (when required by roles but not enabled)",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.TwoFactorChallengeForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.TwoFactorSetup"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/login/oidc/{provider}": {
+            "get": {
+                "tags": [
+                    "OIDCAPI"
+                ],
+                "summary": "Redirect to the single sign-on provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the authorization endpoint of the provider"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/login/oidc/{provider}/callback": {
+            "get": {
+                "tags": [
+                    "OIDCAPI"
+                ],
+                "summary": "Complete the single sign-on with the code and state passed back by the provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "State of the login request",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Error code of the provider",
+                        "name": "error",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.LoginToken"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/menus": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "MenuAPI"
+                ],
+                "summary": "Query menu tree data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Code path of menu (like xxx.xxx.xxx)",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of menu",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether to include menu resources",
+                        "name": "includeResources",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale of the menu names, unless the user has a preferred locale",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.Menu"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "MenuAPI"
+                ],
+                "summary": "Create menu record",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.MenuForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.Menu"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/menus/reconcile": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "MenuAPI"
+                ],
+                "summary": "Reconcile the menus and their resources with the menu file, including the removals",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only return the diff without applying it",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.MenuDiff"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/menus/reorder": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "MenuAPI"
+                ],
+                "summary": "Reorder all the children of a parent in the order of the IDs",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.MenuReorderForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/menus/resources/report": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "MenuAPI"
+                ],
+                "summary": "Validate the menu resources against the registered routes, listing the stale resources and the uncovered routes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.RouteReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/menus/resources/routes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "MenuAPI"
+                ],
+                "summary": "Query the registered API routes to pick the resources of the menus",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Route path",
+                        "name": "path",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only the routes checked by casbin",
+                        "name": "protected",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.Route"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/menus/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "MenuAPI"
+                ],
+                "summary": "Get menu record by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.Menu"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "MenuAPI"
+                ],
+                "summary": "Update menu record by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.MenuForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "MenuAPI"
+                ],
+                "summary": "Delete menu record by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/menus/{id}/move": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "MenuAPI"
+                ],
+                "summary": "Move menu and its children under a new parent at a position among the new siblings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.MenuMoveForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/password/forgot": {
+            "post": {
+                "tags": [
+                    "PasswordResetAPI"
+                ],
+                "summary": "Send a one-time password reset link to the email (the response does not reveal whether the email exists)",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.PasswordForgotForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/password/reset": {
+            "post": {
+                "tags": [
+                    "PasswordResetAPI"
+                ],
+                "summary": "Reset the password with the token from the email",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.PasswordResetForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/permissions/check": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "PermissionAPI"
+                ],
+                "summary": "Check whether a user or roles are allowed to request an API, and explain the deciding policy",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.PermissionCheckForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.PermissionCheck"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/roles": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "RoleAPI"
+                ],
+                "summary": "Query role list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "pagination index",
+                        "name": "current",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "pagination size",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display name of role",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status of role (disabled, enabled)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.Role"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "RoleAPI"
+                ],
+                "summary": "Create role record",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.RoleForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.Role"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/roles/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "RoleAPI"
+                ],
+                "summary": "Get role record by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.Role"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "RoleAPI"
+                ],
+                "summary": "Update role record by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.RoleForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "RoleAPI"
+                ],
+                "summary": "Delete role record by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tenants": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "TenantAPI"
+                ],
+                "summary": "Query tenant list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "pagination index",
+                        "name": "current",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "pagination size",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display name of tenant",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Code of tenant",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status of tenant (disabled, enabled)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.Tenant"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "TenantAPI"
+                ],
+                "summary": "Create tenant record",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.TenantForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.Tenant"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tenants/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "TenantAPI"
+                ],
+                "summary": "Get tenant record by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.Tenant"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "TenantAPI"
+                ],
+                "summary": "Update tenant record by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.TenantForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "TenantAPI"
+                ],
+                "summary": "Delete tenant record by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "Query user list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "pagination index",
+                        "name": "current",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "pagination size",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Username for login",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of user",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status of user (activated, freezed)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.User"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "Create user record",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.UserForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "Get user record by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/schema.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "Update user record by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.UserForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "Delete user record by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "PermissionAPI"
+                ],
+                "summary": "List the effective permissions (method and path) of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.Permission"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/reset-pwd": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "Reset user password by ID, the user must change it at next login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body (optional)",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ResetPasswordForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "SessionAPI"
+                ],
+                "summary": "Query active sessions of the user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id of user",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.ResponseResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.Session"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/sessions/{sid}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "SessionAPI"
+                ],
+                "summary": "Revoke a session of the user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id of user",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "unique id of session",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/unlock": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "Unlock user login locked by failed attempts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unique id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseResult"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "errors.Error": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errors.FieldError"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "errors.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Create time",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "Expired time (empty means never expires)",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID",
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "Plain key, only returned once when created",
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "description": "Last used time",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Display name of API key",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Leading characters of the key (for identification)",
+                    "type": "string"
+                },
+                "role_ids": {
+                    "description": "Subset of the owner's roles (empty means all roles of the owner)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "updated_at": {
+                    "description": "Update time",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "From User.ID (owner)",
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.APIKeyForm": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "expires_at": {
+                    "description": "Expired time (empty means never expires)",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Display name of API key",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "role_ids": {
+                    "description": "Subset of the owner's roles (empty means all roles of the owner)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "schema.Bundle": {
+            "type": "object",
+            "properties": {
+                "exported_at": {
+                    "description": "Export time",
+                    "type": "string"
+                },
+                "menus": {
+                    "description": "Menu tree with resources",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.BundleMenu"
+                    }
+                },
+                "roles": {
+                    "description": "Roles with menus, parents and resources",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.BundleRole"
+                    }
+                },
+                "users": {
+                    "description": "Role bindings of the users (optional)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.BundleUser"
+                    }
+                },
+                "version": {
+                    "description": "Version of the bundle format",
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.BundleImportResult": {
+            "type": "object",
+            "properties": {
+                "bound_users": {
+                    "description": "Usernames of the users whose roles are replaced",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_roles": {
+                    "description": "Codes of the created roles",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "menus": {
+                    "description": "Changes of the menus",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/schema.MenuDiff"
+                        }
+                    ]
+                },
+                "skipped_users": {
+                    "description": "Usernames of the users not found",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_roles": {
+                    "description": "Codes of the updated roles",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "schema.BundleMenu": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "$ref": "#/definitions/schema.BundleMenus"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "properties": {
+                    "type": "string"
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.BundleResource"
+                    }
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.BundleMenus": {
+            "type": "array",
+            "items": {
+                "$ref": "#/definitions/schema.BundleMenu"
+            }
+        },
+        "schema.BundleResource": {
+            "type": "object",
+            "properties": {
+                "effect": {
+                    "description": "allow (default), deny",
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.BundleRole": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "data_scope": {
+                    "type": "string"
+                },
+                "data_scope_depts": {
+                    "description": "Code paths of the departments of the custom data scope",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "menus": {
+                    "description": "Code paths of the menus (like xxx.xxx.xxx)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parents": {
+                    "description": "Codes of the inherited roles",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "require_two_factor": {
+                    "type": "boolean"
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.BundleResource"
+                    }
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.BundleUser": {
+            "type": "object",
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.BundleUserRole"
+                    }
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.BundleUserRole": {
+            "type": "object",
+            "properties": {
+                "expire_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Code of the role",
+                    "type": "string"
+                },
+                "start_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.Captcha": {
+            "type": "object",
+            "properties": {
+                "captcha_id": {
+                    "description": "Captcha ID",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.Dept": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "description": "Child departments",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.Dept"
+                    }
+                },
+                "code": {
+                    "description": "Code of department (unique for each level)",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Create time",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Details about department",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID",
+                    "type": "integer"
+                },
+                "leaders": {
+                    "description": "Leaders of department",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.DeptUser"
+                    }
+                },
+                "name": {
+                    "description": "Display name of department",
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "Parent ID (From Dept.ID)",
+                    "type": "integer"
+                },
+                "parent_path": {
+                    "description": "Parent path (split by .)",
+                    "type": "string"
+                },
+                "sequence": {
+                    "description": "Sequence for sorting (Order by desc)",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status of department (enabled, disabled)",
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "description": "From Tenant.ID",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "Update time",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.DeptForm": {
+            "type": "object",
+            "required": [
+                "code",
+                "name",
+                "status"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Code of department (unique for each level)",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "description": {
+                    "description": "Details about department",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Display name of department",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "parent_id": {
+                    "description": "Parent ID (From Dept.ID), only used on creation, the departments are moved by the move API",
+                    "type": "integer"
+                },
+                "sequence": {
+                    "description": "Sequence for sorting (Order by desc)",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status of department (disabled, enabled)",
+                    "type": "string",
+                    "enum": [
+                        "disabled",
+                        "enabled"
+                    ]
+                }
+            }
+        },
+        "schema.DeptMoveForm": {
+            "type": "object",
+            "properties": {
+                "parent_id": {
+                    "description": "New parent ID (From Dept.ID), zero to move to the root",
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.DeptUser": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Create time",
+                    "type": "string"
+                },
+                "dept_id": {
+                    "description": "From Dept.ID",
+                    "type": "integer"
+                },
+                "dept_name": {
+                    "description": "From Dept.Name",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID",
+                    "type": "integer"
+                },
+                "is_leader": {
+                    "description": "Leader of the department",
+                    "type": "boolean"
+                },
+                "is_primary": {
+                    "description": "Primary department of the user (User.DeptID)",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "From User.Name",
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "description": "From Tenant.ID",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "Update time",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "From User.ID",
+                    "type": "integer"
+                },
+                "username": {
+                    "description": "From User.Username",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.DeptUserForm": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "is_leader": {
+                    "description": "Leader of the department",
+                    "type": "boolean"
+                },
+                "is_primary": {
+                    "description": "Set the department as the primary department of the user",
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "description": "From User.ID",
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.Logger": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Create time",
+                    "type": "string"
+                },
+                "data": {
+                    "description": "Log data",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID",
+                    "type": "integer"
+                },
+                "level": {
+                    "description": "Log level",
+                    "type": "string"
+                },
+                "login_name": {
+                    "description": "From User.Username",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Log message",
+                    "type": "string"
+                },
+                "stack": {
+                    "description": "Error stack",
+                    "type": "string"
+                },
+                "tag": {
                     "description": "Log tag",
                     "type": "string"
                 },
-                "trace_id": {
-                    "description": "Trace ID",
+                "trace_id": {
+                    "description": "Trace ID",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "User ID",
+                    "type": "integer"
+                },
+                "user_name": {
+                    "description": "From User.Name",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.LoginForm": {
+            "type": "object",
+            "required": [
+                "captcha_code",
+                "captcha_id",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "captcha_code": {
+                    "description": "Captcha verify code",
+                    "type": "string"
+                },
+                "captcha_id": {
+                    "description": "Captcha verify id",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Login password (md5 hash)",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Login name",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.LoginToken": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "description": "Access token (JWT)",
+                    "type": "string"
+                },
+                "challenge_expires_at": {
+                    "description": "Challenge token expired time (Unit: second)",
+                    "type": "integer"
+                },
+                "challenge_token": {
+                    "description": "Two-factor challenge token, exchanged for the tokens above by /api/v1/login/2fa",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "Expired time (Unit: second)",
+                    "type": "integer"
+                },
+                "password_expired": {
+                    "description": "The password has expired, the access token only allows changing it by /api/v1/current/password",
+                    "type": "boolean"
+                },
+                "recovery_codes": {
+                    "description": "Recovery codes, only returned when two-factor is set up during login",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "refresh_expires_at": {
+                    "description": "Refresh token expired time (Unit: second)",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "description": "Refresh token (JWT, can only be used once)",
+                    "type": "string"
+                },
+                "token_type": {
+                    "description": "Token type (Usage: Authorization=${token_type} ${access_token})",
+                    "type": "string"
+                },
+                "two_factor_setup": {
+                    "description": "Two-factor must be set up by /api/v1/login/2fa/setup before verifying the challenge",
+                    "type": "boolean"
+                }
+            }
+        },
+        "schema.Menu": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "description": "Child menus",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.Menu"
+                    }
+                },
+                "code": {
+                    "description": "Code of menu (unique for each level)",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Create time",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Details about menu",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Display name of menu",
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "Parent ID (From Menu.ID)",
+                    "type": "integer"
+                },
+                "parent_path": {
+                    "description": "Parent path (split by .)",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Access path of menu",
+                    "type": "string"
+                },
+                "properties": {
+                    "description": "Properties of menu (JSON)",
+                    "type": "string"
+                },
+                "resources": {
+                    "description": "Resources of menu",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.MenuResource"
+                    }
+                },
+                "sequence": {
+                    "description": "Sequence for sorting (Order by desc)",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status of menu (enabled, disabled)",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type of menu (page, button)",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Update time",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.MenuChange": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Change of the menu (create, update, move, delete)",
+                    "type": "string"
+                },
+                "code_path": {
+                    "description": "Code path of the menu (like xxx.xxx.xxx)",
+                    "type": "string"
+                },
+                "effect": {
+                    "description": "Effect of the changed resource (allow, deny)",
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "Updated fields of the menu",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "from": {
+                    "description": "Code path of the previous parent of the moved menu",
+                    "type": "string"
+                },
+                "menu_id": {
+                    "description": "From Menu.ID (empty for the menus to create)",
+                    "type": "integer"
+                },
+                "resource": {
+                    "description": "Method and path of the changed resource (empty for the menu)",
+                    "type": "string"
+                },
+                "skipped": {
+                    "description": "Not applied as the deletion of the menus is denied",
+                    "type": "boolean"
+                }
+            }
+        },
+        "schema.MenuDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.MenuChange"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "schema.MenuForm": {
+            "type": "object",
+            "required": [
+                "code",
+                "name",
+                "status",
+                "type"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Code of menu (unique for each level)",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "description": {
+                    "description": "Details about menu",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Display name of menu",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "parent_id": {
+                    "description": "Parent ID (From Menu.ID)",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "Access path of menu",
+                    "type": "string"
+                },
+                "properties": {
+                    "description": "Properties of menu (JSON)",
+                    "type": "string"
+                },
+                "resources": {
+                    "description": "Resources of menu",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.MenuResource"
+                    }
+                },
+                "sequence": {
+                    "description": "Sequence for sorting (Order by desc)",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status of menu (enabled, disabled)",
+                    "type": "string",
+                    "enum": [
+                        "disabled",
+                        "enabled"
+                    ]
+                },
+                "type": {
+                    "description": "Type of menu (page, button)",
+                    "type": "string",
+                    "enum": [
+                        "page",
+                        "button"
+                    ]
+                }
+            }
+        },
+        "schema.MenuMoveForm": {
+            "type": "object",
+            "properties": {
+                "parent_id": {
+                    "description": "New parent ID (From Menu.ID), zero to move to the root",
+                    "type": "integer"
+                },
+                "position": {
+                    "description": "Position among the new siblings (zero for the first), the last if beyond",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "schema.MenuReorderForm": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "description": "All the menus of a parent (From Menu.ID) in their new order",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "schema.MenuResource": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Create time",
+                    "type": "string"
+                },
+                "effect": {
+                    "description": "Effect of resource (allow, deny)",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID",
+                    "type": "integer"
+                },
+                "menu_id": {
+                    "description": "From Menu.ID",
+                    "type": "integer"
+                },
+                "method": {
+                    "description": "HTTP method",
                     "type": "string"
                 },
-                "user_id": {
-                    "description": "User ID",
+                "path": {
+                    "description": "API request path (e.g. /api/v1/users/:id)",
                     "type": "string"
                 },
-                "user_name": {
-                    "description": "From User.Name",
+                "updated_at": {
+                    "description": "Update time",
                     "type": "string"
                 }
             }
         },
-        "schema.LoginForm": {
+        "schema.PasswordForgotForm": {
             "type": "object",
             "required": [
                 "captcha_code",
                 "captcha_id",
-                "password",
-                "username"
+                "email"
             ],
             "properties": {
                 "captcha_code": {
@@ -1495,202 +4792,501 @@ const docTemplate = `{
                     "description": "Captcha verify id",
                     "type": "string"
                 },
+                "email": {
+                    "description": "Email of user",
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "schema.PasswordResetForm": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
                 "password": {
-                    "description": "Login password (md5 hash)",
+                    "description": "New password",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "token": {
+                    "description": "Reset token from the email (can only be used once)",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.Permission": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "description": "HTTP method",
                     "type": "string"
                 },
-                "username": {
-                    "description": "Login name",
+                "path": {
+                    "description": "API request path pattern (e.g. /api/v1/users/:id)",
                     "type": "string"
+                },
+                "role_ids": {
+                    "description": "Roles granting the permission (From Role.ID)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "schema.LoginToken": {
+        "schema.PermissionCheck": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "description": "Access token (JWT)",
+                "allowed": {
+                    "description": "Whether the request is allowed",
+                    "type": "boolean"
+                },
+                "effect": {
+                    "description": "Effect of the deciding policy (allow, deny)",
+                    "type": "string"
+                },
+                "menu": {
+                    "description": "Menu of the deciding resource",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/schema.Menu"
+                        }
+                    ]
+                },
+                "menu_resource": {
+                    "description": "Menu resource of the deciding policy",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/schema.MenuResource"
+                        }
+                    ]
+                },
+                "policy": {
+                    "description": "Deciding policy (role, tenant, path, method, effect)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "role": {
+                    "description": "Role of the deciding policy, the checked role or one of its ancestors",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/schema.Role"
+                        }
+                    ]
+                },
+                "role_resource": {
+                    "description": "Role resource of the deciding policy",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/schema.RoleResource"
+                        }
+                    ]
+                },
+                "root": {
+                    "description": "The root user is allowed everything",
+                    "type": "boolean"
+                },
+                "subject_id": {
+                    "description": "Checked role granted or denied the request (From Role.ID)",
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.PermissionCheckForm": {
+            "type": "object",
+            "required": [
+                "method",
+                "path"
+            ],
+            "properties": {
+                "method": {
+                    "description": "HTTP method",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "API request path (e.g. /api/v1/users/1)",
+                    "type": "string"
+                },
+                "role_ids": {
+                    "description": "From Role.ID (checked if no user is given)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "user_id": {
+                    "description": "From User.ID (the roles of the user are checked)",
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.RefreshTokenForm": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "description": "Refresh token",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.ResetPasswordForm": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "description": "New password (empty means the default password), must be changed at next login",
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "schema.Role": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code of role (unique)",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Create time",
+                    "type": "string"
+                },
+                "data_scope": {
+                    "description": "Rows visible to the users of role (all, dept, dept_and_children, self, custom)",
+                    "type": "string"
+                },
+                "data_scope_dept_ids": {
+                    "description": "Departments of the custom data scope",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
+                    "description": "Details about role",
+                    "type": "string"
+                },
+                "effective_menus": {
+                    "description": "Menus of the role and of the inherited roles",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.RoleMenu"
+                    }
+                },
+                "id": {
+                    "description": "Unique ID",
+                    "type": "integer"
+                },
+                "menus": {
+                    "description": "Role menu list",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.RoleMenu"
+                    }
+                },
+                "name": {
+                    "description": "Display name of role",
+                    "type": "string"
+                },
+                "parent_ids": {
+                    "description": "Inherited roles (From Role.ID)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "require_two_factor": {
+                    "description": "Users of the role must sign in with two-factor authentication",
+                    "type": "boolean"
+                },
+                "resources": {
+                    "description": "Resources allowed or denied to the role, whatever its menus",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.RoleResource"
+                    }
+                },
+                "sequence": {
+                    "description": "Sequence for sorting",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status of role (disabled, enabled)",
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "description": "From Tenant.ID",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "Update time",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.RoleForm": {
+            "type": "object",
+            "required": [
+                "code",
+                "name",
+                "status"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Code of role (unique)",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "data_scope": {
+                    "description": "Rows visible to the users of role (default all)",
+                    "type": "string",
+                    "enum": [
+                        "all",
+                        "dept",
+                        "dept_and_children",
+                        "self",
+                        "custom"
+                    ]
+                },
+                "data_scope_dept_ids": {
+                    "description": "Departments of the custom data scope",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
+                    "description": "Details about role",
+                    "type": "string"
+                },
+                "menus": {
+                    "description": "Role menu list",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.RoleMenu"
+                    }
+                },
+                "name": {
+                    "description": "Display name of role",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "parent_ids": {
+                    "description": "Inherited roles (From Role.ID)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "require_two_factor": {
+                    "description": "Users of the role must sign in with two-factor authentication",
+                    "type": "boolean"
+                },
+                "resources": {
+                    "description": "Resources allowed or denied to the role, whatever its menus",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.RoleResource"
+                    }
+                },
+                "sequence": {
+                    "description": "Sequence for sorting",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status of role (enabled, disabled)",
+                    "type": "string",
+                    "enum": [
+                        "disabled",
+                        "enabled"
+                    ]
+                }
+            }
+        },
+        "schema.RoleMenu": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Create time",
                     "type": "string"
                 },
-                "expires_at": {
-                    "description": "Expired time (Unit: second)",
+                "id": {
+                    "description": "Unique ID",
+                    "type": "integer"
+                },
+                "menu_id": {
+                    "description": "From Menu.ID",
+                    "type": "integer"
+                },
+                "role_id": {
+                    "description": "From Role.ID",
+                    "type": "integer"
+                },
+                "tenant_id": {
+                    "description": "From Tenant.ID",
                     "type": "integer"
                 },
-                "token_type": {
-                    "description": "Token type (Usage: Authorization=${token_type} ${access_token})",
+                "updated_at": {
+                    "description": "Update time",
                     "type": "string"
                 }
             }
         },
-        "schema.Menu": {
+        "schema.RoleResource": {
             "type": "object",
             "properties": {
-                "children": {
-                    "description": "Child menus",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/schema.Menu"
-                    }
-                },
-                "code": {
-                    "description": "Code of menu (unique for each level)",
-                    "type": "string"
-                },
                 "created_at": {
                     "description": "Create time",
                     "type": "string"
                 },
-                "description": {
-                    "description": "Details about menu",
+                "effect": {
+                    "description": "Effect of resource (allow, deny)",
                     "type": "string"
                 },
                 "id": {
                     "description": "Unique ID",
+                    "type": "integer"
+                },
+                "method": {
+                    "description": "HTTP method",
                     "type": "string"
                 },
-                "name": {
-                    "description": "Display name of menu",
+                "path": {
+                    "description": "API request path (e.g. /api/v1/users/:id)",
                     "type": "string"
                 },
-                "parent_id": {
-                    "description": "Parent ID (From Menu.ID)",
+                "role_id": {
+                    "description": "From Role.ID",
+                    "type": "integer"
+                },
+                "tenant_id": {
+                    "description": "From Tenant.ID",
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.Route": {
+            "type": "object",
+            "properties": {
+                "handler": {
+                    "description": "Name of the handler (e.g. api.(*User).Get)",
                     "type": "string"
                 },
-                "parent_path": {
-                    "description": "Parent path (split by .)",
+                "method": {
+                    "description": "HTTP method",
                     "type": "string"
                 },
                 "path": {
-                    "description": "Access path of menu",
+                    "description": "Route path (e.g. /api/v1/users/:id)",
                     "type": "string"
                 },
-                "properties": {
-                    "description": "Properties of menu (JSON)",
-                    "type": "string"
+                "protected": {
+                    "description": "Checked by casbin, the route must be granted by a menu resource",
+                    "type": "boolean"
+                }
+            }
+        },
+        "schema.RouteReport": {
+            "type": "object",
+            "properties": {
+                "routes": {
+                    "description": "Number of registered routes",
+                    "type": "integer"
                 },
-                "resources": {
-                    "description": "Resources of menu",
+                "stale_resources": {
+                    "description": "Menu resources matching no route",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/schema.MenuResource"
+                        "$ref": "#/definitions/schema.RouteReportResource"
                     }
                 },
-                "sequence": {
-                    "description": "Sequence for sorting (Order by desc)",
-                    "type": "integer"
-                },
-                "status": {
-                    "description": "Status of menu (enabled, disabled)",
-                    "type": "string"
-                },
-                "type": {
-                    "description": "Type of menu (page, button)",
-                    "type": "string"
-                },
-                "updated_at": {
-                    "description": "Update time",
-                    "type": "string"
+                "uncovered_routes": {
+                    "description": "Protected routes allowed by no menu resource",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.Route"
+                    }
                 }
             }
         },
-        "schema.MenuForm": {
+        "schema.RouteReportResource": {
             "type": "object",
-            "required": [
-                "code",
-                "name",
-                "status",
-                "type"
-            ],
             "properties": {
-                "code": {
-                    "description": "Code of menu (unique for each level)",
-                    "type": "string",
-                    "maxLength": 32
+                "code_path": {
+                    "description": "Code path of the menu (like xxx.xxx.xxx)",
+                    "type": "string"
                 },
-                "description": {
-                    "description": "Details about menu",
+                "effect": {
+                    "description": "Effect of resource (allow, deny)",
                     "type": "string"
                 },
-                "name": {
-                    "description": "Display name of menu",
-                    "type": "string",
-                    "maxLength": 128
+                "menu_id": {
+                    "description": "From Menu.ID",
+                    "type": "integer"
                 },
-                "parent_id": {
-                    "description": "Parent ID (From Menu.ID)",
+                "method": {
+                    "description": "HTTP method",
                     "type": "string"
                 },
                 "path": {
-                    "description": "Access path of menu",
-                    "type": "string"
-                },
-                "properties": {
-                    "description": "Properties of menu (JSON)",
+                    "description": "API request path",
                     "type": "string"
-                },
-                "resources": {
-                    "description": "Resources of menu",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/schema.MenuResource"
-                    }
-                },
-                "sequence": {
-                    "description": "Sequence for sorting (Order by desc)",
-                    "type": "integer"
-                },
-                "status": {
-                    "description": "Status of menu (enabled, disabled)",
-                    "type": "string",
-                    "enum": [
-                        "disabled",
-                        "enabled"
-                    ]
-                },
-                "type": {
-                    "description": "Type of menu (page, button)",
-                    "type": "string",
-                    "enum": [
-                        "page",
-                        "button"
-                    ]
                 }
             }
         },
-        "schema.MenuResource": {
+        "schema.Session": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "description": "Create time",
+                "client_ip": {
+                    "description": "Client IP of the login",
+                    "type": "string"
+                },
+                "current": {
+                    "description": "Whether it is the session of the request",
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "description": "Expired time of the session (refresh token)",
                     "type": "string"
                 },
                 "id": {
-                    "description": "Unique ID",
+                    "description": "Session ID (token family ID)",
                     "type": "string"
                 },
-                "menu_id": {
-                    "description": "From Menu.ID",
+                "issued_at": {
+                    "description": "Login time",
                     "type": "string"
                 },
-                "method": {
-                    "description": "HTTP method",
+                "refreshed_at": {
+                    "description": "Last token refresh time",
                     "type": "string"
                 },
-                "path": {
-                    "description": "API request path (e.g. /api/v1/users/:id)",
+                "token_id": {
+                    "description": "ID of the latest access token (jti)",
                     "type": "string"
                 },
-                "updated_at": {
-                    "description": "Update time",
+                "user_agent": {
+                    "description": "User agent of the login",
                     "type": "string"
+                },
+                "user_id": {
+                    "description": "From User.ID",
+                    "type": "integer"
                 }
             }
         },
-        "schema.Role": {
+        "schema.Tenant": {
             "type": "object",
             "properties": {
                 "code": {
-                    "description": "Code of role (unique)",
+                    "description": "Code of tenant (unique, used by the header and the subdomain)",
                     "type": "string"
                 },
                 "created_at": {
@@ -1698,30 +5294,19 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "description": {
-                    "description": "Details about role",
+                    "description": "Details about tenant",
                     "type": "string"
                 },
                 "id": {
                     "description": "Unique ID",
-                    "type": "string"
-                },
-                "menus": {
-                    "description": "Role menu list",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/schema.RoleMenu"
-                    }
+                    "type": "integer"
                 },
                 "name": {
-                    "description": "Display name of role",
+                    "description": "Display name of tenant",
                     "type": "string"
                 },
-                "sequence": {
-                    "description": "Sequence for sorting",
-                    "type": "integer"
-                },
                 "status": {
-                    "description": "Status of role (disabled, enabled)",
+                    "description": "Status of tenant (disabled, enabled)",
                     "type": "string"
                 },
                 "updated_at": {
@@ -1730,7 +5315,7 @@ const docTemplate = `{
                 }
             }
         },
-        "schema.RoleForm": {
+        "schema.TenantForm": {
             "type": "object",
             "required": [
                 "code",
@@ -1739,32 +5324,21 @@ const docTemplate = `{
             ],
             "properties": {
                 "code": {
-                    "description": "Code of role (unique)",
+                    "description": "Code of tenant (unique, lowercase letters, digits and hyphens)",
                     "type": "string",
                     "maxLength": 32
                 },
                 "description": {
-                    "description": "Details about role",
+                    "description": "Details about tenant",
                     "type": "string"
                 },
-                "menus": {
-                    "description": "Role menu list",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/schema.RoleMenu"
-                    }
-                },
                 "name": {
-                    "description": "Display name of role",
+                    "description": "Display name of tenant",
                     "type": "string",
                     "maxLength": 128
                 },
-                "sequence": {
-                    "description": "Sequence for sorting",
-                    "type": "integer"
-                },
                 "status": {
-                    "description": "Status of role (enabled, disabled)",
+                    "description": "Status of tenant (disabled, enabled)",
                     "type": "string",
                     "enum": [
                         "disabled",
@@ -1773,27 +5347,61 @@ const docTemplate = `{
                 }
             }
         },
-        "schema.RoleMenu": {
+        "schema.TwoFactorChallengeForm": {
             "type": "object",
+            "required": [
+                "challenge_token"
+            ],
             "properties": {
-                "created_at": {
-                    "description": "Create time",
-                    "type": "string"
-                },
-                "id": {
-                    "description": "Unique ID",
+                "challenge_token": {
+                    "description": "Challenge token returned by login",
                     "type": "string"
                 },
-                "menu_id": {
-                    "description": "From Menu.ID",
+                "code": {
+                    "description": "TOTP code or recovery code (required except for setup)",
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "schema.TwoFactorCodeForm": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "TOTP code or recovery code",
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "schema.TwoFactorRecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "description": "Recovery codes (each can be used once instead of a TOTP code)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "schema.TwoFactorSetup": {
+            "type": "object",
+            "properties": {
+                "qr_code": {
+                    "description": "QR code of the URI (PNG data URL)",
                     "type": "string"
                 },
-                "role_id": {
-                    "description": "From Role.ID",
+                "secret": {
+                    "description": "Base32 encoded secret (for manual entry)",
                     "type": "string"
                 },
-                "updated_at": {
-                    "description": "Update time",
+                "uri": {
+                    "description": "otpauth URI",
                     "type": "string"
                 }
             }
@@ -1809,6 +5417,11 @@ const docTemplate = `{
                     "type": "string",
                     "maxLength": 128
                 },
+                "locale": {
+                    "description": "Preferred locale of user (e.g. en-US), unchanged if absent",
+                    "type": "string",
+                    "maxLength": 20
+                },
                 "name": {
                     "description": "Name of user",
                     "type": "string",
@@ -1850,18 +5463,38 @@ const docTemplate = `{
                     "description": "Create time",
                     "type": "string"
                 },
+                "created_by": {
+                    "description": "From User.ID (creator)",
+                    "type": "integer"
+                },
+                "dept_id": {
+                    "description": "Primary department of user (From Dept.ID, managed by the department members)",
+                    "type": "integer"
+                },
                 "email": {
                     "description": "Email of user",
                     "type": "string"
                 },
                 "id": {
                     "description": "Unique ID",
+                    "type": "integer"
+                },
+                "locale": {
+                    "description": "Preferred locale of user (e.g. en-US), else negotiated from Accept-Language",
                     "type": "string"
                 },
                 "name": {
                     "description": "Name of user",
                     "type": "string"
                 },
+                "password_changed_at": {
+                    "description": "Last time the password was set",
+                    "type": "string"
+                },
+                "password_expired": {
+                    "description": "Whether the password must be changed at next login (set by reset)",
+                    "type": "boolean"
+                },
                 "phone": {
                     "description": "Phone number of user",
                     "type": "string"
@@ -1881,6 +5514,14 @@ const docTemplate = `{
                     "description": "Status of user (activated, freezed)",
                     "type": "string"
                 },
+                "tenant_id": {
+                    "description": "From Tenant.ID",
+                    "type": "integer"
+                },
+                "two_factor_enabled": {
+                    "description": "Whether TOTP two-factor authentication is enabled",
+                    "type": "boolean"
+                },
                 "updated_at": {
                     "description": "Update time",
                     "type": "string"
@@ -1954,25 +5595,37 @@ const docTemplate = `{
                     "description": "Create time",
                     "type": "string"
                 },
+                "expire_at": {
+                    "description": "Granted until this time (empty means never expires)",
+                    "type": "string"
+                },
                 "id": {
                     "description": "Unique ID",
-                    "type": "string"
+                    "type": "integer"
                 },
                 "role_id": {
                     "description": "From Role.ID",
-                    "type": "string"
+                    "type": "integer"
                 },
                 "role_name": {
                     "description": "From Role.Name",
                     "type": "string"
                 },
+                "start_at": {
+                    "description": "Granted from this time (empty means immediately)",
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "description": "From Tenant.ID",
+                    "type": "integer"
+                },
                 "updated_at": {
                     "description": "Update time",
                     "type": "string"
                 },
                 "user_id": {
                     "description": "From User.ID",
-                    "type": "string"
+                    "type": "integer"
                 }
             }
         },
@@ -2,6 +2,10 @@ package wirex
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -9,6 +13,7 @@ import (
 
 	"github.com/supermicah/go-framework-admin/internal/config"
 	"github.com/supermicah/go-framework-admin/internal/mods"
+	"github.com/supermicah/go-framework-admin/pkg/bus"
 	"github.com/supermicah/go-framework-admin/pkg/cachex"
 	"github.com/supermicah/go-framework-admin/pkg/gormx"
 	"github.com/supermicah/go-framework-admin/pkg/jwtx"
@@ -87,22 +92,39 @@ func InitCacher(ctx context.Context) (cachex.Cacher, func(), error) {
 	}, nil
 }
 
+// InitBus It returns a bus.Bus instance to notify the other instances, and a function to close the bus
+func InitBus(ctx context.Context) (bus.Bus, func(), error) {
+	cfg := config.C.Util.Bus
+
+	var b bus.Bus
+	switch cfg.Type {
+	case "redis":
+		b = bus.NewRedisBus(bus.RedisConfig{
+			Addr:     cfg.Redis.Addr,
+			DB:       cfg.Redis.DB,
+			Username: cfg.Redis.Username,
+			Password: cfg.Redis.Password,
+		}, bus.WithChannelPrefix(cfg.Redis.ChannelPrefix))
+	default:
+		b = bus.NewMemoryBus()
+	}
+
+	return b, func() {
+		_ = b.Close(ctx)
+	}, nil
+}
+
 func InitAuth(ctx context.Context) (jwtx.Auther, func(), error) {
 	cfg := config.C.Middleware.Auth
 	var opts []jwtx.Option
 	opts = append(opts, jwtx.SetExpired(cfg.Expired))
-	opts = append(opts, jwtx.SetSigningKey(cfg.SigningKey, cfg.OldSigningKey))
-
-	var method jwt.SigningMethod
-	switch cfg.SigningMethod {
-	case "HS256":
-		method = jwt.SigningMethodHS256
-	case "HS384":
-		method = jwt.SigningMethodHS384
-	default:
-		method = jwt.SigningMethodHS512
+	opts = append(opts, jwtx.SetRefreshExpired(cfg.RefreshExpired))
+
+	keys, err := initAuthKeys()
+	if err != nil {
+		return nil, nil, err
 	}
-	opts = append(opts, jwtx.SetSigningMethod(method))
+	opts = append(opts, jwtx.SetKeys(keys...))
 
 	var cache cachex.Cacher
 	switch cfg.Store.Type {
@@ -123,8 +145,83 @@ func InitAuth(ctx context.Context) (jwtx.Auther, func(), error) {
 		}, cachex.WithDelimiter(cfg.Store.Delimiter))
 	}
 
-	auth := jwtx.New(jwtx.NewStoreWithCache(cache), opts...)
+	auth, err := jwtx.New(jwtx.NewStoreWithCache(cache), opts...)
+	if err != nil {
+		_ = cache.Close(ctx)
+		return nil, nil, err
+	}
 	return auth, func() {
 		_ = auth.Release(ctx)
 	}, nil
 }
+
+// initAuthKeys It builds the key ring of the JWT auth, falls back to the single secret key if no keys are configured
+func initAuthKeys() ([]*jwtx.Key, error) {
+	cfg := config.C.Middleware.Auth
+
+	if len(cfg.Keys) == 0 {
+		method := jwt.GetSigningMethod(cfg.SigningMethod)
+		if method == nil {
+			return nil, fmt.Errorf("unknown jwt signing method: %s", cfg.SigningMethod)
+		}
+		key, err := jwtx.NewHMACKey("", method, []byte(cfg.SigningKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwt signing method %s: %w", cfg.SigningMethod, err)
+		}
+		keys := []*jwtx.Key{key}
+
+		// Tokens signed with the old secret key are still accepted while changing it
+		if cfg.OldSigningKey != "" && cfg.OldSigningKey != cfg.SigningKey {
+			oldKey, err := jwtx.NewHMACKey("", method, []byte(cfg.OldSigningKey))
+			if err != nil {
+				return nil, fmt.Errorf("invalid jwt signing method %s: %w", cfg.SigningMethod, err)
+			}
+			keys = append(keys, oldKey)
+		}
+		return keys, nil
+	}
+
+	readFile := func(name string) ([]byte, error) {
+		if name == "" {
+			return nil, nil
+		}
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(config.C.General.WorkDir, name)
+		}
+		return os.ReadFile(name)
+	}
+
+	keys := make([]*jwtx.Key, len(cfg.Keys))
+	for i, item := range cfg.Keys {
+		method := jwt.GetSigningMethod(item.Method)
+		if method == nil {
+			return nil, fmt.Errorf("unknown jwt signing method: %s", item.Method)
+		}
+
+		var (
+			key *jwtx.Key
+			err error
+		)
+		if _, ok := method.(*jwt.SigningMethodHMAC); ok {
+			key, err = jwtx.NewHMACKey(item.ID, method, []byte(item.Secret))
+		} else {
+			var privateKey, publicKey []byte
+			if privateKey, err = readFile(item.PrivateKeyFile); err != nil {
+				return nil, err
+			}
+			if publicKey, err = readFile(item.PublicKeyFile); err != nil {
+				return nil, err
+			}
+			key, err = jwtx.ParseKeyFromPEM(item.ID, method, privateKey, publicKey)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwt key #%d: %w", i, err)
+		}
+		keys[i] = key
+	}
+
+	if !keys[0].CanSign() {
+		return nil, errors.New("the first jwt key must have a private key")
+	}
+	return keys, nil
+}
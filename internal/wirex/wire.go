@@ -17,6 +17,7 @@ import (
 func BuildInjector(ctx context.Context) (*Injector, func(), error) {
 	wire.Build(
 		InitCacher,
+		InitBus,
 		InitDB,
 		InitAuth,
 		wire.NewSet(wire.Struct(new(util.Trans), "*")),
@@ -9,6 +9,10 @@ package wirex
 import (
 	"context"
 	"github.com/supermicah/go-framework-admin/internal/mods"
+	"github.com/supermicah/go-framework-admin/internal/mods/org"
+	api3 "github.com/supermicah/go-framework-admin/internal/mods/org/api"
+	biz3 "github.com/supermicah/go-framework-admin/internal/mods/org/biz"
+	dal2 "github.com/supermicah/go-framework-admin/internal/mods/org/dal"
 	"github.com/supermicah/go-framework-admin/internal/mods/rbac"
 	"github.com/supermicah/go-framework-admin/internal/mods/rbac/api"
 	"github.com/supermicah/go-framework-admin/internal/mods/rbac/biz"
@@ -16,7 +20,7 @@ import (
 	"github.com/supermicah/go-framework-admin/internal/mods/sys"
 	api2 "github.com/supermicah/go-framework-admin/internal/mods/sys/api"
 	biz2 "github.com/supermicah/go-framework-admin/internal/mods/sys/biz"
-	dal2 "github.com/supermicah/go-framework-admin/internal/mods/sys/dal"
+	dal3 "github.com/supermicah/go-framework-admin/internal/mods/sys/dal"
 	"github.com/supermicah/go-framework-admin/pkg/util"
 )
 
@@ -47,73 +51,269 @@ func BuildInjector(ctx context.Context) (*Injector, func(), error) {
 	menuResource := &dal.MenuResource{
 		DB: db,
 	}
+	menuI18n := &dal.MenuI18n{
+		DB: db,
+	}
 	roleMenu := &dal.RoleMenu{
 		DB: db,
 	}
+	user := &dal.User{
+		DB: db,
+	}
+	bus, cleanup4, err := InitBus(ctx)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	casbinAdapter := &dal.CasbinAdapter{
+		DB: db,
+	}
+	casbinx := &biz.Casbinx{
+		Cache:         cacher,
+		CasbinAdapter: casbinAdapter,
+	}
+	role := &dal.Role{
+		DB: db,
+	}
+	dept := &dal2.Dept{
+		DB: db,
+	}
+	dataScope := &biz.DataScope{
+		Cache:   cacher,
+		RoleDAL: role,
+		DeptDAL: dept,
+	}
+	event := &biz.Event{
+		Bus:          bus,
+		Cache:        cacher,
+		Casbinx:      casbinx,
+		DataScopeBIZ: dataScope,
+	}
 	bizMenu := &biz.Menu{
-		Cache:           cacher,
 		Trans:           trans,
 		MenuDAL:         menu,
 		MenuResourceDAL: menuResource,
+		MenuI18nDAL:     menuI18n,
 		RoleMenuDAL:     roleMenu,
+		EventBIZ:        event,
 	}
 	apiMenu := &api.Menu{
 		MenuBIZ: bizMenu,
 	}
-	role := &dal.Role{
+	roleParent := &dal.RoleParent{
+		DB: db,
+	}
+	roleResource := &dal.RoleResource{
 		DB: db,
 	}
 	userRole := &dal.UserRole{
 		DB: db,
 	}
 	bizRole := &biz.Role{
-		Cache:       cacher,
-		Trans:       trans,
-		RoleDAL:     role,
-		RoleMenuDAL: roleMenu,
-		UserRoleDAL: userRole,
+		Trans:           trans,
+		RoleDAL:         role,
+		RoleMenuDAL:     roleMenu,
+		RoleParentDAL:   roleParent,
+		RoleResourceDAL: roleResource,
+		UserRoleDAL:     userRole,
+		EventBIZ:        event,
 	}
 	apiRole := &api.Role{
 		RoleBIZ: bizRole,
 	}
-	user := &dal.User{
+	apiKey := &dal.APIKey{
+		DB: db,
+	}
+	session := &biz.Session{
+		Cache:   cacher,
+		Auth:    auther,
+		UserDAL: user,
+	}
+	lockout := &biz.Lockout{
+		Cache: cacher,
+	}
+	passwordHistory := &dal.PasswordHistory{
+		DB: db,
+	}
+	password := &biz.Password{
+		PasswordHistoryDAL: passwordHistory,
+	}
+	userIdentity := &dal.UserIdentity{
+		DB: db,
+	}
+	deptUser := &dal2.DeptUser{
 		DB: db,
 	}
 	bizUser := &biz.User{
-		Cache:       cacher,
-		Trans:       trans,
-		UserDAL:     user,
-		UserRoleDAL: userRole,
+		Trans:              trans,
+		UserDAL:            user,
+		UserRoleDAL:        userRole,
+		APIKeyDAL:          apiKey,
+		SessionBIZ:         session,
+		LockoutBIZ:         lockout,
+		PasswordBIZ:        password,
+		PasswordHistoryDAL: passwordHistory,
+		UserIdentityDAL:    userIdentity,
+		DeptUserDAL:        deptUser,
+		EventBIZ:           event,
 	}
 	apiUser := &api.User{
 		UserBIZ: bizUser,
 	}
+	bizAPIKey := &biz.APIKey{
+		APIKeyDAL: apiKey,
+		UserBIZ:   bizUser,
+	}
+	twoFactor := &biz.TwoFactor{
+		Cache:      cacher,
+		UserDAL:    user,
+		RoleDAL:    role,
+		LockoutBIZ: lockout,
+	}
+	localAuth := &biz.LocalAuth{
+		UserDAL: user,
+	}
+	identity := &biz.Identity{
+		Trans:           trans,
+		UserDAL:         user,
+		UserRoleDAL:     userRole,
+		RoleDAL:         role,
+		UserIdentityDAL: userIdentity,
+		UserBIZ:         bizUser,
+	}
+	ldapAuth := &biz.LDAPAuth{
+		IdentityBIZ: identity,
+	}
+	tenant := &dal.Tenant{
+		DB: db,
+	}
+	bizTenant := &biz.Tenant{
+		Cache:     cacher,
+		TenantDAL: tenant,
+		UserDAL:   user,
+		RoleDAL:   role,
+	}
 	login := &biz.Login{
+		Cache:         cacher,
+		Trans:         trans,
+		Auth:          auther,
+		UserDAL:       user,
+		UserRoleDAL:   userRole,
+		MenuDAL:       menu,
+		RoleParentDAL: roleParent,
+		MenuBIZ:       bizMenu,
+		UserBIZ:       bizUser,
+		SessionBIZ:    session,
+		APIKeyBIZ:     bizAPIKey,
+		TwoFactorBIZ:  twoFactor,
+		LockoutBIZ:    lockout,
+		PasswordBIZ:   password,
+		LocalAuthBIZ:  localAuth,
+		LDAPAuthBIZ:   ldapAuth,
+		TenantBIZ:     bizTenant,
+		DataScopeBIZ:  dataScope,
+	}
+	apiLogin := &api.Login{
+		LoginBIZ: login,
+	}
+	apiSession := &api.Session{
+		SessionBIZ: session,
+	}
+	apiAPIKey := &api.APIKey{
+		APIKeyBIZ: bizAPIKey,
+	}
+	apiTwoFactor := &api.TwoFactor{
+		TwoFactorBIZ: twoFactor,
+	}
+	passwordReset := &biz.PasswordReset{
 		Cache:       cacher,
-		Auth:        auther,
+		Trans:       trans,
 		UserDAL:     user,
-		UserRoleDAL: userRole,
-		MenuDAL:     menu,
 		UserBIZ:     bizUser,
+		PasswordBIZ: password,
+		LockoutBIZ:  lockout,
 	}
-	apiLogin := &api.Login{
-		LoginBIZ: login,
+	apiPasswordReset := &api.PasswordReset{
+		PasswordResetBIZ: passwordReset,
+	}
+	oidc := &biz.OIDC{
+		Cache:       cacher,
+		UserBIZ:     bizUser,
+		LoginBIZ:    login,
+		IdentityBIZ: identity,
 	}
-	casbinx := &rbac.Casbinx{
-		Cache:           cacher,
+	apiOIDC := &api.OIDC{
+		OIDCBIZ: oidc,
+	}
+	apiTenant := &api.Tenant{
+		TenantBIZ: bizTenant,
+	}
+	permission := &biz.Permission{
+		Casbinx:         casbinx,
+		UserBIZ:         bizUser,
+		UserDAL:         user,
+		RoleDAL:         role,
+		RoleMenuDAL:     roleMenu,
+		RoleResourceDAL: roleResource,
+		MenuDAL:         menu,
+		MenuResourceDAL: menuResource,
+	}
+	apiPermission := &api.Permission{
+		PermissionBIZ: permission,
+	}
+	bundle := &biz.Bundle{
+		Trans:           trans,
+		MenuBIZ:         bizMenu,
+		RoleBIZ:         bizRole,
+		UserBIZ:         bizUser,
 		MenuDAL:         menu,
 		MenuResourceDAL: menuResource,
 		RoleDAL:         role,
+		RoleMenuDAL:     roleMenu,
+		RoleParentDAL:   roleParent,
+		RoleResourceDAL: roleResource,
+		UserDAL:         user,
+		UserRoleDAL:     userRole,
+		DeptDAL:         dept,
+		EventBIZ:        event,
+	}
+	apiBundle := &api.Bundle{
+		BundleBIZ: bundle,
+	}
+	route := &biz.Route{
+		MenuDAL:         menu,
+		MenuResourceDAL: menuResource,
+	}
+	apiRoute := &api.Route{
+		RouteBIZ: route,
+	}
+	userRoleSweeper := &biz.UserRoleSweeper{
+		Cache:       cacher,
+		UserRoleDAL: userRole,
+		EventBIZ:    event,
 	}
 	rbacRBAC := &rbac.RBAC{
-		DB:       db,
-		MenuAPI:  apiMenu,
-		RoleAPI:  apiRole,
-		UserAPI:  apiUser,
-		LoginAPI: apiLogin,
-		Casbinx:  casbinx,
+		DB:               db,
+		MenuAPI:          apiMenu,
+		RoleAPI:          apiRole,
+		UserAPI:          apiUser,
+		LoginAPI:         apiLogin,
+		SessionAPI:       apiSession,
+		APIKeyAPI:        apiAPIKey,
+		TwoFactorAPI:     apiTwoFactor,
+		PasswordResetAPI: apiPasswordReset,
+		OIDCAPI:          apiOIDC,
+		TenantAPI:        apiTenant,
+		PermissionAPI:    apiPermission,
+		BundleAPI:        apiBundle,
+		RouteAPI:         apiRoute,
+		Casbinx:          casbinx,
+		Event:            event,
+		UserRoleSweeper:  userRoleSweeper,
 	}
-	logger := &dal2.Logger{
+	logger := &dal3.Logger{
 		DB: db,
 	}
 	bizLogger := &biz2.Logger{
@@ -126,9 +326,24 @@ func BuildInjector(ctx context.Context) (*Injector, func(), error) {
 		DB:        db,
 		LoggerAPI: apiLogger,
 	}
+	bizDept := &biz3.Dept{
+		Trans:       trans,
+		DeptDAL:     dept,
+		DeptUserDAL: deptUser,
+		UserDAL:     user,
+		EventBIZ:    event,
+	}
+	apiDept := &api3.Dept{
+		DeptBIZ: bizDept,
+	}
+	orgORG := &org.ORG{
+		DB:      db,
+		DeptAPI: apiDept,
+	}
 	modsMods := &mods.Mods{
 		RBAC: rbacRBAC,
 		SYS:  sysSYS,
+		ORG:  orgORG,
 	}
 	injector := &Injector{
 		DB:    db,
@@ -137,6 +352,7 @@ func BuildInjector(ctx context.Context) (*Injector, func(), error) {
 		M:     modsMods,
 	}
 	return injector, func() {
+		cleanup4()
 		cleanup3()
 		cleanup2()
 		cleanup()
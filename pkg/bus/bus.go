@@ -0,0 +1,74 @@
+package bus
+
+import (
+	"context"
+	"sync"
+)
+
+// Handler Handles the messages published to a topic
+type Handler func(ctx context.Context, topic string, payload []byte) error
+
+// Bus Publishes messages to the subscribers of every instance. The subscribers of the publishing instance are called
+// synchronously by Publish, the subscribers of the other instances receive the messages asynchronously.
+type Bus interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+	Subscribe(ctx context.Context, topic string, handler Handler) error
+	Close(ctx context.Context) error
+}
+
+// NewMemoryBus Create a bus delivering the messages within the instance only, for single-node deployments
+func NewMemoryBus() Bus {
+	return &memBus{
+		handlers: newHandlers(),
+	}
+}
+
+type memBus struct {
+	handlers *handlers
+}
+
+func (a *memBus) Publish(ctx context.Context, topic string, payload []byte) error {
+	return a.handlers.dispatch(ctx, topic, payload)
+}
+
+func (a *memBus) Subscribe(ctx context.Context, topic string, handler Handler) error {
+	a.handlers.add(topic, handler)
+	return nil
+}
+
+func (a *memBus) Close(ctx context.Context) error {
+	return nil
+}
+
+// handlers Subscribers of the instance by topic
+type handlers struct {
+	mu sync.RWMutex
+	m  map[string][]Handler
+}
+
+func newHandlers() *handlers {
+	return &handlers{m: make(map[string][]Handler)}
+}
+
+// add Add the handler of the topic, reports whether it is the first one
+func (a *handlers) add(topic string, handler Handler) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.m[topic] = append(a.m[topic], handler)
+	return len(a.m[topic]) == 1
+}
+
+// dispatch Call every handler of the topic, the first error is returned
+func (a *handlers) dispatch(ctx context.Context, topic string, payload []byte) error {
+	a.mu.RLock()
+	list := a.m[topic]
+	a.mu.RUnlock()
+
+	var first error
+	for _, handler := range list {
+		if err := handler(ctx, topic, payload); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,32 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBus(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	b := NewMemoryBus()
+	defer b.Close(ctx)
+
+	var received []string
+	errFailed := errors.New("failed")
+	as.Nil(b.Subscribe(ctx, "foo", func(ctx context.Context, topic string, payload []byte) error {
+		received = append(received, topic+":"+string(payload))
+		return nil
+	}))
+	as.Nil(b.Subscribe(ctx, "foo", func(ctx context.Context, topic string, payload []byte) error {
+		return errFailed
+	}))
+
+	// Every handler is called even if one fails
+	as.Equal(errFailed, b.Publish(ctx, "foo", []byte("1")))
+	as.Nil(b.Publish(ctx, "bar", []byte("2")))
+	as.Equal([]string{"foo:1"}, received)
+}
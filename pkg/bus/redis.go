@@ -0,0 +1,147 @@
+package bus
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/supermicah/go-framework-admin/pkg/encoding/json"
+	"github.com/supermicah/go-framework-admin/pkg/logging"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+var defaultChannelPrefix = "bus:"
+
+type options struct {
+	ChannelPrefix string
+}
+
+type Option func(*options)
+
+// WithChannelPrefix Prefix of the redis channels, instances exchange messages only with the same prefix
+func WithChannelPrefix(prefix string) Option {
+	return func(o *options) {
+		o.ChannelPrefix = prefix
+	}
+}
+
+type RedisConfig struct {
+	Addr     string
+	Username string
+	Password string
+	DB       int
+}
+
+// NewRedisBus Create a bus delivering the messages to the other instances by redis pub/sub
+func NewRedisBus(cfg RedisConfig, opts ...Option) Bus {
+	cli := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	return newRedisBus(cli, opts...)
+}
+
+// NewRedisBusWithClient Use redis client create bus
+func NewRedisBusWithClient(cli *redis.Client, opts ...Option) Bus {
+	return newRedisBus(cli, opts...)
+}
+
+func newRedisBus(cli *redis.Client, opts ...Option) Bus {
+	defaultOpts := &options{
+		ChannelPrefix: defaultChannelPrefix,
+	}
+
+	for _, o := range opts {
+		o(defaultOpts)
+	}
+
+	return &redisBus{
+		opts:     defaultOpts,
+		cli:      cli,
+		source:   util.NewXID(),
+		handlers: newHandlers(),
+	}
+}
+
+// redisMessage Message sent to the redis channel
+type redisMessage struct {
+	Source  string `json:"source"` // Publishing instance, which already delivered the message to its subscribers
+	Payload []byte `json:"payload"`
+}
+
+type redisBus struct {
+	opts     *options
+	cli      *redis.Client
+	source   string
+	handlers *handlers
+	mu       sync.Mutex
+	pubSub   *redis.PubSub
+}
+
+func (a *redisBus) Publish(ctx context.Context, topic string, payload []byte) error {
+	buf, err := json.Marshal(&redisMessage{Source: a.source, Payload: payload})
+	if err != nil {
+		return err
+	}
+	if err := a.cli.Publish(ctx, a.opts.ChannelPrefix+topic, buf).Err(); err != nil {
+		return err
+	}
+	return a.handlers.dispatch(ctx, topic, payload)
+}
+
+func (a *redisBus) Subscribe(ctx context.Context, topic string, handler Handler) error {
+	if !a.handlers.add(topic, handler) {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	channel := a.opts.ChannelPrefix + topic
+	if a.pubSub != nil {
+		return a.pubSub.Subscribe(ctx, channel)
+	}
+
+	// The connection is opened by the first subscription, it stays in the subscribed mode
+	a.pubSub = a.cli.Subscribe(ctx, channel)
+	if _, err := a.pubSub.Receive(ctx); err != nil {
+		_ = a.pubSub.Close()
+		a.pubSub = nil
+		return err
+	}
+	go a.receive(a.pubSub.Channel())
+	return nil
+}
+
+func (a *redisBus) receive(ch <-chan *redis.Message) {
+	ctx := context.Background()
+	for msg := range ch {
+		var rmsg redisMessage
+		if err := json.Unmarshal([]byte(msg.Payload), &rmsg); err != nil {
+			logging.Context(ctx).Error("Failed to parse bus message", zap.Error(err), zap.String("channel", msg.Channel))
+			continue
+		} else if rmsg.Source == a.source {
+			continue
+		}
+
+		topic := strings.TrimPrefix(msg.Channel, a.opts.ChannelPrefix)
+		if err := a.handlers.dispatch(ctx, topic, rmsg.Payload); err != nil {
+			logging.Context(ctx).Error("Failed to handle bus message", zap.Error(err), zap.String("topic", topic))
+		}
+	}
+}
+
+func (a *redisBus) Close(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pubSub != nil {
+		_ = a.pubSub.Close()
+		a.pubSub = nil
+	}
+	return a.cli.Close()
+}
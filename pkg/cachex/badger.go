@@ -69,6 +69,33 @@ func (a *badgerCache) Set(ctx context.Context, ns, key, value string, expiration
 	})
 }
 
+func (a *badgerCache) SetNX(ctx context.Context, ns, key, value string, expiration ...time.Duration) (bool, error) {
+	ok := false
+	err := a.db.Update(func(txn *badger.Txn) error {
+		k := a.strToBytes(a.getKey(ns, key))
+		if _, err := txn.Get(k); err == nil {
+			return nil
+		} else if err != badger.ErrKeyNotFound {
+			return err
+		}
+
+		entry := badger.NewEntry(k, a.strToBytes(value))
+		if len(expiration) > 0 {
+			entry = entry.WithTTL(expiration[0])
+		}
+		ok = true
+		return txn.SetEntry(entry)
+	})
+	if err != nil {
+		// A concurrent transaction has written the key first
+		if err == badger.ErrConflict {
+			return false, nil
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
 func (a *badgerCache) Get(ctx context.Context, ns, key string) (string, bool, error) {
 	value := ""
 	ok := false
@@ -12,6 +12,8 @@ import (
 // Cacher is the interface that wraps the basic Get, Set, and Delete methods.
 type Cacher interface {
 	Set(ctx context.Context, ns, key, value string, expiration ...time.Duration) error
+	// SetNX Set the value only if the key does not exist, reporting whether it was set.
+	SetNX(ctx context.Context, ns, key, value string, expiration ...time.Duration) (bool, error)
 	Get(ctx context.Context, ns, key string) (string, bool, error)
 	GetAndDelete(ctx context.Context, ns, key string) (string, bool, error)
 	Exists(ctx context.Context, ns, key string) (bool, error)
@@ -72,6 +74,18 @@ func (a *memCache) Set(ctx context.Context, ns, key, value string, expiration ..
 	return nil
 }
 
+func (a *memCache) SetNX(ctx context.Context, ns, key, value string, expiration ...time.Duration) (bool, error) {
+	var exp time.Duration
+	if len(expiration) > 0 {
+		exp = expiration[0]
+	}
+
+	if err := a.cache.Add(a.getKey(ns, key), value, exp); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
 func (a *memCache) Get(ctx context.Context, ns, key string) (string, bool, error) {
 	val, ok := a.cache.Get(a.getKey(ns, key))
 	if !ok {
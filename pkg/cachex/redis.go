@@ -57,6 +57,7 @@ func newRedisCache(cli redisClienter, opts ...Option) Cacher {
 
 type redisClienter interface {
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd
 	Get(ctx context.Context, key string) *redis.StringCmd
 	Exists(ctx context.Context, keys ...string) *redis.IntCmd
 	Del(ctx context.Context, keys ...string) *redis.IntCmd
@@ -83,6 +84,19 @@ func (a *redisCache) Set(ctx context.Context, ns, key, value string, expiration
 	return cmd.Err()
 }
 
+func (a *redisCache) SetNX(ctx context.Context, ns, key, value string, expiration ...time.Duration) (bool, error) {
+	var exp time.Duration
+	if len(expiration) > 0 {
+		exp = expiration[0]
+	}
+
+	cmd := a.cli.SetNX(ctx, a.getKey(ns, key), value, exp)
+	if err := cmd.Err(); err != nil {
+		return false, err
+	}
+	return cmd.Val(), nil
+}
+
 func (a *redisCache) Get(ctx context.Context, ns, key string) (string, bool, error) {
 	cmd := a.cli.Get(ctx, a.getKey(ns, key))
 	if err := cmd.Err(); err != nil {
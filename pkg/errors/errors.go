@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -35,10 +36,18 @@ const (
 
 // Error Customize the error structure for implementation errors.Error interface
 type Error struct {
-	ID     string `json:"id,omitempty"`
-	Code   int32  `json:"code,omitempty"`
-	Detail string `json:"detail,omitempty"`
-	Status string `json:"status,omitempty"`
+	ID     string        `json:"id,omitempty"`
+	Code   int32         `json:"code,omitempty"`
+	Detail string        `json:"detail,omitempty"`
+	Status string        `json:"status,omitempty"`
+	Fields []*FieldError `json:"fields,omitempty"`
+}
+
+// FieldError Describe why a field of the request is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
 func (e *Error) Error() string {
@@ -80,6 +89,24 @@ func BadRequest(id, format string, a ...interface{}) error {
 	}
 }
 
+// InvalidFields generates a 400 error with the errors of the fields.
+func InvalidFields(id string, fields ...*FieldError) error {
+	if id == "" {
+		id = DefaultBadRequestID
+	}
+	messages := make([]string, len(fields))
+	for i, f := range fields {
+		messages[i] = f.Message
+	}
+	return &Error{
+		ID:     id,
+		Code:   http.StatusBadRequest,
+		Detail: strings.Join(messages, "; "),
+		Status: http.StatusText(http.StatusBadRequest),
+		Fields: fields,
+	}
+}
+
 // Unauthorized generates a 401 error.
 func Unauthorized(id, format string, a ...interface{}) error {
 	if id == "" {
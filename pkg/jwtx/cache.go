@@ -38,6 +38,18 @@ func (a *memCache) Set(ctx context.Context, ns, key, value string, expiration ..
 	return nil
 }
 
+func (a *memCache) SetNX(ctx context.Context, ns, key, value string, expiration ...time.Duration) (bool, error) {
+	var exp time.Duration
+	if len(expiration) > 0 {
+		exp = expiration[0]
+	}
+
+	if err := a.cache.Add(a.getKey(ns, key), value, exp); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
 func (a *memCache) Get(ctx context.Context, ns, key string) (string, bool, error) {
 	val, ok := a.cache.Get(a.getKey(ns, key))
 	if !ok {
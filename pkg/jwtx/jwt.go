@@ -6,30 +6,74 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/rs/xid"
 )
 
 type Auther interface {
-	// GenerateToken Generate a JWT (JSON Web Token) with the provided subject.
-	GenerateToken(ctx context.Context, subject string) (TokenInfo, error)
+	// GenerateToken Generate an access/refresh token pair with the provided subject.
+	GenerateToken(ctx context.Context, subject string, opts ...TokenOption) (TokenInfo, error)
+	// RefreshToken Rotate a refresh token, returning its claims and a new token pair of the same family.
+	RefreshToken(ctx context.Context, refreshToken string) (*Claims, TokenInfo, error)
 	// DestroyToken Invalidate a token by removing it from the token store.
 	DestroyToken(ctx context.Context, accessToken string) error
+	// RevokeFamily Invalidate every token issued from the same login.
+	RevokeFamily(ctx context.Context, familyID string) error
+	// ParseToken Parse the claims from a given access token.
+	ParseToken(ctx context.Context, accessToken string) (*Claims, error)
 	// ParseSubject Parse the subject (or user identifier) from a given access token.
 	ParseSubject(ctx context.Context, accessToken string) (string, error)
+	// GetJWKS Get the public keys of the key ring for verifying tokens elsewhere.
+	GetJWKS() *JSONWebKeySet
 	// Release any resources held by the JWTAuth instance.
 	Release(ctx context.Context) error
 }
 
 const defaultKey = "CG24SDVP8OHPK395GB5G"
 
-var ErrInvalidToken = errors.New("invalid token")
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+var (
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
+
+// Claims The JWT claims issued by JWTAuth, the family ID is shared by all tokens rotated from the same login.
+type Claims struct {
+	jwt.StandardClaims
+	TokenType string `json:"typ,omitempty"`
+	FamilyID  string `json:"fid,omitempty"`
+	Version   int64  `json:"ver,omitempty"` // Version of the subject's credentials, kept when the token is rotated
+	TenantID  int64  `json:"tid,omitempty"` // Tenant of the subject, kept when the token is rotated
+}
+
+// TokenOption Set extra claims of a token pair.
+type TokenOption func(*Claims)
+
+// WithVersion Embed the version of the subject's credentials, tokens of an outdated version can be rejected by the caller.
+func WithVersion(version int64) TokenOption {
+	return func(c *Claims) {
+		c.Version = version
+	}
+}
+
+// WithTenantID Embed the tenant of the subject.
+func WithTenantID(tenantID int64) TokenOption {
+	return func(c *Claims) {
+		c.TenantID = tenantID
+	}
+}
 
 type options struct {
-	signingMethod jwt.SigningMethod
-	signingKey    []byte
-	signingKey2   []byte
-	keyFuncs      []func(*jwt.Token) (interface{}, error)
-	expired       int
-	tokenType     string
+	signingMethod  jwt.SigningMethod
+	signingKey     []byte
+	signingKey2    []byte
+	keys           []*Key
+	expired        int
+	refreshExpired int
+	tokenType      string
 }
 
 type Option func(*options)
@@ -40,6 +84,8 @@ func SetSigningMethod(method jwt.SigningMethod) Option {
 	}
 }
 
+// SetSigningKey Set the secret key of the signing method when no key ring is set, tokens signed with the old key
+// are still accepted.
 func SetSigningKey(key, oldKey string) Option {
 	return func(o *options) {
 		o.signingKey = []byte(key)
@@ -49,44 +95,58 @@ func SetSigningKey(key, oldKey string) Option {
 	}
 }
 
+// SetKeys Set the key ring, the first key signs new tokens and all keys are used to verify tokens.
+func SetKeys(keys ...*Key) Option {
+	return func(o *options) {
+		o.keys = keys
+	}
+}
+
 func SetExpired(expired int) Option {
 	return func(o *options) {
 		o.expired = expired
 	}
 }
 
-func New(store Storer, opts ...Option) Auther {
+func SetRefreshExpired(expired int) Option {
+	return func(o *options) {
+		o.refreshExpired = expired
+	}
+}
+
+func New(store Storer, opts ...Option) (Auther, error) {
 	o := options{
-		tokenType:     "Bearer",
-		expired:       7200,
-		signingMethod: jwt.SigningMethodHS512,
-		signingKey:    []byte(defaultKey),
+		tokenType:      "Bearer",
+		expired:        7200,
+		refreshExpired: 604800,
+		signingMethod:  jwt.SigningMethodHS512,
+		signingKey:     []byte(defaultKey),
 	}
 
 	for _, opt := range opts {
 		opt(&o)
 	}
 
-	o.keyFuncs = append(o.keyFuncs, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
+	if len(o.keys) == 0 {
+		key, err := NewHMACKey("", o.signingMethod, o.signingKey)
+		if err != nil {
+			return nil, err
 		}
-		return o.signingKey, nil
-	})
+		o.keys = []*Key{key}
 
-	if o.signingKey2 != nil {
-		o.keyFuncs = append(o.keyFuncs, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, ErrInvalidToken
+		if o.signingKey2 != nil {
+			oldKey, err := NewHMACKey("", o.signingMethod, o.signingKey2)
+			if err != nil {
+				return nil, err
 			}
-			return o.signingKey2, nil
-		})
+			o.keys = append(o.keys, oldKey)
+		}
 	}
 
 	return &JWTAuth{
 		opts:  &o,
 		store: store,
-	}
+	}, nil
 }
 
 type JWTAuth struct {
@@ -94,38 +154,111 @@ type JWTAuth struct {
 	store Storer
 }
 
-func (a *JWTAuth) GenerateToken(ctx context.Context, subject string) (TokenInfo, error) {
+func (a *JWTAuth) signToken(claims *Claims) (string, error) {
+	key := a.opts.keys[0]
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.SignKey)
+}
+
+func (a *JWTAuth) keyFunc(key *Key) jwt.Keyfunc {
+	return func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, ErrInvalidToken
+		}
+		return key.VerifyKey, nil
+	}
+}
+
+func (a *JWTAuth) findKey(kid string) *Key {
+	for _, key := range a.opts.keys {
+		if key.ID == kid {
+			return key
+		}
+	}
+	return nil
+}
+
+func (a *JWTAuth) generateToken(subject, familyID string, version, tenantID int64) (TokenInfo, error) {
 	now := time.Now()
 	expiresAt := now.Add(time.Duration(a.opts.expired) * time.Second).Unix()
-
-	token := jwt.NewWithClaims(a.opts.signingMethod, &jwt.StandardClaims{
-		IssuedAt:  now.Unix(),
-		ExpiresAt: expiresAt,
-		NotBefore: now.Unix(),
-		Subject:   subject,
+	refreshExpiresAt := now.Add(time.Duration(a.opts.refreshExpired) * time.Second).Unix()
+
+	accessTokenID := xid.New().String()
+	accessToken, err := a.signToken(&Claims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        accessTokenID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt,
+			NotBefore: now.Unix(),
+			Subject:   subject,
+		},
+		TokenType: TokenTypeAccess,
+		FamilyID:  familyID,
+		Version:   version,
+		TenantID:  tenantID,
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	tokenStr, err := token.SignedString(a.opts.signingKey)
+	refreshToken, err := a.signToken(&Claims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        xid.New().String(),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: refreshExpiresAt,
+			NotBefore: now.Unix(),
+			Subject:   subject,
+		},
+		TokenType: TokenTypeRefresh,
+		FamilyID:  familyID,
+		Version:   version,
+		TenantID:  tenantID,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	tokenInfo := &tokenInfo{
-		ExpiresAt:   expiresAt,
-		TokenType:   a.opts.tokenType,
-		AccessToken: tokenStr,
+		ExpiresAt:        expiresAt,
+		TokenType:        a.opts.tokenType,
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+		TokenID:          accessTokenID,
+		FamilyID:         familyID,
 	}
 	return tokenInfo, nil
 }
 
-func (a *JWTAuth) parseToken(tokenStr string) (*jwt.StandardClaims, error) {
+func (a *JWTAuth) GenerateToken(ctx context.Context, subject string, opts ...TokenOption) (TokenInfo, error) {
+	claims := &Claims{}
+	for _, opt := range opts {
+		opt(claims)
+	}
+	return a.generateToken(subject, xid.New().String(), claims.Version, claims.TenantID)
+}
+
+func (a *JWTAuth) parseToken(tokenStr string) (*Claims, error) {
 	var (
 		token *jwt.Token
 		err   error
 	)
 
-	for _, keyFunc := range a.opts.keyFuncs {
-		token, err = jwt.ParseWithClaims(tokenStr, &jwt.StandardClaims{}, keyFunc)
+	keys := a.opts.keys
+	if unverified, _, _ := new(jwt.Parser).ParseUnverified(tokenStr, &Claims{}); unverified != nil {
+		// Tokens issued before key IDs were introduced are tried against every key
+		if kid, ok := unverified.Header["kid"].(string); ok {
+			key := a.findKey(kid)
+			if key == nil {
+				return nil, ErrInvalidToken
+			}
+			keys = []*Key{key}
+		}
+	}
+
+	for _, key := range keys {
+		token, err = jwt.ParseWithClaims(tokenStr, &Claims{}, a.keyFunc(key))
 		if err != nil || token == nil || !token.Valid {
 			continue
 		}
@@ -136,7 +269,7 @@ func (a *JWTAuth) parseToken(tokenStr string) (*jwt.StandardClaims, error) {
 		return nil, ErrInvalidToken
 	}
 
-	return token.Claims.(*jwt.StandardClaims), nil
+	return token.Claims.(*Claims), nil
 }
 
 func (a *JWTAuth) callStore(fn func(Storer) error) error {
@@ -146,26 +279,103 @@ func (a *JWTAuth) callStore(fn func(Storer) error) error {
 	return nil
 }
 
+// revokeFamily Invalidate every token of the family, it lasts as long as a refresh token issued right now.
+func (a *JWTAuth) revokeFamily(ctx context.Context, familyID string) error {
+	if familyID == "" {
+		return nil
+	}
+	return a.callStore(func(store Storer) error {
+		expired := time.Duration(a.opts.refreshExpired) * time.Second
+		return store.Set(ctx, familyKey(familyID), expired)
+	})
+}
+
+func (a *JWTAuth) checkFamily(ctx context.Context, familyID string) error {
+	if familyID == "" {
+		return nil
+	}
+	return a.callStore(func(store Storer) error {
+		if exists, err := store.Check(ctx, familyKey(familyID)); err != nil {
+			return err
+		} else if exists {
+			return ErrInvalidToken
+		}
+		return nil
+	})
+}
+
+func (a *JWTAuth) RefreshToken(ctx context.Context, refreshToken string) (*Claims, TokenInfo, error) {
+	if refreshToken == "" {
+		return nil, nil, ErrInvalidToken
+	}
+
+	claims, err := a.parseToken(refreshToken)
+	if err != nil {
+		return nil, nil, err
+	} else if claims.TokenType != TokenTypeRefresh {
+		return nil, nil, ErrInvalidToken
+	}
+
+	if err := a.checkFamily(ctx, claims.FamilyID); err != nil {
+		return claims, nil, err
+	}
+
+	// A refresh token can only be used once, presenting it again means it has leaked. The token is marked atomically,
+	// so only one of the concurrent refreshes with the same token succeeds.
+	err = a.callStore(func(store Storer) error {
+		expired := time.Until(time.Unix(claims.ExpiresAt, 0))
+		if ok, err := store.SetNX(ctx, refreshKey(claims.Id), expired); err != nil {
+			return err
+		} else if !ok {
+			if err := a.revokeFamily(ctx, claims.FamilyID); err != nil {
+				return err
+			}
+			return ErrRefreshTokenReused
+		}
+		return nil
+	})
+	if err != nil {
+		return claims, nil, err
+	}
+
+	token, err := a.generateToken(claims.Subject, claims.FamilyID, claims.Version, claims.TenantID)
+	if err != nil {
+		return claims, nil, err
+	}
+	return claims, token, nil
+}
+
 func (a *JWTAuth) DestroyToken(ctx context.Context, tokenStr string) error {
 	claims, err := a.parseToken(tokenStr)
 	if err != nil {
 		return err
 	}
 
-	return a.callStore(func(store Storer) error {
+	err = a.callStore(func(store Storer) error {
 		expired := time.Until(time.Unix(claims.ExpiresAt, 0))
 		return store.Set(ctx, tokenStr, expired)
 	})
+	if err != nil {
+		return err
+	}
+
+	return a.revokeFamily(ctx, claims.FamilyID)
 }
 
-func (a *JWTAuth) ParseSubject(ctx context.Context, tokenStr string) (string, error) {
+func (a *JWTAuth) RevokeFamily(ctx context.Context, familyID string) error {
+	return a.revokeFamily(ctx, familyID)
+}
+
+func (a *JWTAuth) ParseToken(ctx context.Context, tokenStr string) (*Claims, error) {
 	if tokenStr == "" {
-		return "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
 	claims, err := a.parseToken(tokenStr)
 	if err != nil {
-		return "", err
+		return nil, err
+	} else if claims.TokenType == TokenTypeRefresh {
+		return nil, ErrInvalidToken
 	}
 
 	err = a.callStore(func(store Storer) error {
@@ -177,12 +387,34 @@ func (a *JWTAuth) ParseSubject(ctx context.Context, tokenStr string) (string, er
 		return nil
 	})
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	if err := a.checkFamily(ctx, claims.FamilyID); err != nil {
+		return nil, err
 	}
 
+	return claims, nil
+}
+
+func (a *JWTAuth) ParseSubject(ctx context.Context, tokenStr string) (string, error) {
+	claims, err := a.ParseToken(ctx, tokenStr)
+	if err != nil {
+		return "", err
+	}
 	return claims.Subject, nil
 }
 
+func (a *JWTAuth) GetJWKS() *JSONWebKeySet {
+	set := &JSONWebKeySet{Keys: make([]*JSONWebKey, 0, len(a.opts.keys))}
+	for _, key := range a.opts.keys {
+		if jwk, ok := key.JWK(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
 func (a *JWTAuth) Release(ctx context.Context) error {
 	return a.callStore(func(store Storer) error {
 		return store.Close(ctx)
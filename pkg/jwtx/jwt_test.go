@@ -2,6 +2,7 @@ package jwtx
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,7 +14,7 @@ func TestAuth(t *testing.T) {
 
 	store := NewStoreWithCache(cache)
 	ctx := context.Background()
-	jwtAuth := New(store)
+	jwtAuth := mustNew(t, store)
 
 	userID := "test"
 	token, err := jwtAuth.GenerateToken(ctx, userID)
@@ -35,3 +36,119 @@ func TestAuth(t *testing.T) {
 	err = jwtAuth.Release(ctx)
 	assert.Nil(t, err)
 }
+
+func TestRefreshToken(t *testing.T) {
+	cache := NewMemoryCache(MemoryConfig{CleanupInterval: time.Second})
+
+	store := NewStoreWithCache(cache)
+	ctx := context.Background()
+	jwtAuth := mustNew(t, store)
+
+	userID := "test"
+	token, err := jwtAuth.GenerateToken(ctx, userID)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, token.GetRefreshToken())
+	assert.Greater(t, token.GetRefreshExpiresAt(), token.GetExpiresAt())
+
+	_, err = jwtAuth.ParseSubject(ctx, token.GetRefreshToken())
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+
+	_, _, err = jwtAuth.RefreshToken(ctx, token.GetAccessToken())
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+
+	claims, newToken, err := jwtAuth.RefreshToken(ctx, token.GetRefreshToken())
+	assert.Nil(t, err)
+	assert.Equal(t, userID, claims.Subject)
+	assert.Equal(t, token.GetFamilyID(), claims.FamilyID)
+	assert.Equal(t, token.GetFamilyID(), newToken.GetFamilyID())
+	assert.NotEqual(t, token.GetRefreshToken(), newToken.GetRefreshToken())
+
+	claims, err = jwtAuth.ParseToken(ctx, newToken.GetAccessToken())
+	assert.Nil(t, err)
+	assert.Equal(t, userID, claims.Subject)
+	assert.Equal(t, newToken.GetTokenID(), claims.Id)
+
+	// The version and the tenant are kept when the token is rotated.
+	versionToken, err := jwtAuth.GenerateToken(ctx, userID, WithVersion(3), WithTenantID(5))
+	assert.Nil(t, err)
+	claims, versionToken, err = jwtAuth.RefreshToken(ctx, versionToken.GetRefreshToken())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), claims.Version)
+	assert.Equal(t, int64(5), claims.TenantID)
+	claims, err = jwtAuth.ParseToken(ctx, versionToken.GetAccessToken())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), claims.Version)
+	assert.Equal(t, int64(5), claims.TenantID)
+
+	// Reusing a rotated refresh token revokes the whole family.
+	_, _, err = jwtAuth.RefreshToken(ctx, token.GetRefreshToken())
+	assert.EqualError(t, err, ErrRefreshTokenReused.Error())
+
+	_, err = jwtAuth.ParseSubject(ctx, newToken.GetAccessToken())
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+
+	_, _, err = jwtAuth.RefreshToken(ctx, newToken.GetRefreshToken())
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+
+	// Other logins are not affected.
+	otherToken, err := jwtAuth.GenerateToken(ctx, userID)
+	assert.Nil(t, err)
+	_, err = jwtAuth.ParseSubject(ctx, otherToken.GetAccessToken())
+	assert.Nil(t, err)
+
+	// Revoking the family logs out the whole login.
+	revokedToken, err := jwtAuth.GenerateToken(ctx, userID)
+	assert.Nil(t, err)
+	err = jwtAuth.RevokeFamily(ctx, revokedToken.GetFamilyID())
+	assert.Nil(t, err)
+	_, err = jwtAuth.ParseSubject(ctx, revokedToken.GetAccessToken())
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+	_, _, err = jwtAuth.RefreshToken(ctx, revokedToken.GetRefreshToken())
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+
+	// Logout revokes the refresh token of the same login.
+	err = jwtAuth.DestroyToken(ctx, otherToken.GetAccessToken())
+	assert.Nil(t, err)
+	_, _, err = jwtAuth.RefreshToken(ctx, otherToken.GetRefreshToken())
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+
+	err = jwtAuth.Release(ctx)
+	assert.Nil(t, err)
+}
+
+func TestRefreshTokenConcurrent(t *testing.T) {
+	cache := NewMemoryCache(MemoryConfig{CleanupInterval: time.Second})
+
+	store := NewStoreWithCache(cache)
+	ctx := context.Background()
+	jwtAuth := mustNew(t, store)
+
+	token, err := jwtAuth.GenerateToken(ctx, "test")
+	assert.Nil(t, err)
+
+	// Only one of the concurrent refreshes with the same token succeeds, the others are detected as a reuse.
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		success int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := jwtAuth.RefreshToken(ctx, token.GetRefreshToken())
+			if err == nil {
+				mu.Lock()
+				success++
+				mu.Unlock()
+			} else {
+				assert.Contains(t, []error{ErrRefreshTokenReused, ErrInvalidToken}, err)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, success)
+
+	err = jwtAuth.Release(ctx)
+	assert.Nil(t, err)
+}
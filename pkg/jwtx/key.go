@@ -0,0 +1,209 @@
+package jwtx
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"math/big"
+
+	"github.com/golang-jwt/jwt"
+)
+
+var ErrUnsupportedKey = errors.New("unsupported signing key")
+
+// Key A key of the key ring, asymmetric keys without a private part can only verify tokens.
+type Key struct {
+	ID        string            // Key ID written into the `kid` header
+	Method    jwt.SigningMethod // Signing method
+	SignKey   interface{}       // Secret or private key, nil if the key is only used for verification
+	VerifyKey interface{}       // Secret or public key
+}
+
+// CanSign Reports whether the key holds the secret or private part.
+func (k *Key) CanSign() bool {
+	return k.SignKey != nil
+}
+
+// NewHMACKey Create a symmetric key, the key ID is derived from the secret if empty.
+func NewHMACKey(id string, method jwt.SigningMethod, secret []byte) (*Key, error) {
+	if _, ok := method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, ErrUnsupportedKey
+	}
+
+	if id == "" {
+		sum := sha256.Sum256(secret)
+		id = hex.EncodeToString(sum[:8])
+	}
+
+	return &Key{
+		ID:        id,
+		Method:    method,
+		SignKey:   secret,
+		VerifyKey: secret,
+	}, nil
+}
+
+// ParseKeyFromPEM Create an RSA/ECDSA/Ed25519 key from PEM encoded keys, the public key is derived from the private
+// key if it is empty and the private key may be empty for keys that are only used for verification.
+func ParseKeyFromPEM(id string, method jwt.SigningMethod, privateKey, publicKey []byte) (*Key, error) {
+	var (
+		signKey   crypto.Signer
+		verifyKey crypto.PublicKey
+		err       error
+	)
+
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		if len(privateKey) > 0 {
+			signKey, err = jwt.ParseRSAPrivateKeyFromPEM(privateKey)
+		} else {
+			verifyKey, err = jwt.ParseRSAPublicKeyFromPEM(publicKey)
+		}
+	case *jwt.SigningMethodECDSA:
+		if len(privateKey) > 0 {
+			signKey, err = jwt.ParseECPrivateKeyFromPEM(privateKey)
+		} else {
+			verifyKey, err = jwt.ParseECPublicKeyFromPEM(publicKey)
+		}
+	case *jwt.SigningMethodEd25519:
+		if len(privateKey) > 0 {
+			var key crypto.PrivateKey
+			if key, err = jwt.ParseEdPrivateKeyFromPEM(privateKey); err == nil {
+				signKey = key.(crypto.Signer)
+			}
+		} else {
+			verifyKey, err = jwt.ParseEdPublicKeyFromPEM(publicKey)
+		}
+	default:
+		return nil, ErrUnsupportedKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{
+		ID:        id,
+		Method:    method,
+		VerifyKey: verifyKey,
+	}
+	if signKey != nil {
+		key.SignKey = signKey
+		key.VerifyKey = signKey.Public()
+	}
+
+	if key.ID == "" {
+		der, err := x509.MarshalPKIXPublicKey(key.VerifyKey)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(der)
+		key.ID = base64.RawURLEncoding.EncodeToString(sum[:12])
+	}
+	return key, nil
+}
+
+// JSONWebKey The public part of a key as described in RFC 7517.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet A set of public keys as described in RFC 7517.
+type JSONWebKeySet struct {
+	Keys []*JSONWebKey `json:"keys"`
+}
+
+// JWK Convert the public part of the key, symmetric keys must never be published.
+func (k *Key) JWK() (*JSONWebKey, bool) {
+	encode := base64.RawURLEncoding.EncodeToString
+	jwk := &JSONWebKey{
+		KeyID:     k.ID,
+		Use:       "sig",
+		Algorithm: k.Method.Alg(),
+	}
+
+	switch pub := k.VerifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encode(pub.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = pub.Curve.Params().Name
+		jwk.X = encode(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = encode(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encode(pub)
+	default:
+		return nil, false
+	}
+	return jwk, true
+}
+
+// PublicKey Decode the public key of the JWK, the inverse of Key.JWK.
+func (k *JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	decode := base64.RawURLEncoding.DecodeString
+	switch k.KeyType {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, ErrUnsupportedKey
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, ErrUnsupportedKey
+		}
+		return pub, nil
+	case "OKP":
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		} else if k.Curve != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedKey
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, ErrUnsupportedKey
+}
@@ -0,0 +1,168 @@
+package jwtx
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func encodePEM(t *testing.T, privateKey interface{}) ([]byte, []byte) {
+	privDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.Nil(t, err)
+
+	var pub interface{}
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		pub = k.Public()
+		privDER = x509.MarshalPKCS1PrivateKey(k)
+	case *ecdsa.PrivateKey:
+		pub = k.Public()
+		privDER, err = x509.MarshalECPrivateKey(k)
+		assert.Nil(t, err)
+	case ed25519.PrivateKey:
+		pub = k.Public()
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	assert.Nil(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+}
+
+func TestAsymmetricKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		method     jwt.SigningMethod
+		privateKey interface{}
+		keyType    string
+	}{
+		{jwt.SigningMethodRS256, rsaKey, "RSA"},
+		{jwt.SigningMethodES256, ecKey, "EC"},
+		{jwt.SigningMethodEdDSA, edKey, "OKP"},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		privPEM, pubPEM := encodePEM(t, tt.privateKey)
+		key, err := ParseKeyFromPEM("", tt.method, privPEM, nil)
+		assert.Nil(t, err)
+		assert.True(t, key.CanSign())
+		assert.NotEmpty(t, key.ID)
+
+		jwtAuth := mustNew(t, nil, SetKeys(key))
+		token, err := jwtAuth.GenerateToken(ctx, "test")
+		assert.Nil(t, err)
+
+		parsed, _, err := new(jwt.Parser).ParseUnverified(token.GetAccessToken(), &Claims{})
+		assert.Nil(t, err)
+		assert.Equal(t, key.ID, parsed.Header["kid"])
+		assert.Equal(t, tt.method.Alg(), parsed.Method.Alg())
+
+		// Verify with the public key only
+		pubKey, err := ParseKeyFromPEM("", tt.method, nil, pubPEM)
+		assert.Nil(t, err)
+		assert.False(t, pubKey.CanSign())
+		assert.Equal(t, key.ID, pubKey.ID)
+
+		verifier := mustNew(t, nil, SetKeys(pubKey))
+		id, err := verifier.ParseSubject(ctx, token.GetAccessToken())
+		assert.Nil(t, err)
+		assert.Equal(t, "test", id)
+
+		jwks := verifier.GetJWKS()
+		assert.Len(t, jwks.Keys, 1)
+		assert.Equal(t, tt.keyType, jwks.Keys[0].KeyType)
+		assert.Equal(t, key.ID, jwks.Keys[0].KeyID)
+		assert.Equal(t, tt.method.Alg(), jwks.Keys[0].Algorithm)
+
+		// Verify with the key decoded from the JWK
+		decoded, err := jwks.Keys[0].PublicKey()
+		assert.Nil(t, err)
+		_, err = jwt.Parse(token.GetAccessToken(), func(*jwt.Token) (interface{}, error) { return decoded, nil })
+		assert.Nil(t, err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	ctx := context.Background()
+
+	oldKey, err := NewHMACKey("old", jwt.SigningMethodHS512, []byte("old-secret"))
+	assert.Nil(t, err)
+	oldToken, err := mustNew(t, nil, SetKeys(oldKey)).GenerateToken(ctx, "test")
+	assert.Nil(t, err)
+
+	// Tokens without key ID are still accepted
+	legacyToken, err := mustNew(t, nil, SetSigningKey("old-secret", "")).GenerateToken(ctx, "test")
+	assert.Nil(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	privPEM, _ := encodePEM(t, ecKey)
+	newKey, err := ParseKeyFromPEM("new", jwt.SigningMethodES256, privPEM, nil)
+	assert.Nil(t, err)
+
+	legacyKey, err := NewHMACKey("", jwt.SigningMethodHS512, []byte("old-secret"))
+	assert.Nil(t, err)
+
+	jwtAuth := mustNew(t, nil, SetKeys(newKey, oldKey, legacyKey))
+	for _, token := range []TokenInfo{oldToken, legacyToken} {
+		id, err := jwtAuth.ParseSubject(ctx, token.GetAccessToken())
+		assert.Nil(t, err)
+		assert.Equal(t, "test", id)
+	}
+
+	newToken, err := jwtAuth.GenerateToken(ctx, "test")
+	assert.Nil(t, err)
+	_, err = mustNew(t, nil, SetKeys(oldKey)).ParseSubject(ctx, newToken.GetAccessToken())
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+
+	// Symmetric keys are never published
+	jwks := jwtAuth.GetJWKS()
+	assert.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "new", jwks.Keys[0].KeyID)
+}
+
+func TestOldSigningKey(t *testing.T) {
+	ctx := context.Background()
+
+	oldToken, err := mustNew(t, nil, SetSigningKey("old-secret", "")).GenerateToken(ctx, "test")
+	assert.Nil(t, err)
+
+	// The old key only verifies tokens, new tokens are signed with the new key
+	jwtAuth := mustNew(t, nil, SetSigningKey("new-secret", "old-secret"))
+	id, err := jwtAuth.ParseSubject(ctx, oldToken.GetAccessToken())
+	assert.Nil(t, err)
+	assert.Equal(t, "test", id)
+
+	newToken, err := jwtAuth.GenerateToken(ctx, "test")
+	assert.Nil(t, err)
+	_, err = mustNew(t, nil, SetSigningKey("old-secret", "")).ParseSubject(ctx, newToken.GetAccessToken())
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+
+	// An asymmetric signing method requires a key ring
+	_, err = New(nil, SetSigningMethod(jwt.SigningMethodRS256))
+	assert.NotNil(t, err)
+}
+
+func mustNew(t *testing.T, store Storer, opts ...Option) Auther {
+	jwtAuth, err := New(store, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jwtAuth
+}
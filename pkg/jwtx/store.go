@@ -8,11 +8,23 @@ import (
 // Storer is the interface that storage the token.
 type Storer interface {
 	Set(ctx context.Context, tokenStr string, expiration time.Duration) error
+	// SetNX Set the token only if it is not stored yet, reporting whether it was set.
+	SetNX(ctx context.Context, tokenStr string, expiration time.Duration) (bool, error)
 	Delete(ctx context.Context, tokenStr string) error
 	Check(ctx context.Context, tokenStr string) (bool, error)
 	Close(ctx context.Context) error
 }
 
+// familyKey The store key marking a revoked token family.
+func familyKey(familyID string) string {
+	return "family:" + familyID
+}
+
+// refreshKey The store key marking a refresh token that has already been rotated.
+func refreshKey(tokenID string) string {
+	return "refresh:" + tokenID
+}
+
 type storeOptions struct {
 	CacheNS string // default "jwt"
 }
@@ -27,6 +39,7 @@ func WithCacheNS(ns string) StoreOption {
 
 type Cacher interface {
 	Set(ctx context.Context, ns, key, value string, expiration ...time.Duration) error
+	SetNX(ctx context.Context, ns, key, value string, expiration ...time.Duration) (bool, error)
 	Get(ctx context.Context, ns, key string) (string, bool, error)
 	Exists(ctx context.Context, ns, key string) (bool, error)
 	Delete(ctx context.Context, ns, key string) error
@@ -55,6 +68,10 @@ func (s *storeImpl) Set(ctx context.Context, tokenStr string, expiration time.Du
 	return s.c.Set(ctx, s.opts.CacheNS, tokenStr, "", expiration)
 }
 
+func (s *storeImpl) SetNX(ctx context.Context, tokenStr string, expiration time.Duration) (bool, error) {
+	return s.c.SetNX(ctx, s.opts.CacheNS, tokenStr, "", expiration)
+}
+
 func (s *storeImpl) Delete(ctx context.Context, tokenStr string) error {
 	return s.c.Delete(ctx, s.opts.CacheNS, tokenStr)
 }
@@ -8,13 +8,21 @@ type TokenInfo interface {
 	GetAccessToken() string
 	GetTokenType() string
 	GetExpiresAt() int64
+	GetRefreshToken() string
+	GetRefreshExpiresAt() int64
+	GetTokenID() string
+	GetFamilyID() string
 	EncodeToJSON() ([]byte, error)
 }
 
 type tokenInfo struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresAt   int64  `json:"expires_at"`
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresAt        int64  `json:"expires_at"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresAt int64  `json:"refresh_expires_at"`
+	TokenID          string `json:"-"`
+	FamilyID         string `json:"-"`
 }
 
 func (t *tokenInfo) GetAccessToken() string {
@@ -29,6 +37,22 @@ func (t *tokenInfo) GetExpiresAt() int64 {
 	return t.ExpiresAt
 }
 
+func (t *tokenInfo) GetRefreshToken() string {
+	return t.RefreshToken
+}
+
+func (t *tokenInfo) GetRefreshExpiresAt() int64 {
+	return t.RefreshExpiresAt
+}
+
+func (t *tokenInfo) GetTokenID() string {
+	return t.TokenID
+}
+
+func (t *tokenInfo) GetFamilyID() string {
+	return t.FamilyID
+}
+
 func (t *tokenInfo) EncodeToJSON() ([]byte, error) {
 	return jsoniter.Marshal(t)
 }
@@ -0,0 +1,100 @@
+package ldaptest
+
+import (
+	"errors"
+	"strings"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+var errInvalidFilter = errors.New("invalid filter")
+
+// match Reports whether the entry matches the encoded filter, names and values are compared case-insensitively.
+// Extensible matches are not supported.
+func match(p *ber.Packet, e *ldap.Entry) (bool, error) {
+	if p.ClassType != ber.ClassContext {
+		return false, errInvalidFilter
+	}
+
+	switch p.Tag {
+	case ldap.FilterAnd, ldap.FilterOr:
+		if len(p.Children) == 0 {
+			return false, errInvalidFilter
+		}
+		for _, child := range p.Children {
+			ok, err := match(child, e)
+			if err != nil {
+				return false, err
+			} else if ok == (p.Tag == ldap.FilterOr) {
+				return ok, nil
+			}
+		}
+		return p.Tag == ldap.FilterAnd, nil
+	case ldap.FilterNot:
+		if len(p.Children) != 1 {
+			return false, errInvalidFilter
+		}
+		ok, err := match(p.Children[0], e)
+		return !ok, err
+	case ldap.FilterPresent:
+		attr := p.Data.String()
+		return strings.EqualFold(attr, "objectClass") || len(e.GetEqualFoldAttributeValues(attr)) > 0, nil
+	case ldap.FilterEqualityMatch, ldap.FilterApproxMatch, ldap.FilterGreaterOrEqual, ldap.FilterLessOrEqual,
+		ldap.FilterSubstrings:
+		if len(p.Children) != 2 {
+			return false, errInvalidFilter
+		}
+	default:
+		return false, errInvalidFilter
+	}
+
+	assertion := p.Children[1]
+	for _, v := range e.GetEqualFoldAttributeValues(p.Children[0].Data.String()) {
+		v = strings.ToLower(v)
+		want := strings.ToLower(assertion.Data.String())
+		switch p.Tag {
+		case ldap.FilterEqualityMatch, ldap.FilterApproxMatch:
+			if v == want {
+				return true, nil
+			}
+		case ldap.FilterGreaterOrEqual:
+			if v >= want {
+				return true, nil
+			}
+		case ldap.FilterLessOrEqual:
+			if v <= want {
+				return true, nil
+			}
+		case ldap.FilterSubstrings:
+			if matchSubstrings(v, assertion.Children) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func matchSubstrings(v string, subs []*ber.Packet) bool {
+	for _, sub := range subs {
+		s := strings.ToLower(sub.Data.String())
+		switch sub.Tag {
+		case ldap.FilterSubstringsInitial:
+			if !strings.HasPrefix(v, s) {
+				return false
+			}
+			v = v[len(s):]
+		case ldap.FilterSubstringsAny:
+			i := strings.Index(v, s)
+			if i < 0 {
+				return false
+			}
+			v = v[i+len(s):]
+		case ldap.FilterSubstringsFinal:
+			if !strings.HasSuffix(v, s) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,367 @@
+// Package ldaptest provides an in-process directory server for tests, supporting simple bind, search and StartTLS.
+// Searches require a successful bind, like most directories configured for authentication.
+package ldaptest
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// startTLSOID Name of the StartTLS extended operation
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+type entry struct {
+	ldap.Entry
+	password string
+}
+
+// Server An in-memory directory listening on the loopback interface.
+type Server struct {
+	URL string // ldap:// or ldaps:// URL of the server
+
+	listener  net.Listener
+	tlsConfig *tls.Config
+	certPEM   []byte
+	mu        sync.Mutex
+	entries   []*entry
+	binds     int
+	conns     map[net.Conn]struct{}
+	wg        sync.WaitGroup
+}
+
+// NewServer Start a plain server which supports StartTLS.
+func NewServer() *Server {
+	return newServer(false)
+}
+
+// NewTLSServer Start a server accepting TLS connections only (ldaps).
+func NewTLSServer() *Server {
+	return newServer(true)
+}
+
+func newServer(useTLS bool) *Server {
+	s := &Server{conns: make(map[net.Conn]struct{})}
+	s.tlsConfig, s.certPEM = newCertificate()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	s.URL = "ldap://" + listener.Addr().String()
+	if useTLS {
+		listener = tls.NewListener(listener, s.tlsConfig)
+		s.URL = "ldaps://" + listener.Addr().String()
+	}
+	s.listener = listener
+
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// newCertificate Generate a self-signed certificate for 127.0.0.1 and localhost.
+func newCertificate() (*tls.Config, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ldaptest"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+
+	cert := tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	return &tls.Config{Certificates: []tls.Certificate{cert}},
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// CertificatePEM Get the self-signed certificate of the server to trust.
+func (s *Server) CertificatePEM() []byte {
+	return s.certPEM
+}
+
+// CertPool Get a pool trusting the certificate of the server.
+func (s *Server) CertPool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(s.certPEM)
+	return pool
+}
+
+// AddEntry Add an entry, entries with a password can bind.
+func (s *Server) AddEntry(dn, password string, attrs map[string][]string) {
+	e := &entry{Entry: *ldap.NewEntry(dn, attrs), password: password}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, e)
+}
+
+// SetAttribute Replace the values of the attribute of the entry.
+func (s *Server) SetAttribute(dn, name string, values ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		if !strings.EqualFold(e.DN, dn) {
+			continue
+		}
+		attr := ldap.NewEntryAttribute(name, values)
+		for i, item := range e.Attributes {
+			if strings.EqualFold(item.Name, name) {
+				e.Attributes[i] = attr
+				return
+			}
+		}
+		e.Attributes = append(e.Attributes, attr)
+	}
+}
+
+// Binds Get the number of successful binds.
+func (s *Server) Binds() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.binds
+}
+
+// Close Stop the server and close the open connections.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// session State of a connection
+type session struct {
+	conn  net.Conn
+	r     *bufio.Reader
+	bound bool
+}
+
+func (s *Server) handle(conn net.Conn) {
+	sess := &session{conn: conn, r: bufio.NewReader(conn)}
+	defer func() { _ = sess.conn.Close() }()
+
+	for {
+		_ = sess.conn.SetDeadline(time.Now().Add(time.Minute))
+		msg, err := ber.ReadPacket(sess.r)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+		msgID, ok := msg.Children[0].Value.(int64)
+		if !ok {
+			return
+		}
+
+		op := msg.Children[1]
+		if op.ClassType != ber.ClassApplication {
+			return
+		}
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			s.write(sess, msgID, s.bind(sess, op))
+		case ldap.ApplicationSearchRequest:
+			for _, resp := range s.search(sess, op) {
+				s.write(sess, msgID, resp)
+			}
+		case ldap.ApplicationExtendedRequest:
+			if len(op.Children) == 0 || op.Children[0].Data.String() != startTLSOID {
+				s.write(sess, msgID, newResult(ldap.ApplicationExtendedResponse, ldap.LDAPResultProtocolError, "unsupported extended operation"))
+				continue
+			}
+			if _, ok := sess.conn.(*tls.Conn); ok {
+				s.write(sess, msgID, newResult(ldap.ApplicationExtendedResponse, ldap.LDAPResultOperationsError, "TLS already established"))
+				continue
+			}
+			s.write(sess, msgID, newResult(ldap.ApplicationExtendedResponse, ldap.LDAPResultSuccess, ""))
+			tlsConn := tls.Server(sess.conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			sess.conn, sess.r = tlsConn, bufio.NewReader(tlsConn)
+		default:
+			// Unbind and unsupported operations close the connection
+			return
+		}
+	}
+}
+
+func (s *Server) write(sess *session, msgID int64, op *ber.Packet) {
+	msg := ber.NewSequence("LDAP Response")
+	msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, msgID, "MessageID"))
+	msg.AppendChild(op)
+	_, _ = sess.conn.Write(msg.Bytes())
+}
+
+func (s *Server) bind(sess *session, op *ber.Packet) *ber.Packet {
+	sess.bound = false
+	if len(op.Children) != 3 {
+		return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultProtocolError, "invalid bind request")
+	}
+	dn, auth := op.Children[1].Data.String(), op.Children[2]
+	if auth.ClassType != ber.ClassContext || auth.Tag != 0 {
+		return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultUnwillingToPerform, "only simple bind is supported")
+	}
+	password := auth.Data.String()
+	if password == "" {
+		return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultUnwillingToPerform, "unauthenticated bind is not allowed")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		if strings.EqualFold(e.DN, dn) && e.password != "" && e.password == password {
+			sess.bound = true
+			s.binds++
+			return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess, "")
+		}
+	}
+	return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials, "invalid credentials")
+}
+
+func (s *Server) search(sess *session, op *ber.Packet) []*ber.Packet {
+	if !sess.bound {
+		return []*ber.Packet{newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights, "bind required")}
+	}
+	if len(op.Children) != 8 {
+		return []*ber.Packet{newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, "invalid search request")}
+	}
+
+	baseDN := strings.ToLower(op.Children[0].Data.String())
+	scope, _ := op.Children[1].Value.(int64)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+	var attrNames []string
+	for _, attr := range op.Children[7].Children {
+		attrNames = append(attrNames, attr.Data.String())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var resps []*ber.Packet
+	for _, e := range s.entries {
+		if !inScope(strings.ToLower(e.DN), baseDN, scope) {
+			continue
+		}
+		if ok, err := match(filter, &e.Entry); err != nil {
+			return []*ber.Packet{newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, err.Error())}
+		} else if !ok {
+			continue
+		}
+		if sizeLimit > 0 && int64(len(resps)) == sizeLimit {
+			return append(resps, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSizeLimitExceeded, ""))
+		}
+		resps = append(resps, encodeEntry(e.DN, selectAttributes(&e.Entry, attrNames)))
+	}
+	return append(resps, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""))
+}
+
+func inScope(dn, baseDN string, scope int64) bool {
+	switch scope {
+	case ldap.ScopeBaseObject:
+		return dn == baseDN
+	case ldap.ScopeSingleLevel:
+		i := strings.IndexByte(dn, ',')
+		return i >= 0 && dn[i+1:] == baseDN
+	default:
+		return baseDN == "" || dn == baseDN || strings.HasSuffix(dn, ","+baseDN)
+	}
+}
+
+func selectAttributes(e *ldap.Entry, names []string) []*ldap.EntryAttribute {
+	if len(names) == 0 {
+		return e.Attributes
+	}
+
+	var attrs []*ldap.EntryAttribute
+	for _, name := range names {
+		if values := e.GetEqualFoldAttributeValues(name); len(values) > 0 {
+			attrs = append(attrs, ldap.NewEntryAttribute(name, values))
+		}
+	}
+	return attrs
+}
+
+// newResult Encode a response holding an LDAPResult.
+func newResult(tag ber.Tag, code uint16, message string) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	p.AppendChild(newOctetString(""))
+	p.AppendChild(newOctetString(message))
+	return p
+}
+
+func encodeEntry(dn string, attrs []*ldap.EntryAttribute) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	p.AppendChild(newOctetString(dn))
+	attrsPacket := ber.NewSequence("Attributes")
+	for _, attr := range attrs {
+		values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, v := range attr.Values {
+			values.AppendChild(newOctetString(v))
+		}
+		attrPacket := ber.NewSequence("Attribute")
+		attrPacket.AppendChild(newOctetString(attr.Name))
+		attrPacket.AppendChild(values)
+		attrsPacket.AppendChild(attrPacket)
+	}
+	p.AppendChild(attrsPacket)
+	return p
+}
+
+func newOctetString(s string) *ber.Packet {
+	return ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, s, "")
+}
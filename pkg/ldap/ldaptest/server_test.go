@@ -0,0 +1,123 @@
+package ldaptest_test
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/pkg/ldap/ldaptest"
+)
+
+func TestServer(t *testing.T) {
+	as := assert.New(t)
+
+	for _, useTLS := range []bool{false, true} {
+		server := ldaptest.NewServer()
+		if useTLS {
+			server = ldaptest.NewTLSServer()
+		}
+		tlsConfig := &tls.Config{RootCAs: server.CertPool(), ServerName: "127.0.0.1"}
+
+		server.AddEntry("cn=admin,dc=example,dc=com", "secret", nil)
+		server.AddEntry("uid=alice,ou=people,dc=example,dc=com", "alice-pwd", map[string][]string{
+			"uid": {"alice"}, "cn": {"Alice"}, "mail": {"alice@example.com"},
+		})
+		server.AddEntry("uid=bob,ou=people,dc=example,dc=com", "bob-pwd", map[string][]string{
+			"uid": {"bob"}, "cn": {"Bob"},
+		})
+
+		conn, err := ldap.DialURL(server.URL, ldap.DialWithTLSConfig(tlsConfig))
+		if !as.Nil(err) {
+			server.Close()
+			continue
+		}
+		if !useTLS {
+			as.Nil(conn.StartTLS(tlsConfig))
+		}
+
+		req := ldap.NewSearchRequest("ou=people,dc=example,dc=com", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+			0, 0, false, "(uid=alice)", []string{"cn", "mail"}, nil)
+		_, err = conn.Search(req)
+		as.True(ldap.IsErrorWithCode(err, ldap.LDAPResultInsufficientAccessRights))
+
+		as.True(ldap.IsErrorWithCode(conn.Bind("cn=admin,dc=example,dc=com", "wrong"), ldap.LDAPResultInvalidCredentials))
+		as.Nil(conn.Bind("cn=admin,dc=example,dc=com", "secret"))
+
+		result, err := conn.Search(req)
+		as.Nil(err)
+		if as.Len(result.Entries, 1) {
+			as.Equal("uid=alice,ou=people,dc=example,dc=com", result.Entries[0].DN)
+			as.Equal("Alice", result.Entries[0].GetEqualFoldAttributeValue("CN"))
+			as.Equal("alice@example.com", result.Entries[0].GetAttributeValue("mail"))
+			as.Empty(result.Entries[0].GetAttributeValue("uid"))
+		}
+
+		req.Filter, req.SizeLimit = "(uid=*)", 1
+		result, err = conn.Search(req)
+		as.True(ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded))
+		as.Len(result.Entries, 1)
+
+		req.Scope, req.SizeLimit = ldap.ScopeSingleLevel, 0
+		req.BaseDN = "dc=example,dc=com"
+		result, err = conn.Search(req)
+		as.Nil(err)
+		as.Len(result.Entries, 0)
+
+		as.Nil(conn.Bind("uid=bob,ou=people,dc=example,dc=com", "bob-pwd"))
+		as.Equal(2, server.Binds())
+		as.Nil(conn.Close())
+		server.Close()
+	}
+
+	// The certificate of the server must be trusted
+	server := ldaptest.NewTLSServer()
+	defer server.Close()
+	_, err := ldap.DialURL(server.URL)
+	as.NotNil(err)
+}
+
+func TestFilter(t *testing.T) {
+	as := assert.New(t)
+
+	server := ldaptest.NewServer()
+	defer server.Close()
+	server.AddEntry("cn=admin,dc=example,dc=com", "secret", nil)
+	server.AddEntry("uid=alice,ou=people,dc=example,dc=com", "", map[string][]string{
+		"uid":         {"alice"},
+		"cn":          {"Alice (Admin)"},
+		"mail":        {"Alice@Example.com"},
+		"objectClass": {"top", "inetOrgPerson"},
+	})
+
+	conn, err := ldap.DialURL(server.URL)
+	if !as.Nil(err) {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+	as.Nil(conn.Bind("cn=admin,dc=example,dc=com", "secret"))
+
+	for filter, match := range map[string]bool{
+		"(uid=alice)": true,
+		"(uid=ALICE)": true,
+		"(uid=bob)":   false,
+		"(&(objectClass=inetOrgPerson)(uid=alice))":       true,
+		"(&(objectClass=inetOrgPerson)(uid=bob))":         false,
+		"(|(uid=bob)(mail=alice@example.com))":            true,
+		"(!(uid=alice))":                                  false,
+		"(mail=*)":                                        true,
+		"(telephoneNumber=*)":                             false,
+		"(mail=ali*@*.com)":                               true,
+		"(mail=*bob*)":                                    false,
+		"(cn=" + ldap.EscapeFilter("Alice (Admin)") + ")": true,
+		"(uid>=a)":                                        true,
+		"(uid<=a)":                                        false,
+	} {
+		result, err := conn.Search(ldap.NewSearchRequest("ou=people,dc=example,dc=com", ldap.ScopeWholeSubtree,
+			ldap.NeverDerefAliases, 0, 0, false, filter, nil, nil))
+		if as.Nil(err, filter) {
+			as.Equal(match, len(result.Entries) == 1, filter)
+		}
+	}
+}
@@ -10,14 +10,19 @@ import (
 
 var ErrCasbinDenied = errors.Forbidden("com.casbin.denied", "Permission denied")
 
+const casbinDenyEffect = "deny"
+
 type CasbinConfig struct {
 	AllowedPathPrefixes []string
 	SkippedPathPrefixes []string
 	Skipper             func(c *gin.Context) bool
-	GetEnforcer         func(c *gin.Context) *casbin.Enforcer
+	GetEnforcer         func(c *gin.Context) *casbin.SyncedEnforcer
 	GetSubjects         func(c *gin.Context) []string
+	GetDomain           func(c *gin.Context) string
 }
 
+// CasbinWithConfig The request passes if one of the subjects is allowed and none of them is explicitly denied, that is
+// matched by a policy whose effect (the last field) is deny.
 func CasbinWithConfig(config CasbinConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if !AllowedPathPrefixes(c, config.AllowedPathPrefixes...) ||
@@ -33,15 +38,24 @@ func CasbinWithConfig(config CasbinConfig) gin.HandlerFunc {
 			return
 		}
 
+		dom := config.GetDomain(c)
+		allowed := false
 		for _, sub := range config.GetSubjects(c) {
-			if b, err := enforcer.Enforce(sub, c.Request.URL.Path, c.Request.Method); err != nil {
+			b, explain, err := enforcer.EnforceEx(sub, dom, c.Request.URL.Path, c.Request.Method)
+			if err != nil {
 				util.ResError(c, err)
 				return
 			} else if b {
-				c.Next()
+				allowed = true
+			} else if len(explain) > 0 && explain[len(explain)-1] == casbinDenyEffect {
+				util.ResError(c, ErrCasbinDenied)
 				return
 			}
 		}
-		util.ResError(c, ErrCasbinDenied)
+		if !allowed {
+			util.ResError(c, ErrCasbinDenied)
+			return
+		}
+		c.Next()
 	}
 }
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+type TenantConfig struct {
+	Enable              bool
+	AllowedPathPrefixes []string
+	SkippedPathPrefixes []string
+	Skipper             func(c *gin.Context) bool
+	HeaderKey           string // Request header carrying the tenant code
+	Domain              string // Base domain, the tenant code is the subdomain of the request host
+	GetTenantID         func(c *gin.Context, code string) (int64, error)
+}
+
+// TenantWithConfig Scope the request to the tenant given by the header or the subdomain, the requests without a tenant
+// are not scoped until the authenticated user is known.
+func TenantWithConfig(config TenantConfig) gin.HandlerFunc {
+	if !config.Enable {
+		return Empty()
+	}
+
+	return func(c *gin.Context) {
+		if !AllowedPathPrefixes(c, config.AllowedPathPrefixes...) ||
+			SkippedPathPrefixes(c, config.SkippedPathPrefixes...) ||
+			(config.Skipper != nil && config.Skipper(c)) {
+			c.Next()
+			return
+		}
+
+		code := c.GetHeader(config.HeaderKey)
+		if code == "" && config.Domain != "" {
+			host := c.Request.Host
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+			if suffix := "." + config.Domain; strings.HasSuffix(host, suffix) {
+				code = strings.TrimSuffix(host, suffix)
+			}
+		}
+		if code == "" {
+			c.Next()
+			return
+		}
+
+		tenantID, err := config.GetTenantID(c, code)
+		if err != nil {
+			util.ResError(c, err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(util.NewTenantID(c.Request.Context(), tenantID))
+		c.Next()
+	}
+}
@@ -0,0 +1,73 @@
+package oidc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Claims Claims of the ID token or the userinfo response
+type Claims map[string]interface{}
+
+// Value Get the value of the claim, nested claims are separated by dots (e.g. "realm_access.roles").
+func (c Claims) Value(path string) interface{} {
+	if v, ok := c[path]; ok {
+		return v
+	}
+
+	var cur interface{} = map[string]interface{}(c)
+	for _, name := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if cur, ok = m[name]; !ok {
+			return nil
+		}
+	}
+	return cur
+}
+
+// String Get the claim as a string, numbers and booleans are formatted.
+func (c Claims) String(path string) string {
+	switch v := c.Value(path).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64, bool:
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// Strings Get the claim as a list of strings, a single string is returned as a list of one item.
+func (c Claims) Strings(path string) []string {
+	switch v := c.Value(path).(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	case []string:
+		return v
+	}
+	return nil
+}
+
+// Merge Add the claims which are not present yet.
+func (c Claims) Merge(other Claims) Claims {
+	for k, v := range other {
+		if _, ok := c[k]; !ok {
+			c[k] = v
+		}
+	}
+	return c
+}
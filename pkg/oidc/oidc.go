@@ -0,0 +1,140 @@
+// Package oidc implements the relying party of the OpenID Connect authorization code flow with PKCE on top of
+// go-oidc (discovery, key sets and ID token verification) and oauth2 (authorization and token endpoints).
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+var ErrInvalidIDToken = errors.New("invalid id token")
+
+// signingAlgs Asymmetric algorithms accepted for ID tokens, symmetric ones and "none" are rejected.
+var signingAlgs = []string{
+	gooidc.RS256, gooidc.RS384, gooidc.RS512, gooidc.PS256, gooidc.PS384, gooidc.PS512,
+	gooidc.ES256, gooidc.ES384, gooidc.ES512, gooidc.EdDSA,
+}
+
+// Config Client registration at the provider
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string // Default openid, profile and email
+	HTTPClient   *http.Client
+}
+
+// Token Tokens issued by the token endpoint
+type Token struct {
+	*oauth2.Token
+	IDToken string
+}
+
+// Provider An OpenID provider discovered from its issuer
+type Provider struct {
+	cfg      Config
+	provider *gooidc.Provider
+	oauth2   *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+// NewProvider Fetch the discovery document of the issuer.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{gooidc.ScopeOpenID, "profile", "email"}
+	}
+
+	provider, err := gooidc.NewProvider(cfg.context(ctx), cfg.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	// The client authenticates with HTTP basic auth, the code can only be used once so auto detection is not an option
+	endpoint := provider.Endpoint()
+	endpoint.AuthStyle = oauth2.AuthStyleInHeader
+	return &Provider{
+		cfg:      cfg,
+		provider: provider,
+		oauth2: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     endpoint,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       cfg.Scopes,
+		},
+		verifier: provider.Verifier(&gooidc.Config{ClientID: cfg.ClientID, SupportedSigningAlgs: signingAlgs}),
+	}, nil
+}
+
+// context Pass the HTTP client of the configuration to the requests of go-oidc and oauth2.
+func (c Config) context(ctx context.Context) context.Context {
+	if c.HTTPClient == nil {
+		return ctx
+	}
+	return gooidc.ClientContext(ctx, c.HTTPClient)
+}
+
+// Endpoint Get the authorization and token endpoints of the provider.
+func (p *Provider) Endpoint() oauth2.Endpoint {
+	return p.oauth2.Endpoint
+}
+
+// AuthCodeURL Build the URL of the authorization endpoint to redirect the user to, the code challenge is derived
+// from the verifier (S256).
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	return p.oauth2.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange Exchange the authorization code for the tokens, errors of the provider are *oauth2.RetrieveError.
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (*Token, error) {
+	token, err := p.oauth2.Exchange(p.cfg.context(ctx), code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, _ := token.Extra("id_token").(string)
+	if idToken == "" {
+		return nil, ErrInvalidIDToken
+	}
+	return &Token{Token: token, IDToken: idToken}, nil
+}
+
+// UserInfo Get the claims of the user from the userinfo endpoint.
+func (p *Provider) UserInfo(ctx context.Context, accessToken string) (Claims, error) {
+	if p.provider.UserInfoEndpoint() == "" {
+		return Claims{}, nil
+	}
+
+	userInfo, err := p.provider.UserInfo(p.cfg.context(ctx), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken}))
+	if err != nil {
+		return nil, err
+	}
+	claims := make(Claims)
+	if err := userInfo.Claims(&claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// VerifyIDToken Verify the signature, issuer, audience, expiry and nonce of the ID token and return its claims.
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (Claims, error) {
+	idToken, err := p.verifier.Verify(p.cfg.context(ctx), rawIDToken)
+	if err != nil || idToken.Subject == "" || idToken.Nonce != nonce {
+		return nil, ErrInvalidIDToken
+	}
+
+	claims := make(Claims)
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, ErrInvalidIDToken
+	}
+	// go-oidc does not check the authorized party of tokens issued to several audiences
+	if len(idToken.Audience) > 1 && claims.String("azp") != p.cfg.ClientID {
+		return nil, ErrInvalidIDToken
+	}
+	return claims, nil
+}
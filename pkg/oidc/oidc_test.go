@@ -0,0 +1,140 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+
+	"github.com/supermicah/go-framework-admin/pkg/oidc"
+	"github.com/supermicah/go-framework-admin/pkg/oidc/oidctest"
+)
+
+// authorize Follow the authorization URL and return the parameters of the redirect back to the client
+func authorize(t *testing.T, authURL string) url.Values {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	assert.Nil(t, err)
+	return location.Query()
+}
+
+func TestProvider(t *testing.T) {
+	server := oidctest.NewServer("client", "secret")
+	defer server.Close()
+	server.SetUser(map[string]interface{}{
+		"sub":                "1001",
+		"preferred_username": "alice",
+		"groups":             []string{"admins", "dev"},
+		"realm_access":       map[string]interface{}{"roles": []string{"viewer"}},
+	})
+
+	ctx := context.Background()
+	_, err := oidc.NewProvider(ctx, oidc.Config{Issuer: server.URL + "/other"})
+	assert.NotNil(t, err)
+
+	provider, err := oidc.NewProvider(ctx, oidc.Config{
+		Issuer:       server.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/callback",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, server.URL+"/token", provider.Endpoint().TokenURL)
+
+	verifier := oauth2.GenerateVerifier()
+	params := authorize(t, provider.AuthCodeURL("state", "nonce", verifier))
+	assert.Equal(t, "state", params.Get("state"))
+
+	// The code requires the verifier and can only be used once
+	_, err = provider.Exchange(ctx, params.Get("code"), "wrong")
+	if retrieveErr, ok := err.(*oauth2.RetrieveError); assert.True(t, ok) {
+		assert.Equal(t, "invalid_grant", retrieveErr.ErrorCode)
+	}
+
+	params = authorize(t, provider.AuthCodeURL("state", "nonce", verifier))
+	token, err := provider.Exchange(ctx, params.Get("code"), verifier)
+	assert.Nil(t, err)
+	_, err = provider.Exchange(ctx, params.Get("code"), verifier)
+	assert.NotNil(t, err)
+
+	claims, err := provider.VerifyIDToken(ctx, token.IDToken, "nonce")
+	assert.Nil(t, err)
+	assert.Equal(t, "1001", claims.String("sub"))
+	assert.Equal(t, "alice", claims.String("preferred_username"))
+	assert.Equal(t, []string{"admins", "dev"}, claims.Strings("groups"))
+	assert.Equal(t, []string{"viewer"}, claims.Strings("realm_access.roles"))
+
+	_, err = provider.VerifyIDToken(ctx, token.IDToken, "other")
+	assert.Equal(t, oidc.ErrInvalidIDToken, err)
+	_, err = provider.VerifyIDToken(ctx, token.IDToken+"x", "nonce")
+	assert.Equal(t, oidc.ErrInvalidIDToken, err)
+
+	userInfo, err := provider.UserInfo(ctx, token.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", userInfo.String("preferred_username"))
+	_, err = provider.UserInfo(ctx, "invalid")
+	assert.NotNil(t, err)
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": server.URL,
+			"aud": "client",
+			"sub": "1001",
+			"exp": time.Now().Add(time.Minute).Unix(),
+		}
+	}
+	_, err = provider.VerifyIDToken(ctx, server.SignIDToken(valid()), "")
+	assert.Nil(t, err)
+
+	for _, modify := range []func(jwt.MapClaims){
+		func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		func(c jwt.MapClaims) { c["aud"] = "other" },
+		func(c jwt.MapClaims) { c["aud"] = []string{"client", "other"} },
+		func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		func(c jwt.MapClaims) { delete(c, "exp") },
+		func(c jwt.MapClaims) { delete(c, "sub") },
+	} {
+		claims := valid()
+		modify(claims)
+		_, err = provider.VerifyIDToken(ctx, server.SignIDToken(claims), "")
+		assert.Equal(t, oidc.ErrInvalidIDToken, err)
+	}
+
+	// Symmetric signatures are rejected
+	hs, err := jwt.NewWithClaims(jwt.SigningMethodHS256, valid()).SignedString([]byte("secret"))
+	assert.Nil(t, err)
+	_, err = provider.VerifyIDToken(ctx, hs, "")
+	assert.Equal(t, oidc.ErrInvalidIDToken, err)
+}
+
+func TestClaims(t *testing.T) {
+	claims := oidc.Claims{
+		"name":     "Alice",
+		"verified": true,
+		"level":    float64(3),
+		"a.b":      "dotted",
+		"a":        map[string]interface{}{"b": "nested", "c": []interface{}{"x", 1, "y"}},
+	}
+	assert.Equal(t, "Alice", claims.String("name"))
+	assert.Equal(t, "true", claims.String("verified"))
+	assert.Equal(t, "3", claims.String("level"))
+	assert.Equal(t, "dotted", claims.String("a.b"))
+	assert.Equal(t, []string{"x", "y"}, claims.Strings("a.c"))
+	assert.Equal(t, []string{"Alice"}, claims.Strings("name"))
+	assert.Nil(t, claims.Strings("missing"))
+	assert.Equal(t, "", claims.String("a.missing.b"))
+
+	claims.Merge(oidc.Claims{"name": "Bob", "email": "bob@example.com"})
+	assert.Equal(t, "Alice", claims.String("name"))
+	assert.Equal(t, "bob@example.com", claims.String("email"))
+}
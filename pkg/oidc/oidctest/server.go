@@ -0,0 +1,211 @@
+// Package oidctest provides a mock OpenID provider for tests, the authorization endpoint signs in the configured
+// user without interaction.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"golang.org/x/oauth2"
+
+	"github.com/supermicah/go-framework-admin/pkg/jwtx"
+)
+
+const keyID = "oidctest"
+
+type authRequest struct {
+	RedirectURI   string
+	Nonce         string
+	CodeChallenge string
+	Claims        map[string]interface{}
+}
+
+// Server A mock OpenID provider, the URL of the server is the issuer.
+type Server struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	key          *rsa.PrivateKey
+	mu           sync.Mutex
+	user         map[string]interface{}
+	codes        map[string]*authRequest
+	accessTokens map[string]map[string]interface{}
+}
+
+// NewServer Start a mock provider with a registered client.
+func NewServer(clientID, clientSecret string) *Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		key:          key,
+		codes:        make(map[string]*authRequest),
+		accessTokens: make(map[string]map[string]interface{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/jwks", s.jwks)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/userinfo", s.userinfo)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// SetUser Set the claims of the user signed in by the next authorization requests (sub is required).
+func (s *Server) SetUser(claims map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.user = claims
+}
+
+// SignIDToken Sign the claims with the key of the provider.
+func (s *Server) SignIDToken(claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 s.URL,
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"userinfo_endpoint":      s.URL + "/userinfo",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	key := &jwtx.Key{ID: keyID, Method: jwt.SigningMethodRS256, VerifyKey: &s.key.PublicKey}
+	jwk, _ := key.JWK()
+	writeJSON(w, http.StatusOK, &jwtx.JSONWebKeySet{Keys: []*jwtx.JSONWebKey{jwk}})
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != s.ClientID || q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	redirectURI, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	s.mu.Lock()
+	user := s.user
+	s.mu.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("state", q.Get("state"))
+	if user == nil {
+		params.Set("error", "access_denied")
+	} else {
+		code := oauth2.GenerateVerifier()
+		s.mu.Lock()
+		s.codes[code] = &authRequest{
+			RedirectURI:   q.Get("redirect_uri"),
+			Nonce:         q.Get("nonce"),
+			CodeChallenge: q.Get("code_challenge"),
+			Claims:        user,
+		}
+		s.mu.Unlock()
+		params.Set("code", code)
+	}
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != s.ClientID || clientSecret != s.ClientSecret {
+		writeError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	} else if r.PostFormValue("grant_type") != "authorization_code" {
+		writeError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	s.mu.Lock()
+	code := r.PostFormValue("code")
+	req, ok := s.codes[code]
+	delete(s.codes, code)
+	s.mu.Unlock()
+	if !ok || req.RedirectURI != r.PostFormValue("redirect_uri") ||
+		oauth2.S256ChallengeFromVerifier(r.PostFormValue("code_verifier")) != req.CodeChallenge {
+		writeError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   s.URL,
+		"aud":   s.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": req.Nonce,
+	}
+	for k, v := range req.Claims {
+		claims[k] = v
+	}
+
+	accessToken := oauth2.GenerateVerifier()
+	s.mu.Lock()
+	s.accessTokens[accessToken] = req.Claims
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     s.SignIDToken(claims),
+	})
+}
+
+func (s *Server) userinfo(w http.ResponseWriter, r *http.Request) {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if len(auth) <= len(prefix) {
+		writeError(w, http.StatusUnauthorized, "invalid_token")
+		return
+	}
+
+	s.mu.Lock()
+	claims, ok := s.accessTokens[auth[len(prefix):]]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token")
+		return
+	}
+	writeJSON(w, http.StatusOK, claims)
+}
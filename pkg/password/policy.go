@@ -0,0 +1,111 @@
+// Package password checks passwords against a configurable complexity policy.
+package password
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Violation codes of the policy
+const (
+	CodeTooShort      = "too_short"
+	CodeMissingUpper  = "missing_upper"
+	CodeMissingLower  = "missing_lower"
+	CodeMissingDigit  = "missing_digit"
+	CodeMissingSymbol = "missing_symbol"
+	CodeCommon        = "common"
+)
+
+//go:embed common.txt
+var commonList string
+
+var common = parseList(strings.Split(commonList, "\n"))
+
+func parseList(items []string) map[string]struct{} {
+	m := make(map[string]struct{}, len(items))
+	for _, v := range items {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			m[v] = struct{}{}
+		}
+	}
+	return m
+}
+
+// IsCommon Reports whether the password is in the built-in list of common passwords (case-insensitive).
+func IsCommon(password string) bool {
+	_, ok := common[strings.ToLower(password)]
+	return ok
+}
+
+// Violation A rule of the policy which the password breaks
+type Violation struct {
+	Code    string
+	Message string
+}
+
+// Policy Complexity requirements of passwords, the zero value accepts everything
+type Policy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	DenyCommon    bool     // reject the built-in list of common passwords
+	DenyList      []string // additional passwords to reject (case-insensitive)
+}
+
+// Validate Check the plaintext password and return all the rules it breaks.
+func (p Policy) Validate(password string) []Violation {
+	var violations []Violation
+	if n := len([]rune(password)); n < p.MinLength {
+		violations = append(violations, Violation{
+			Code:    CodeTooShort,
+			Message: fmt.Sprintf("Password must be at least %d characters", p.MinLength),
+		})
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		violations = append(violations, Violation{Code: CodeMissingUpper, Message: "Password must contain an uppercase letter"})
+	}
+	if p.RequireLower && !lower {
+		violations = append(violations, Violation{Code: CodeMissingLower, Message: "Password must contain a lowercase letter"})
+	}
+	if p.RequireDigit && !digit {
+		violations = append(violations, Violation{Code: CodeMissingDigit, Message: "Password must contain a digit"})
+	}
+	if p.RequireSymbol && !symbol {
+		violations = append(violations, Violation{Code: CodeMissingSymbol, Message: "Password must contain a symbol"})
+	}
+
+	if p.isDenied(password) {
+		violations = append(violations, Violation{Code: CodeCommon, Message: "Password is too common"})
+	}
+	return violations
+}
+
+func (p Policy) isDenied(password string) bool {
+	if p.DenyCommon && IsCommon(password) {
+		return true
+	}
+	for _, v := range p.DenyList {
+		if strings.EqualFold(strings.TrimSpace(v), password) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func codes(violations []Violation) []string {
+	result := make([]string, len(violations))
+	for i, v := range violations {
+		result[i] = v.Code
+	}
+	return result
+}
+
+func TestPolicyValidate(t *testing.T) {
+	assert.Empty(t, Policy{}.Validate(""))
+	assert.Empty(t, Policy{}.Validate("123456"))
+
+	p := Policy{
+		MinLength:     8,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		DenyCommon:    true,
+		DenyList:      []string{"Company2024!"},
+	}
+	assert.Empty(t, p.Validate("Correct-Horse-9"))
+	assert.Empty(t, p.Validate("Пароль-Надёжный-9"))
+	assert.Equal(t, []string{CodeTooShort, CodeMissingUpper, CodeMissingSymbol}, codes(p.Validate("xyz987")))
+	assert.Equal(t, []string{CodeMissingLower, CodeMissingDigit}, codes(p.Validate("ABCDEFGH!")))
+	assert.Equal(t, []string{CodeCommon}, codes(p.Validate("P@ssw0rd")))
+	assert.Equal(t, []string{CodeMissingUpper, CodeCommon}, codes(p.Validate("company2024!")))
+}
+
+func TestIsCommon(t *testing.T) {
+	assert.True(t, IsCommon("password"))
+	assert.True(t, IsCommon("PASSWORD"))
+	assert.False(t, IsCommon(""))
+	assert.False(t, IsCommon("Correct-Horse-9"))
+}
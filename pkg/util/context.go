@@ -17,6 +17,12 @@ type (
 	userTokenCtx  struct{}
 	isRootUserCtx struct{}
 	userCacheCtx  struct{}
+	sessionIDCtx  struct{}
+	clientIPCtx   struct{}
+	userAgentCtx  struct{}
+	languageCtx   struct{}
+	tenantIDCtx   struct{}
+	dataScopeCtx  struct{}
 )
 
 func NewTraceID(ctx context.Context, traceID string) context.Context {
@@ -85,9 +91,100 @@ func FromIsRootUser(ctx context.Context) bool {
 	return v != nil && v.(bool)
 }
 
+func NewSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDCtx{}, sessionID)
+}
+
+func FromSessionID(ctx context.Context) string {
+	v := ctx.Value(sessionIDCtx{})
+	if v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
+func NewClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPCtx{}, clientIP)
+}
+
+func FromClientIP(ctx context.Context) string {
+	v := ctx.Value(clientIPCtx{})
+	if v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
+func NewUserAgent(ctx context.Context, userAgent string) context.Context {
+	return context.WithValue(ctx, userAgentCtx{}, userAgent)
+}
+
+func FromUserAgent(ctx context.Context) string {
+	v := ctx.Value(userAgentCtx{})
+	if v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
+// NewAcceptLanguage Keep the Accept-Language header of the request to negotiate the locale, see ParseAcceptLanguage.
+func NewAcceptLanguage(ctx context.Context, acceptLanguage string) context.Context {
+	return context.WithValue(ctx, languageCtx{}, acceptLanguage)
+}
+
+func FromAcceptLanguage(ctx context.Context) string {
+	v := ctx.Value(languageCtx{})
+	if v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
+// NewTenantID Scope the database access to the tenant, see GetDB.
+func NewTenantID(ctx context.Context, tenantID int64) context.Context {
+	return context.WithValue(ctx, tenantIDCtx{}, tenantID)
+}
+
+// FromTenantID Get the tenant of the context, false if the context is not scoped to a tenant.
+func FromTenantID(ctx context.Context) (int64, bool) {
+	v := ctx.Value(tenantIDCtx{})
+	if v != nil {
+		return v.(int64), true
+	}
+	return 0, false
+}
+
+// NewCrossTenant Lift the tenant scope of the context, for the data shared by the tenants.
+func NewCrossTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantIDCtx{}, nil)
+}
+
+// DataScope Rows visible to the user of the context, the rows owned by the user or by the users of the departments.
+type DataScope struct {
+	UserID  int64   // Owner of the visible rows (zero if the rows of the user are not visible)
+	DeptIDs []int64 // Departments of the owners of the visible rows
+}
+
+// NewDataScope Restrict the rows visible to the user of the context, all the rows are visible without a data scope.
+func NewDataScope(ctx context.Context, dataScope *DataScope) context.Context {
+	return context.WithValue(ctx, dataScopeCtx{}, dataScope)
+}
+
+func FromDataScope(ctx context.Context) (*DataScope, bool) {
+	v := ctx.Value(dataScopeCtx{})
+	if v != nil {
+		return v.(*DataScope), true
+	}
+	return nil, false
+}
+
 // UserCache Set user cache object
 type UserCache struct {
-	RoleIDs []int64 `json:"rids"`
+	RoleIDs         []int64 `json:"rids"`
+	TokenVersion    int64   `json:"tv"`
+	PasswordExpired bool    `json:"pe,omitempty"`
+	DeptID          int64   `json:"did,omitempty"`
+	Locale          string  `json:"loc,omitempty"` // Preferred locale of the user
 }
 
 func (a UserCache) ToRoleIDsStr() []string {
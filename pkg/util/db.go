@@ -3,11 +3,15 @@ package util
 import (
 	"context"
 	"errors"
+	"reflect"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// TenantField The models with this field are scoped by the tenant of the context
+const TenantField = "TenantID"
+
 type Trans struct {
 	DB *gorm.DB
 }
@@ -32,9 +36,48 @@ func GetDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
 	if FromRowLock(ctx) {
 		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
 	}
+	if tenantID, ok := FromTenantID(ctx); ok {
+		db = db.Scopes(TenantScope(tenantID))
+	}
 	return db.WithContext(ctx)
 }
 
+// TenantScope Filter the rows of the models with a tenant field by the tenant, the tenant is also filled in the new
+// rows without one. The models without the field are not affected.
+func TenantScope(tenantID int64) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		stmt := db.Statement
+		model := stmt.Model
+		if model == nil {
+			model = stmt.Dest
+		}
+		if model == nil {
+			return db
+		} else if stmt.Schema == nil {
+			if err := stmt.Parse(model); err != nil {
+				return db
+			}
+		}
+
+		field := stmt.Schema.LookUpField(TenantField)
+		if field == nil {
+			return db
+		}
+
+		if dest := reflect.Indirect(reflect.ValueOf(stmt.Dest)); dest.Kind() == reflect.Struct &&
+			dest.Type() == stmt.Schema.ModelType && dest.CanAddr() {
+			if _, zero := field.ValueOf(stmt.Context, dest); zero {
+				_ = field.Set(stmt.Context, dest, tenantID)
+			}
+		}
+
+		return db.Where(clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName},
+			Value:  tenantID,
+		})
+	}
+}
+
 func wrapQueryOptions(db *gorm.DB, opts QueryOptions) *gorm.DB {
 	if len(opts.SelectFields) > 0 {
 		db = db.Select(opts.SelectFields)
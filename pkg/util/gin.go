@@ -16,8 +16,20 @@ import (
 	"github.com/supermicah/go-framework-admin/pkg/logging"
 )
 
-// GetToken Get access token from header or query parameter
+// APIKeyPrefix The prefix that distinguishes API keys from access tokens
+const APIKeyPrefix = "ak_"
+
+// IsAPIKey Reports whether the token is an API key
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// GetToken Get access token or API key from header or query parameter
 func GetToken(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+
 	var token string
 	auth := c.GetHeader("Authorization")
 	prefix := "Bearer "
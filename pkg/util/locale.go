@@ -0,0 +1,77 @@
+package util
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseAcceptLanguage The language tags of an Accept-Language header (e.g. "en-US,en;q=0.9,zh;q=0.8") ordered by
+// their quality, the wildcard and the tags with a zero quality are dropped.
+func ParseAcceptLanguage(header string) []string {
+	type tag struct {
+		name    string
+		quality float64
+	}
+
+	var tags []tag
+	for _, item := range strings.Split(header, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ";")
+		name := strings.TrimSpace(parts[0])
+		if name == "" || name == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range parts[1:] {
+			if param = strings.TrimSpace(param); strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			tags = append(tags, tag{name: name, quality: quality})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	names := make([]string, len(tags))
+	for i, item := range tags {
+		names[i] = item.name
+	}
+	return names
+}
+
+// MatchLocale The first supported locale matching one of the preferred language tags, by the whole tag first and by
+// the primary language (e.g. "en" of "en-GB") then, the case and the separator (- or _) are ignored. Empty if none
+// of them is supported.
+func MatchLocale(preferred, supported []string) string {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.ReplaceAll(s, "_", "-"))
+	}
+	primary := func(s string) string {
+		lang, _, _ := strings.Cut(s, "-")
+		return lang
+	}
+
+	for _, item := range preferred {
+		item = normalize(item)
+		if item == "" {
+			continue
+		}
+		for _, locale := range supported {
+			if normalize(locale) == item {
+				return locale
+			}
+		}
+		for _, locale := range supported {
+			if primary(normalize(locale)) == primary(item) {
+				return locale
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tags := ParseAcceptLanguage("zh;q=0.8, en-US, fr;q=0, *;q=0.5, en;q=0.9")
+	if expected := []string{"en-US", "en", "zh"}; !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected %v, got %v", expected, tags)
+	}
+	if tags := ParseAcceptLanguage(""); len(tags) != 0 {
+		t.Errorf("expected no tags, got %v", tags)
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	supported := []string{"zh-CN", "en-US"}
+	for _, item := range []struct {
+		preferred []string
+		expected  string
+	}{
+		{[]string{"en_us"}, "en-US"},
+		{[]string{"en-GB"}, "en-US"},
+		{[]string{"fr", "zh"}, "zh-CN"},
+		{[]string{"fr"}, ""},
+		{nil, ""},
+	} {
+		if locale := MatchLocale(item.preferred, supported); locale != item.expected {
+			t.Errorf("preferred %v: expected %q, got %q", item.preferred, item.expected, locale)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestAPIKey(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	var role, otherRole schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{Code: "apikey", Name: "API key", Status: schema.RoleStatusEnabled}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{Code: "apikey2", Name: "API key 2", Status: schema.RoleStatusEnabled}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &otherRole})
+
+	password := hash.MD5String("apikey")
+	var user schema.User
+	e.POST(baseAPI + "/users").WithJSON(schema.UserForm{
+		Username: "apikey",
+		Name:     "API key",
+		Password: password,
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{{RoleID: role.ID}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+	as.NotEmpty(user.ID)
+
+	var token schema.LoginToken
+	login(e, "apikey", password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	auth := "Bearer " + token.AccessToken
+
+	// Roles must be owned by the user
+	e.POST(baseAPI+"/current/api-keys").WithHeader("Authorization", auth).
+		WithJSON(schema.APIKeyForm{Name: "ci", RoleIDs: []int64{otherRole.ID}}).
+		Expect().Status(http.StatusBadRequest)
+
+	var apiKey schema.APIKey
+	e.POST(baseAPI+"/current/api-keys").WithHeader("Authorization", auth).
+		WithJSON(schema.APIKeyForm{Name: "ci", RoleIDs: []int64{role.ID}}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &apiKey})
+	as.NotEmpty(apiKey.ID)
+	as.True(util.IsAPIKey(apiKey.Key))
+	as.Equal(apiKey.Prefix, apiKey.Key[:len(apiKey.Prefix)])
+	as.Equal([]int64{role.ID}, apiKey.RoleIDs)
+
+	// The key is never returned again
+	var apiKeys schema.APIKeys
+	e.GET(baseAPI+"/current/api-keys").WithHeader("Authorization", auth).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &apiKeys})
+	as.Len(apiKeys, 1)
+	as.Empty(apiKeys[0].Key)
+	as.Nil(apiKeys[0].LastUsedAt)
+
+	// Authenticate as the owner with the key
+	var current schema.User
+	e.GET(baseAPI+"/current/user").WithHeader("X-API-Key", apiKey.Key).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &current})
+	as.Equal(user.ID, current.ID)
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", "Bearer "+apiKey.Key).Expect().Status(http.StatusOK)
+	e.GET(baseAPI+"/current/user").WithHeader("X-API-Key", apiKey.Key+"x").Expect().Status(http.StatusUnauthorized)
+
+	// A key cannot manage the current user, e.g. mint a key with more roles than its own
+	e.POST(baseAPI+"/current/api-keys").WithHeader("X-API-Key", apiKey.Key).
+		WithJSON(schema.APIKeyForm{Name: "escalated", RoleIDs: []int64{role.ID}}).
+		Expect().Status(http.StatusForbidden)
+	e.GET(baseAPI+"/current/api-keys").WithHeader("X-API-Key", apiKey.Key).Expect().Status(http.StatusForbidden)
+	e.GET(baseAPI+"/current/sessions").WithHeader("X-API-Key", apiKey.Key).Expect().Status(http.StatusForbidden)
+	e.POST(baseAPI+"/current/2fa/setup").WithHeader("X-API-Key", apiKey.Key).Expect().Status(http.StatusForbidden)
+	e.PUT(baseAPI+"/current/password").WithHeader("X-API-Key", apiKey.Key).
+		WithJSON(schema.UpdateLoginPassword{OldPassword: password, NewPassword: hash.MD5String("apikey2")}).
+		Expect().Status(http.StatusForbidden)
+	e.PUT(baseAPI+"/current/user").WithHeader("X-API-Key", apiKey.Key).
+		WithJSON(schema.UpdateCurrentUser{Name: "escalated"}).Expect().Status(http.StatusForbidden)
+
+	var getAPIKey schema.APIKey
+	e.GET(fmt.Sprintf("%s/current/api-keys/%d", baseAPI, apiKey.ID)).WithHeader("Authorization", auth).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &getAPIKey})
+	as.NotNil(getAPIKey.LastUsedAt)
+
+	e.PUT(fmt.Sprintf("%s/current/api-keys/%d", baseAPI, apiKey.ID)).WithHeader("Authorization", auth).
+		WithJSON(schema.APIKeyForm{Name: "ci 2"}).Expect().Status(http.StatusOK)
+
+	e.DELETE(fmt.Sprintf("%s/current/api-keys/%d", baseAPI, apiKey.ID)).WithHeader("Authorization", auth).
+		Expect().Status(http.StatusOK)
+	e.GET(baseAPI+"/current/user").WithHeader("X-API-Key", apiKey.Key).Expect().Status(http.StatusUnauthorized)
+
+	e.DELETE(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, otherRole.ID)).Expect().Status(http.StatusOK)
+}
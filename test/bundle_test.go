@@ -0,0 +1,184 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestBundle(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	var menu schema.Menu
+	e.POST(baseAPI + "/menus").WithJSON(schema.MenuForm{
+		Code:      "bundle",
+		Name:      "Bundle",
+		Type:      "page",
+		Status:    schema.MenuStatusEnabled,
+		Resources: schema.MenuResources{{Method: "GET", Path: "/api/v1/bundle-items"}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menu})
+	var button schema.Menu
+	e.POST(baseAPI + "/menus").WithJSON(schema.MenuForm{
+		Code:      "delete",
+		Name:      "Bundle delete",
+		Type:      "button",
+		Status:    schema.MenuStatusEnabled,
+		ParentID:  menu.ID,
+		Resources: schema.MenuResources{{Method: "DELETE", Path: "/api/v1/bundle-items/:id"}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &button})
+
+	var parent schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{
+		Code:   "bundle-parent",
+		Name:   "Bundle parent",
+		Status: schema.RoleStatusEnabled,
+		Menus:  schema.RoleMenus{{MenuID: button.ID}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &parent})
+	var role schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{
+		Code:      "bundle-child",
+		Name:      "Bundle child",
+		Status:    schema.RoleStatusEnabled,
+		ParentIDs: []int64{parent.ID},
+		Resources: schema.RoleResources{
+			{Method: "DELETE", Path: "/api/v1/bundle-items/:id", Effect: schema.ResourceEffectDeny},
+		},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+
+	var user schema.User
+	e.POST(baseAPI + "/users").WithJSON(schema.UserForm{
+		Username: "bundle",
+		Name:     "Bundle",
+		Password: "test123456",
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{{RoleID: role.ID}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+
+	data := e.GET(baseAPI+"/bundles/export").WithQuery("users", true).WithQuery("format", "yaml").
+		Expect().Status(http.StatusOK).Body().Raw()
+	bundle, err := schema.ParseBundle([]byte(data), schema.BundleFormatYAML)
+	if !as.Nil(err) {
+		return
+	}
+	as.Equal(schema.BundleVersion, bundle.Version)
+
+	// Only the items of the test are imported again
+	exported := &schema.Bundle{Version: bundle.Version}
+	for _, item := range bundle.Menus {
+		if item.Code == "bundle" {
+			exported.Menus = append(exported.Menus, item)
+		}
+	}
+	for _, item := range bundle.Roles {
+		if item.Code == "bundle-parent" || item.Code == "bundle-child" {
+			exported.Roles = append(exported.Roles, item)
+		}
+	}
+	for _, item := range bundle.Users {
+		if item.Username == "bundle" {
+			exported.Users = append(exported.Users, item)
+		}
+	}
+	if !as.Len(exported.Menus, 1) || !as.Len(exported.Roles, 2) || !as.Len(exported.Users, 1) {
+		return
+	}
+	as.Len(exported.Menus[0].Children, 1)
+	for _, item := range exported.Roles {
+		if item.Code == "bundle-parent" {
+			as.Equal([]string{"bundle.delete"}, item.Menus)
+		} else {
+			as.Equal([]string{"bundle-parent"}, item.Parents)
+			as.Len(item.Resources, 1)
+		}
+	}
+	as.Equal("bundle-child", exported.Users[0].Roles[0].Role)
+
+	// Import into a database without the menus and roles of the bundle
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, parent.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/menus/%d", baseAPI, menu.ID)).Expect().Status(http.StatusOK)
+
+	body, err := exported.Marshal(schema.BundleFormatYAML)
+	if !as.Nil(err) {
+		return
+	}
+	var result schema.BundleImportResult
+	e.POST(baseAPI+"/bundles/import").WithHeader("Content-Type", "application/yaml").WithBytes(body).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &result})
+	as.Equal([]string{"bundle-parent", "bundle-child"}, result.CreatedRoles)
+	as.Empty(result.UpdatedRoles)
+	as.Equal([]string{"bundle"}, result.BoundUsers)
+	if as.NotNil(result.Menus) {
+		as.Len(result.Menus.Changes, 4)
+	}
+
+	var menus schema.Menus
+	e.GET(baseAPI+"/menus").WithQuery("code", "bundle.delete").
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menus})
+	if !as.Len(menus, 1) || !as.NotNil(menus[0].Children) || !as.Len(*menus[0].Children, 1) {
+		return
+	}
+	button = *(*menus[0].Children)[0]
+
+	var roles schema.Roles
+	e.GET(baseAPI+"/roles").WithQuery("code", "bundle-").
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &roles})
+	if !as.Len(roles, 2) {
+		return
+	}
+	for _, item := range roles {
+		if item.Code == "bundle-parent" {
+			parent = *item
+		} else {
+			role = *item
+		}
+	}
+	e.GET(fmt.Sprintf("%s/roles/%d", baseAPI, parent.ID)).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &parent})
+	if as.Len(parent.Menus, 1) {
+		as.Equal(button.ID, parent.Menus[0].MenuID)
+	}
+	e.GET(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+	as.Equal([]int64{parent.ID}, role.ParentIDs)
+
+	e.GET(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+	if as.Len(user.Roles, 1) {
+		as.Equal(role.ID, user.Roles[0].RoleID)
+	}
+
+	enforce := func(path, method string) bool {
+		ok, err := casbinx.GetEnforcer().Enforce(strconv.FormatInt(parent.ID, 10), "0", path, method)
+		as.Nil(err)
+		return ok
+	}
+	as.True(enforce("/api/v1/bundle-items", "GET"))
+	as.True(enforce("/api/v1/bundle-items/1", "DELETE"))
+
+	// Importing the bundle again only updates the roles
+	result = schema.BundleImportResult{}
+	e.POST(baseAPI+"/bundles/import").WithHeader("Content-Type", "application/yaml").WithBytes(body).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &result})
+	as.Empty(result.CreatedRoles)
+	as.Equal([]string{"bundle-parent", "bundle-child"}, result.UpdatedRoles)
+	as.Empty(result.Menus.Changes)
+
+	// The references are checked before any change
+	exported.Roles[0].Menus = []string{"bundle.unknown"}
+	e.POST(baseAPI + "/bundles/import").WithJSON(exported).Expect().Status(http.StatusBadRequest)
+	e.POST(baseAPI + "/bundles/import").WithJSON(schema.Bundle{Version: schema.BundleVersion + 1}).
+		Expect().Status(http.StatusBadRequest)
+
+	e.DELETE(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, parent.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/menus/%d", baseAPI, button.ParentID)).Expect().Status(http.StatusOK)
+}
@@ -0,0 +1,261 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/middleware"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestCasbin(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	parentForm := schema.MenuForm{
+		Code:      "casbin",
+		Name:      "Casbin",
+		Type:      "page",
+		Status:    schema.MenuStatusEnabled,
+		Resources: schema.MenuResources{{Method: "GET", Path: "/api/v1/casbin"}},
+	}
+	var parent schema.Menu
+	e.POST(baseAPI + "/menus").WithJSON(parentForm).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &parent})
+
+	childForm := schema.MenuForm{
+		Code:      "casbin-items",
+		Name:      "Casbin items",
+		Type:      "button",
+		Status:    schema.MenuStatusEnabled,
+		ParentID:  parent.ID,
+		Resources: schema.MenuResources{{Method: "GET", Path: "/api/v1/casbin/items/:id"}},
+	}
+	var child schema.Menu
+	e.POST(baseAPI + "/menus").WithJSON(childForm).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &child})
+
+	roleForm := schema.RoleForm{
+		Code:   "casbin",
+		Name:   "Casbin",
+		Status: schema.RoleStatusEnabled,
+		Menus:  schema.RoleMenus{{MenuID: child.ID}},
+	}
+	var role schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(roleForm).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+
+	enforce := func(path, method string) bool {
+		ok, err := casbinx.GetEnforcer().Enforce(strconv.FormatInt(role.ID, 10), "0", path, method)
+		as.Nil(err)
+		return ok
+	}
+
+	// The role is granted the resources of its menus and of their parents
+	as.True(enforce("/api/v1/casbin/items/1", "GET"))
+	as.True(enforce("/api/v1/casbin", "GET"))
+	as.False(enforce("/api/v1/casbin/items", "POST"))
+
+	childForm.Resources = schema.MenuResources{{Method: "POST", Path: "/api/v1/casbin/items"}}
+	e.PUT(fmt.Sprintf("%s/menus/%d", baseAPI, child.ID)).WithJSON(childForm).Expect().Status(http.StatusOK)
+	as.False(enforce("/api/v1/casbin/items/1", "GET"))
+	as.True(enforce("/api/v1/casbin/items", "POST"))
+
+	parentForm.Resources = schema.MenuResources{{Method: "GET", Path: "/api/v1/casbin/:name"}}
+	e.PUT(fmt.Sprintf("%s/menus/%d", baseAPI, parent.ID)).WithJSON(parentForm).Expect().Status(http.StatusOK)
+	as.False(enforce("/api/v1/casbin", "GET"))
+	as.True(enforce("/api/v1/casbin/stats", "GET"))
+
+	roleForm.Status = schema.RoleStatusDisabled
+	e.PUT(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).WithJSON(roleForm).Expect().Status(http.StatusOK)
+	as.False(enforce("/api/v1/casbin/items", "POST"))
+	as.Empty(casbinx.GetEnforcer().GetFilteredPolicy(0, strconv.FormatInt(role.ID, 10)))
+
+	roleForm.Status = schema.RoleStatusEnabled
+	e.PUT(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).WithJSON(roleForm).Expect().Status(http.StatusOK)
+	as.True(enforce("/api/v1/casbin/items", "POST"))
+
+	// Stale policies are fixed by the events of the other instances
+	_, err := casbinx.GetEnforcer().RemovePolicy(strconv.FormatInt(role.ID, 10), "0", "/api/v1/casbin/items", "POST", schema.ResourceEffectAllow)
+	as.Nil(err)
+	as.False(enforce("/api/v1/casbin/items", "POST"))
+	as.Nil(events.Bus.Publish(context.Background(), schema.EventRoleChanged, []byte(fmt.Sprintf(`{"role_ids":[%d]}`, role.ID))))
+	as.True(enforce("/api/v1/casbin/items", "POST"))
+
+	e.DELETE(fmt.Sprintf("%s/menus/%d", baseAPI, parent.ID)).Expect().Status(http.StatusOK)
+	as.False(enforce("/api/v1/casbin/items", "POST"))
+	as.False(enforce("/api/v1/casbin/stats", "GET"))
+
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).Expect().Status(http.StatusOK)
+	as.Empty(casbinx.GetEnforcer().GetFilteredPolicy(0, strconv.FormatInt(role.ID, 10)))
+}
+
+func TestCasbinRoleInheritance(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	menuForm := schema.MenuForm{
+		Code:      "casbin-inherit",
+		Name:      "Casbin inherit",
+		Type:      "page",
+		Status:    schema.MenuStatusEnabled,
+		Resources: schema.MenuResources{{Method: "GET", Path: "/api/v1/casbin-inherit"}},
+	}
+	var menu schema.Menu
+	e.POST(baseAPI + "/menus").WithJSON(menuForm).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menu})
+
+	parentForm := schema.RoleForm{
+		Code:   "casbin-parent",
+		Name:   "Casbin parent",
+		Status: schema.RoleStatusEnabled,
+		Menus:  schema.RoleMenus{{MenuID: menu.ID}},
+	}
+	var parent schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(parentForm).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &parent})
+
+	childForm := schema.RoleForm{
+		Code:      "casbin-child",
+		Name:      "Casbin child",
+		Status:    schema.RoleStatusEnabled,
+		ParentIDs: []int64{parent.ID},
+	}
+	var child schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(childForm).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &child})
+
+	enforce := func() bool {
+		ok, err := casbinx.GetEnforcer().Enforce(strconv.FormatInt(child.ID, 10), "0", "/api/v1/casbin-inherit", "GET")
+		as.Nil(err)
+		return ok
+	}
+
+	// The child role inherits the resources of the parent role
+	as.True(enforce())
+
+	var getChild schema.Role
+	e.GET(fmt.Sprintf("%s/roles/%d", baseAPI, child.ID)).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &getChild})
+	as.Equal([]int64{parent.ID}, getChild.ParentIDs)
+	as.Empty(getChild.Menus)
+	if as.Len(getChild.EffectiveMenus, 1) {
+		as.Equal(menu.ID, getChild.EffectiveMenus[0].MenuID)
+	}
+
+	// The inheritance cannot be circular
+	parentForm.ParentIDs = []int64{child.ID}
+	e.PUT(fmt.Sprintf("%s/roles/%d", baseAPI, parent.ID)).WithJSON(parentForm).Expect().Status(http.StatusBadRequest)
+	childForm.ParentIDs = []int64{child.ID}
+	e.PUT(fmt.Sprintf("%s/roles/%d", baseAPI, child.ID)).WithJSON(childForm).Expect().Status(http.StatusBadRequest)
+	childForm.ParentIDs = []int64{parent.ID}
+
+	// The disabled parent role is not inherited
+	parentForm.ParentIDs = nil
+	parentForm.Status = schema.RoleStatusDisabled
+	e.PUT(fmt.Sprintf("%s/roles/%d", baseAPI, parent.ID)).WithJSON(parentForm).Expect().Status(http.StatusOK)
+	as.False(enforce())
+
+	parentForm.Status = schema.RoleStatusEnabled
+	e.PUT(fmt.Sprintf("%s/roles/%d", baseAPI, parent.ID)).WithJSON(parentForm).Expect().Status(http.StatusOK)
+	as.True(enforce())
+
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, parent.ID)).Expect().Status(http.StatusOK)
+	as.False(enforce())
+	as.Empty(casbinx.GetEnforcer().GetFilteredGroupingPolicy(0, strconv.FormatInt(child.ID, 10)))
+
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, child.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/menus/%d", baseAPI, menu.ID)).Expect().Status(http.StatusOK)
+}
+
+func TestCasbinDenyPolicy(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	menuForm := schema.MenuForm{
+		Code:   "casbin-deny",
+		Name:   "Casbin deny",
+		Type:   "page",
+		Status: schema.MenuStatusEnabled,
+		Resources: schema.MenuResources{
+			{Method: "GET", Path: "/api/v1/casbin-deny/*"},
+			{Method: "DELETE", Path: "/api/v1/casbin-deny/*"},
+			{Method: "GET", Path: "/api/v1/casbin-deny/export", Effect: schema.ResourceEffectDeny},
+		},
+	}
+	var menu schema.Menu
+	e.POST(baseAPI + "/menus").WithJSON(menuForm).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menu})
+	menuForm.Code = "casbin-deny-invalid"
+	menuForm.Resources = schema.MenuResources{{Method: "GET", Path: "/api/v1/casbin-deny", Effect: "maybe"}}
+	e.POST(baseAPI + "/menus").WithJSON(menuForm).Expect().Status(http.StatusBadRequest)
+
+	// The editors may call everything of the menu except deleting
+	var editor schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{
+		Code:   "casbin-deny-editor",
+		Name:   "Editor",
+		Status: schema.RoleStatusEnabled,
+		Menus:  schema.RoleMenus{{MenuID: menu.ID}},
+		Resources: schema.RoleResources{
+			{Method: "DELETE", Path: "/api/v1/casbin-deny/:id", Effect: schema.ResourceEffectDeny},
+		},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &editor})
+	var admin schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{
+		Code:   "casbin-deny-admin",
+		Name:   "Admin",
+		Status: schema.RoleStatusEnabled,
+		Menus:  schema.RoleMenus{{MenuID: menu.ID}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &admin})
+
+	var role schema.Role
+	e.GET(fmt.Sprintf("%s/roles/%d", baseAPI, editor.ID)).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+	if as.Len(role.Resources, 1) {
+		as.Equal(schema.ResourceEffectDeny, role.Resources[0].Effect)
+	}
+
+	enforce := func(role schema.Role, path, method string) bool {
+		ok, err := casbinx.GetEnforcer().Enforce(strconv.FormatInt(role.ID, 10), "0", path, method)
+		as.Nil(err)
+		return ok
+	}
+	as.True(enforce(editor, "/api/v1/casbin-deny/1", "GET"))
+	as.False(enforce(editor, "/api/v1/casbin-deny/1", "DELETE"))
+	as.False(enforce(editor, "/api/v1/casbin-deny/export", "GET"))
+	as.True(enforce(admin, "/api/v1/casbin-deny/1", "DELETE"))
+	as.False(enforce(admin, "/api/v1/casbin-deny/export", "GET"))
+
+	// A role denied the request denies it whatever the other roles of the user allow
+	handler := gin.New()
+	handler.Use(middleware.CasbinWithConfig(middleware.CasbinConfig{
+		GetEnforcer: func(c *gin.Context) *casbin.SyncedEnforcer { return casbinx.GetEnforcer() },
+		GetSubjects: func(c *gin.Context) []string {
+			return []string{strconv.FormatInt(admin.ID, 10), strconv.FormatInt(editor.ID, 10)}
+		},
+		GetDomain: func(c *gin.Context) string { return "0" },
+	}))
+	handler.Any("/api/v1/casbin-deny/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	serve := func(method string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, "/api/v1/casbin-deny/1", nil))
+		return w.Code
+	}
+	as.Equal(http.StatusOK, serve("GET"))
+	as.Equal(http.StatusForbidden, serve("DELETE"))
+
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, editor.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, admin.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/menus/%d", baseAPI, menu.ID)).Expect().Status(http.StatusOK)
+	as.Empty(casbinx.GetEnforcer().GetFilteredPolicy(0, strconv.FormatInt(editor.ID, 10)))
+}
@@ -0,0 +1,139 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/assert"
+
+	orgSchema "github.com/supermicah/go-framework-admin/internal/mods/org/schema"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestDataScope(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	createRole := func(code, dataScope string) schema.Role {
+		var role schema.Role
+		e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{
+			Code:      code,
+			Name:      code,
+			Status:    schema.RoleStatusEnabled,
+			DataScope: dataScope,
+		}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+		as.Equal(dataScope, role.DataScope)
+		return role
+	}
+	selfRole := createRole("ds-self", schema.RoleDataScopeSelf)
+	deptRole := createRole("ds-dept", schema.RoleDataScopeDept)
+
+	createDept := func(code string, parentID int64) orgSchema.Dept {
+		var dept orgSchema.Dept
+		e.POST(baseAPI + "/depts").WithJSON(orgSchema.DeptForm{
+			Code:     code,
+			Name:     code,
+			Status:   orgSchema.DeptStatusEnabled,
+			ParentID: parentID,
+		}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &dept})
+		return dept
+	}
+	deptA := createDept("ds-a", 0)
+	deptAChild := createDept("ds-a-child", deptA.ID)
+	deptB := createDept("ds-b", 0)
+
+	password := hash.MD5String("data-scope")
+	createUser := func(req *httpexpect.Request, username string, dept *orgSchema.Dept, role schema.Role) schema.User {
+		var user schema.User
+		req.WithJSON(schema.UserForm{
+			Username: username,
+			Name:     username,
+			Password: password,
+			Status:   schema.UserStatusActivated,
+			Roles:    schema.UserRoles{{RoleID: role.ID}},
+		}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+		if dept != nil {
+			e.POST(fmt.Sprintf("%s/depts/%d/users", baseAPI, dept.ID)).
+				WithJSON(orgSchema.DeptUserForm{UserID: user.ID, IsPrimary: true}).Expect().Status(http.StatusOK)
+		}
+		return user
+	}
+	tokenOf := func(username string) string {
+		var token schema.LoginToken
+		login(e, username, password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+		return "Bearer " + token.AccessToken
+	}
+	queryUsernames := func(auth string) []string {
+		var users schema.Users
+		e.GET(baseAPI+"/users").WithQuery("username", "ds-").WithHeader("Authorization", auth).
+			Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &users})
+		var usernames []string
+		for _, user := range users {
+			usernames = append(usernames, user.Username)
+		}
+		sort.Strings(usernames)
+		return usernames
+	}
+
+	self := createUser(e.POST(baseAPI+"/users"), "ds-self", &deptA, selfRole)
+	colleague := createUser(e.POST(baseAPI+"/users"), "ds-colleague", &deptAChild, selfRole)
+	stranger := createUser(e.POST(baseAPI+"/users"), "ds-stranger", &deptB, selfRole)
+	manager := createUser(e.POST(baseAPI+"/users"), "ds-manager", &deptA, deptRole)
+
+	// The users of a self scope only see their own rows and the rows they created
+	selfAuth := tokenOf(self.Username)
+	as.Equal([]string{"ds-self"}, queryUsernames(selfAuth))
+	created := createUser(e.POST(baseAPI+"/users").WithHeader("Authorization", selfAuth), "ds-created", nil, selfRole)
+	as.Equal(self.ID, created.CreatedBy)
+	as.Equal([]string{"ds-created", "ds-self"}, queryUsernames(selfAuth))
+
+	// The users of a department scope see the rows owned by the users of their department
+	managerAuth := tokenOf(manager.Username)
+	as.Equal([]string{"ds-created", "ds-manager", "ds-self"}, queryUsernames(managerAuth))
+
+	// The changes of the data scope apply to the signed in users
+	updateRole := func(role schema.Role, dataScope string, deptIDs ...int64) {
+		e.PUT(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).WithJSON(schema.RoleForm{
+			Code:             role.Code,
+			Name:             role.Name,
+			Status:           schema.RoleStatusEnabled,
+			DataScope:        dataScope,
+			DataScopeDeptIDs: deptIDs,
+		}).Expect().Status(http.StatusOK)
+	}
+	updateRole(deptRole, schema.RoleDataScopeDeptAndChildren)
+	as.Equal([]string{"ds-colleague", "ds-created", "ds-manager", "ds-self"}, queryUsernames(managerAuth))
+
+	// The department of a user signed in through two-factor is cached as well
+	var setup schema.TwoFactorSetup
+	e.POST(baseAPI+"/current/2fa/setup").WithHeader("Authorization", managerAuth).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &setup})
+	e.POST(baseAPI+"/current/2fa/enable").WithHeader("Authorization", managerAuth).
+		WithJSON(schema.TwoFactorCodeForm{Code: totpCode(t, setup.Secret, 0)}).Expect().Status(http.StatusOK)
+	var challenge, token schema.LoginToken
+	login(e, manager.Username, password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &challenge})
+	loginTwoFactor(e, challenge.ChallengeToken, totpCode(t, setup.Secret, 1)).
+		Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	managerAuth = "Bearer " + token.AccessToken
+	as.Equal([]string{"ds-colleague", "ds-created", "ds-manager", "ds-self"}, queryUsernames(managerAuth))
+
+	updateRole(selfRole, schema.RoleDataScopeCustom, deptB.ID)
+	as.Equal([]string{"ds-stranger"}, queryUsernames(selfAuth))
+
+	// Without a data scope all the rows are visible
+	as.Len(queryUsernames(""), 5)
+
+	for _, user := range []schema.User{self, colleague, stranger, manager, created} {
+		e.DELETE(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).Expect().Status(http.StatusOK)
+	}
+	for _, dept := range []orgSchema.Dept{deptAChild, deptA, deptB} {
+		e.DELETE(fmt.Sprintf("%s/depts/%d", baseAPI, dept.ID)).Expect().Status(http.StatusOK)
+	}
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, selfRole.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, deptRole.ID)).Expect().Status(http.StatusOK)
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	orgSchema "github.com/supermicah/go-framework-admin/internal/mods/org/schema"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestDept(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	createDept := func(code string, parentID int64) orgSchema.Dept {
+		var dept orgSchema.Dept
+		e.POST(baseAPI + "/depts").WithJSON(orgSchema.DeptForm{
+			Code:     code,
+			Name:     code,
+			Status:   orgSchema.DeptStatusEnabled,
+			ParentID: parentID,
+		}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &dept})
+		as.Equal(parentID, dept.ParentID)
+		return dept
+	}
+	root := createDept("dept-root", 0)
+	sales := createDept("dept-sales", root.ID)
+	east := createDept("dept-east", sales.ID)
+	rd := createDept("dept-rd", root.ID)
+	as.Equal(fmt.Sprintf("%d.%d.", root.ID, sales.ID), east.ParentPath)
+
+	e.POST(baseAPI + "/depts").WithJSON(orgSchema.DeptForm{
+		Code: "dept-sales", Name: "Sales", Status: orgSchema.DeptStatusEnabled, ParentID: root.ID,
+	}).Expect().Status(http.StatusBadRequest)
+
+	// The departments are queried as a tree
+	var tree orgSchema.Depts
+	e.GET(baseAPI+"/depts").WithQuery("root", root.ID).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &tree})
+	if as.Len(tree, 1) && as.NotNil(tree[0].Children) {
+		as.Equal(root.ID, tree[0].ID)
+		as.Len(*tree[0].Children, 2)
+	}
+
+	// A department is moved with its children, but not under itself
+	e.PUT(fmt.Sprintf("%s/depts/%d/move", baseAPI, sales.ID)).WithJSON(orgSchema.DeptMoveForm{ParentID: east.ID}).
+		Expect().Status(http.StatusBadRequest)
+	e.PUT(fmt.Sprintf("%s/depts/%d/move", baseAPI, sales.ID)).WithJSON(orgSchema.DeptMoveForm{ParentID: rd.ID}).
+		Expect().Status(http.StatusOK)
+	var moved orgSchema.Dept
+	e.GET(fmt.Sprintf("%s/depts/%d", baseAPI, east.ID)).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &moved})
+	as.Equal(fmt.Sprintf("%d.%d.%d.", root.ID, rd.ID, sales.ID), moved.ParentPath)
+
+	// The primary department of a member is the department of the user
+	var user schema.User
+	e.POST(baseAPI + "/users").WithJSON(schema.UserForm{
+		Username: "dept-member",
+		Name:     "Member",
+		Password: hash.MD5String("dept-member"),
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+
+	getUserDeptID := func() int64 {
+		var item schema.User
+		e.GET(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).
+			Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &item})
+		return item.DeptID
+	}
+	e.POST(fmt.Sprintf("%s/depts/%d/users", baseAPI, sales.ID)).
+		WithJSON(orgSchema.DeptUserForm{UserID: user.ID, IsPrimary: true, IsLeader: true}).Expect().Status(http.StatusOK)
+	as.Equal(sales.ID, getUserDeptID())
+	e.POST(fmt.Sprintf("%s/depts/%d/users", baseAPI, rd.ID)).
+		WithJSON(orgSchema.DeptUserForm{UserID: user.ID, IsPrimary: true}).Expect().Status(http.StatusOK)
+	as.Equal(rd.ID, getUserDeptID())
+
+	var members orgSchema.DeptUsers
+	e.GET(fmt.Sprintf("%s/depts/%d/users", baseAPI, sales.ID)).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &members})
+	if as.Len(members, 1) {
+		as.Equal("dept-member", members[0].Username)
+		as.False(members[0].IsPrimary)
+		as.True(members[0].IsLeader)
+	}
+	var dept orgSchema.Dept
+	e.GET(fmt.Sprintf("%s/depts/%d", baseAPI, sales.ID)).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &dept})
+	as.Len(dept.Leaders, 1)
+
+	// The departments with children or members cannot be deleted
+	e.DELETE(fmt.Sprintf("%s/depts/%d", baseAPI, root.ID)).Expect().Status(http.StatusBadRequest)
+	e.DELETE(fmt.Sprintf("%s/depts/%d", baseAPI, rd.ID)).Expect().Status(http.StatusBadRequest)
+
+	e.DELETE(fmt.Sprintf("%s/depts/%d/users/%d", baseAPI, rd.ID, user.ID)).Expect().Status(http.StatusOK)
+	as.Zero(getUserDeptID())
+	e.DELETE(fmt.Sprintf("%s/depts/%d/users/%d", baseAPI, rd.ID, user.ID)).Expect().Status(http.StatusNotFound)
+
+	e.DELETE(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).Expect().Status(http.StatusOK)
+	for _, item := range []orgSchema.Dept{east, sales, rd, root} {
+		e.DELETE(fmt.Sprintf("%s/depts/%d", baseAPI, item.ID)).Expect().Status(http.StatusOK)
+	}
+	e.GET(fmt.Sprintf("%s/depts/%d", baseAPI, root.ID)).Expect().Status(http.StatusNotFound)
+}
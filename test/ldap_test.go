@@ -0,0 +1,116 @@
+package test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/ldap/ldaptest"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestLDAP(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	server := ldaptest.NewServer()
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	as.Nil(os.WriteFile(caFile, server.CertificatePEM(), 0o600))
+
+	const lisaDN = "uid=lisa,ou=people,dc=example,dc=com"
+	server.AddEntry("cn=admin,dc=example,dc=com", "admin-pwd", nil)
+	server.AddEntry(lisaDN, "lisa-pwd", map[string][]string{
+		"uid":      {"lisa"},
+		"cn":       {"Lisa"},
+		"mail":     {"lisa@example.com"},
+		"memberOf": {"CN=Admins,OU=Groups,DC=example,DC=com", "cn=unmapped,ou=groups,dc=example,dc=com"},
+	})
+	server.AddEntry("uid=carl,ou=people,dc=example,dc=com", "carl-pwd", map[string][]string{
+		"uid": {"carl"},
+		"cn":  {"Carl"},
+	})
+	server.AddEntry("cn=Staff,ou=groups,dc=example,dc=com", "", map[string][]string{
+		"cn":     {"Staff"},
+		"member": {lisaDN},
+	})
+
+	ldapCfg := config.C.Util.LDAP
+	defer func() { config.C.Util.LDAP = ldapCfg }()
+	config.C.Util.LDAP.Enable = true
+	config.C.Util.LDAP.URL = server.URL
+	config.C.Util.LDAP.StartTLS = true
+	config.C.Util.LDAP.CAFile = caFile
+	config.C.Util.LDAP.BindDN = "cn=admin,dc=example,dc=com"
+	config.C.Util.LDAP.BindPassword = "admin-pwd"
+	config.C.Util.LDAP.BaseDN = "ou=people,dc=example,dc=com"
+	config.C.Util.LDAP.RoleMapping = map[string]string{
+		"cn=admins,ou=groups,dc=example,dc=com": "ldap-admin",
+		"Staff":                                 "ldap-staff",
+	}
+	config.C.Util.LDAP.DefaultRoles = []string{"ldap-user"}
+	config.C.Util.LDAP.AutoCreate = true
+	config.C.Util.LDAP.SyncRoles = true
+
+	roles := make(map[string]int64)
+	for _, code := range []string{"ldap-admin", "ldap-staff", "ldap-user"} {
+		var role schema.Role
+		e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{Code: code, Name: code, Status: schema.RoleStatusEnabled}).
+			Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+		roles[code] = role.ID
+	}
+
+	// Local users are authenticated without the directory
+	password := hash.MD5String("local")
+	e.POST(baseAPI + "/users").WithJSON(schema.UserForm{
+		Username: "ldap-local",
+		Name:     "Local",
+		Password: password,
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{},
+	}).Expect().Status(http.StatusOK)
+	login(e, "ldap-local", password).Status(http.StatusOK)
+	as.Equal(0, server.Binds())
+
+	// First bind provisions the user with the mapped and default roles
+	var token schema.LoginToken
+	login(e, "lisa", "lisa-pwd").Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	as.NotEmpty(token.AccessToken)
+
+	var user schema.User
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", "Bearer "+token.AccessToken).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+	as.Equal("lisa", user.Username)
+	as.Equal("Lisa", user.Name)
+	as.Equal("lisa@example.com", user.Email)
+	as.ElementsMatch([]int64{roles["ldap-admin"], roles["ldap-user"]}, user.Roles.ToRoleIDs())
+
+	login(e, "lisa", "wrong").Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Object().Value("id").IsEqual(config.ErrInvalidUsernameOrPassword)
+
+	// Next bind updates the profile and synchronizes the roles, here from the group search
+	server.SetAttribute(lisaDN, "cn", "Lisa Simpson")
+	config.C.Util.LDAP.GroupBaseDN = "ou=groups,dc=example,dc=com"
+	var token2 schema.LoginToken
+	login(e, "lisa", "lisa-pwd").Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token2})
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", "Bearer "+token.AccessToken).
+		Expect().Status(http.StatusUnauthorized)
+
+	user = schema.User{}
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", "Bearer "+token2.AccessToken).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+	as.Equal("lisa", user.Username)
+	as.Equal("Lisa Simpson", user.Name)
+	as.ElementsMatch([]int64{roles["ldap-staff"], roles["ldap-user"]}, user.Roles.ToRoleIDs())
+
+	// Unknown users are rejected without provisioning
+	config.C.Util.LDAP.AutoCreate = false
+	login(e, "carl", "carl-pwd").Status(http.StatusForbidden).
+		JSON().Object().Value("error").Object().Value("id").IsEqual(config.ErrLDAPLoginFailedID)
+}
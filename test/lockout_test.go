@@ -0,0 +1,83 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func loginFrom(e *httpexpect.Expect, username, password, clientIP string) *httpexpect.Response {
+	return loginRequest(e, username, password).WithTransformer(func(r *http.Request) {
+		r.RemoteAddr = clientIP + ":10000"
+	}).Expect()
+}
+
+func TestLockout(t *testing.T) {
+	e := tester(t)
+
+	lockout := config.C.Util.Lockout
+	defer func() { config.C.Util.Lockout = lockout }()
+	config.C.Util.Lockout.MaxAttempts = 3
+	config.C.Util.Lockout.MaxIPAttempts = 5
+	config.C.Util.Lockout.LockDuration = 1
+
+	password := hash.MD5String("lockout")
+	var user schema.User
+	e.POST(baseAPI + "/users").WithJSON(schema.UserForm{
+		Username: "lockout",
+		Name:     "Lockout",
+		Password: password,
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+
+	// Locked by username, the correct password is rejected as well
+	for i := 0; i < 3; i++ {
+		loginFrom(e, "lockout", "wrong", "10.0.0.1").Status(http.StatusBadRequest)
+	}
+	loginFrom(e, "lockout", password, "10.0.0.1").Status(http.StatusTooManyRequests).
+		JSON().Object().Value("error").Object().Value("id").IsEqual(config.ErrLoginLockedID)
+	loginFrom(e, "lockout", password, "10.0.0.9").Status(http.StatusTooManyRequests)
+
+	e.PATCH(fmt.Sprintf("%s/users/%d/unlock", baseAPI, user.ID)).Expect().Status(http.StatusOK)
+	e.PATCH(fmt.Sprintf("%s/users/%d/unlock", baseAPI, user.ID+1000)).Expect().Status(http.StatusNotFound)
+	loginFrom(e, "lockout", password, "10.0.0.1").Status(http.StatusOK)
+
+	// Exponential backoff, the second lock lasts twice as long as the first one
+	for i := 0; i < 3; i++ {
+		loginFrom(e, "lockout", "wrong", "10.0.0.5").Status(http.StatusBadRequest)
+	}
+	loginFrom(e, "lockout", password, "10.0.0.5").Status(http.StatusTooManyRequests)
+	time.Sleep(1200 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		loginFrom(e, "lockout", "wrong", "10.0.0.6").Status(http.StatusBadRequest)
+	}
+	time.Sleep(1200 * time.Millisecond)
+	loginFrom(e, "lockout", password, "10.0.0.5").Status(http.StatusTooManyRequests)
+	time.Sleep(1000 * time.Millisecond)
+	loginFrom(e, "lockout", password, "10.0.0.5").Status(http.StatusOK)
+
+	// Locked by client IP across usernames
+	for i := 0; i < 5; i++ {
+		loginFrom(e, fmt.Sprintf("nobody%d", i), "wrong", "10.0.0.2").Status(http.StatusBadRequest)
+	}
+	loginFrom(e, "lockout", password, "10.0.0.2").Status(http.StatusTooManyRequests)
+	loginFrom(e, "lockout", password, "10.0.0.3").Status(http.StatusOK)
+
+	// Root user
+	rootUsername := config.C.General.Root.Username
+	for i := 0; i < 3; i++ {
+		loginFrom(e, rootUsername, "wrong", "10.0.0.4").Status(http.StatusBadRequest)
+	}
+	loginFrom(e, rootUsername, "wrong", "10.0.0.4").Status(http.StatusTooManyRequests)
+	e.PATCH(fmt.Sprintf("%s/users/%d/unlock", baseAPI, config.C.General.Root.ID)).Expect().Status(http.StatusOK)
+	loginFrom(e, rootUsername, "wrong", "10.0.0.4").Status(http.StatusBadRequest)
+}
@@ -0,0 +1,143 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestMenuI18n(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	general := config.C.General
+	defer func() { config.C.General = general }()
+	config.C.General.WorkDir = t.TempDir()
+	config.C.General.MenuFile = "menu.json"
+	config.C.General.MenuLocaleFiles = map[string]string{"en-US": "menu_en.json"}
+	config.C.General.DefaultLocale = "zh-CN"
+	config.C.General.DenyDeleteMenu = false
+
+	var existing schema.Menus
+	e.GET(baseAPI+"/menus").WithQuery("includeResources", true).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &existing})
+	writeMenus := func(name string, menus schema.Menus) {
+		b, err := json.Marshal(menus)
+		as.Nil(err)
+		as.Nil(os.WriteFile(filepath.Join(config.C.General.WorkDir, name), b, 0644))
+	}
+	writeMenus("menu.json", append(existing, &schema.Menu{
+		Code:     "i18n",
+		Name:     "国际化",
+		Type:     "page",
+		Status:   schema.MenuStatusEnabled,
+		Children: &schema.Menus{{Code: "add", Name: "新增", Type: "button", Status: schema.MenuStatusEnabled}},
+	}))
+	writeMenus("menu_en.json", schema.Menus{
+		{Code: "i18n", Name: "I18n", Children: &schema.Menus{{Code: "add", Name: "Add"}, {Code: "unknown", Name: "Unknown"}}},
+	})
+	e.POST(baseAPI + "/menus/reconcile").Expect().Status(http.StatusOK)
+
+	menuNames := func(menus schema.Menus) []string {
+		for _, menu := range menus {
+			if menu.Code == "i18n" && menu.Children != nil && len(*menu.Children) == 1 {
+				return []string{menu.Name, (*menu.Children)[0].Name}
+			}
+		}
+		return nil
+	}
+	adminNames := func(acceptLanguage string) []string {
+		var menus schema.Menus
+		e.GET(baseAPI+"/menus").WithQuery("code", "i18n").WithHeader("Accept-Language", acceptLanguage).
+			Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menus})
+		return menuNames(menus)
+	}
+
+	as.Equal([]string{"I18n", "Add"}, adminNames("en-GB,en;q=0.9"))
+	as.Equal([]string{"国际化", "新增"}, adminNames("zh-CN"))
+	as.Equal([]string{"国际化", "新增"}, adminNames("fr-FR,de;q=0.5"))
+	as.Equal([]string{"国际化", "新增"}, adminNames(""))
+
+	// Updating the locale file updates the translations
+	writeMenus("menu_en.json", schema.Menus{
+		{Code: "i18n", Name: "Internationalization", Children: &schema.Menus{{Code: "add", Name: "Add"}}},
+	})
+	e.POST(baseAPI + "/menus/reconcile").Expect().Status(http.StatusOK)
+	as.Equal([]string{"Internationalization", "Add"}, adminNames("en"))
+
+	// The preferred locale of the user comes before the Accept-Language header
+	var menus schema.Menus
+	e.GET(baseAPI+"/menus").WithQuery("code", "i18n").
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menus})
+	if !as.Len(menus, 1) {
+		return
+	}
+	var role schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{
+		Code:   "i18n",
+		Name:   "I18n",
+		Status: schema.RoleStatusEnabled,
+		Menus:  schema.RoleMenus{{MenuID: (*menus[0].Children)[0].ID}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+	password := hash.MD5String("i18n")
+	var user schema.User
+	e.POST(baseAPI + "/users").WithJSON(schema.UserForm{
+		Username: "i18n",
+		Name:     "I18n",
+		Password: password,
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{{RoleID: role.ID}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+
+	var token schema.LoginToken
+	login(e, "i18n", password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	auth := "Bearer " + token.AccessToken
+	currentNames := func(acceptLanguage string) []string {
+		var menus schema.Menus
+		e.GET(baseAPI+"/current/menus").WithHeader("Authorization", auth).WithHeader("Accept-Language", acceptLanguage).
+			Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menus})
+		return menuNames(menus)
+	}
+
+	as.Equal([]string{"Internationalization", "Add"}, currentNames("en-US"))
+	as.Equal([]string{"国际化", "新增"}, currentNames("zh"))
+
+	locale := "en_US"
+	e.PUT(baseAPI+"/current/user").WithHeader("Authorization", auth).
+		WithJSON(schema.UpdateCurrentUser{Name: "I18n", Locale: &locale}).Expect().Status(http.StatusOK)
+	as.Equal([]string{"Internationalization", "Add"}, currentNames("zh-CN"))
+
+	// The locale is read from the cached user, and kept when the field is absent
+	userCacheVal, ok, err := cache.Get(context.Background(), config.CacheNSForUser, fmt.Sprintf("%d", user.ID))
+	as.Nil(err)
+	as.True(ok)
+	as.Equal(locale, util.ParseUserCache(userCacheVal).Locale)
+	e.PUT(baseAPI+"/current/user").WithHeader("Authorization", auth).
+		WithJSON(schema.UpdateCurrentUser{Name: "I18n 2"}).Expect().Status(http.StatusOK)
+	as.Equal([]string{"Internationalization", "Add"}, currentNames("zh-CN"))
+
+	// A new login caches the locale of the user again
+	login(e, "i18n", password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	auth = "Bearer " + token.AccessToken
+	as.Equal([]string{"Internationalization", "Add"}, currentNames("zh-CN"))
+
+	// The translations are deleted with the menus
+	writeMenus("menu.json", existing)
+	e.POST(baseAPI + "/menus/reconcile").Expect().Status(http.StatusOK)
+	e.GET(baseAPI+"/menus").WithQuery("code", "i18n").Expect().Status(http.StatusOK).
+		JSON().Object().Value("data").Array().IsEmpty()
+
+	e.DELETE(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).Expect().Status(http.StatusOK)
+}
@@ -0,0 +1,160 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestMenuMove(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	create := func(code string, parentID int64, sequence int) *schema.Menu {
+		var menu schema.Menu
+		e.POST(baseAPI + "/menus").WithJSON(schema.MenuForm{
+			Code:     code,
+			Name:     code,
+			Sequence: sequence,
+			Type:     "page",
+			Status:   schema.MenuStatusEnabled,
+			ParentID: parentID,
+		}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menu})
+		return &menu
+	}
+	get := func(id int64) *schema.Menu {
+		var menu schema.Menu
+		e.GET(fmt.Sprintf("%s/menus/%d", baseAPI, id)).
+			Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menu})
+		return &menu
+	}
+	childCodes := func(code string) []string {
+		var menus schema.Menus
+		e.GET(baseAPI+"/menus").WithQuery("code", code).
+			Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menus})
+		var codes []string
+		if as.Len(menus, 1) && menus[0].Children != nil {
+			for _, child := range *menus[0].Children {
+				codes = append(codes, child.Code)
+			}
+		}
+		return codes
+	}
+	move := func(id, parentID int64, position int) *httpexpect.Response {
+		return e.POST(fmt.Sprintf("%s/menus/%d/move", baseAPI, id)).
+			WithJSON(schema.MenuMoveForm{ParentID: parentID, Position: position}).Expect()
+	}
+	reorder := func(ids ...int64) *httpexpect.Response {
+		return e.PUT(baseAPI + "/menus/reorder").WithJSON(schema.MenuReorderForm{IDs: ids}).Expect()
+	}
+
+	a := create("move-a", 0, 0)
+	b := create("move-b", 0, 0)
+	c := create("move-c", a.ID, 0)
+	d := create("move-d", c.ID, 0)
+	b1 := create("move-b1", b.ID, 2)
+	b2 := create("move-b2", b.ID, 1)
+	as.Equal([]string{"move-b1", "move-b2"}, childCodes("move-b"))
+
+	// The descendants follow the moved menu
+	move(c.ID, b.ID, 1).Status(http.StatusOK)
+	as.Equal([]string{"move-b1", "move-c", "move-b2"}, childCodes("move-b"))
+	c = get(c.ID)
+	as.Equal(b.ID, c.ParentID)
+	as.Equal(fmt.Sprintf("%d.", b.ID), c.ParentPath)
+	as.Equal(fmt.Sprintf("%d.%d.", b.ID, c.ID), get(d.ID).ParentPath)
+	as.Nil(get(a.ID).Children)
+
+	// A position beyond the siblings moves the menu last
+	move(c.ID, b.ID, 10).Status(http.StatusOK)
+	as.Equal([]string{"move-b1", "move-b2", "move-c"}, childCodes("move-b"))
+	move(c.ID, b.ID, 0).Status(http.StatusOK)
+	as.Equal([]string{"move-c", "move-b1", "move-b2"}, childCodes("move-b"))
+
+	// A menu cannot be moved under itself or its descendants
+	move(c.ID, c.ID, 0).Status(http.StatusBadRequest)
+	move(c.ID, d.ID, 0).Status(http.StatusBadRequest)
+	move(b.ID, d.ID, 0).Status(http.StatusBadRequest)
+	move(c.ID, -1, 0).Status(http.StatusNotFound)
+	e.PUT(fmt.Sprintf("%s/menus/%d", baseAPI, b.ID)).WithJSON(schema.MenuForm{
+		Code:     b.Code,
+		Name:     b.Name,
+		Type:     b.Type,
+		Status:   b.Status,
+		ParentID: d.ID,
+	}).Expect().Status(http.StatusBadRequest)
+
+	reorder(b2.ID, c.ID, b1.ID).Status(http.StatusOK)
+	as.Equal([]string{"move-b2", "move-c", "move-b1"}, childCodes("move-b"))
+	reorder(b2.ID, c.ID).Status(http.StatusBadRequest)
+	reorder(b2.ID, c.ID, b1.ID, d.ID).Status(http.StatusBadRequest)
+	reorder(b2.ID, b2.ID, c.ID).Status(http.StatusBadRequest)
+	reorder().Status(http.StatusBadRequest)
+
+	// Moving to the root
+	move(c.ID, 0, 0).Status(http.StatusOK)
+	c = get(c.ID)
+	as.Equal(int64(0), c.ParentID)
+	as.Equal("", c.ParentPath)
+	as.Equal(fmt.Sprintf("%d.", c.ID), get(d.ID).ParentPath)
+
+	for _, id := range []int64{a.ID, b.ID, c.ID} {
+		e.DELETE(fmt.Sprintf("%s/menus/%d", baseAPI, id)).Expect().Status(http.StatusOK)
+	}
+}
+
+func TestMenuMovePathPrefix(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	// The ID of the moved menu is a suffix of the ID of its child, so its path also occurs inside the descendant paths
+	// (e.g. "5." in "5.15."), the IDs are out of the range of the other tests
+	parent := &schema.Menu{ID: 1000005, Code: "prefix-5", Name: "prefix-5", Type: "page", Status: schema.MenuStatusEnabled}
+	child := &schema.Menu{ID: 11000005, Code: "prefix-15", Name: "prefix-15", Type: "page", Status: schema.MenuStatusEnabled,
+		ParentID: parent.ID, ParentPath: "1000005."}
+	grandchild := &schema.Menu{ID: 11000006, Code: "prefix-16", Name: "prefix-16", Type: "page", Status: schema.MenuStatusEnabled,
+		ParentID: child.ID, ParentPath: "1000005.11000005."}
+	as.NoError(db.Create([]*schema.Menu{parent, child, grandchild}).Error)
+
+	var target schema.Menu
+	e.POST(baseAPI + "/menus").WithJSON(schema.MenuForm{
+		Code:   "prefix-target",
+		Name:   "prefix-target",
+		Type:   "page",
+		Status: schema.MenuStatusEnabled,
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &target})
+
+	parentPath := func(id int64) string {
+		var menu schema.Menu
+		e.GET(fmt.Sprintf("%s/menus/%d", baseAPI, id)).
+			Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menu})
+		return menu.ParentPath
+	}
+
+	e.POST(fmt.Sprintf("%s/menus/%d/move", baseAPI, parent.ID)).
+		WithJSON(schema.MenuMoveForm{ParentID: target.ID}).Expect().Status(http.StatusOK)
+	as.Equal(fmt.Sprintf("%d.", target.ID), parentPath(parent.ID))
+	as.Equal(fmt.Sprintf("%d.1000005.", target.ID), parentPath(child.ID))
+	as.Equal(fmt.Sprintf("%d.1000005.11000005.", target.ID), parentPath(grandchild.ID))
+
+	// Reparenting by the update shares the path replacement
+	e.PUT(fmt.Sprintf("%s/menus/%d", baseAPI, parent.ID)).WithJSON(schema.MenuForm{
+		Code:   parent.Code,
+		Name:   parent.Name,
+		Type:   parent.Type,
+		Status: parent.Status,
+	}).Expect().Status(http.StatusOK)
+	as.Equal("", parentPath(parent.ID))
+	as.Equal("1000005.", parentPath(child.ID))
+	as.Equal("1000005.11000005.", parentPath(grandchild.ID))
+
+	for _, id := range []int64{parent.ID, target.ID} {
+		e.DELETE(fmt.Sprintf("%s/menus/%d", baseAPI, id)).Expect().Status(http.StatusOK)
+	}
+}
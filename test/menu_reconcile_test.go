@@ -0,0 +1,194 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestMenuReconcile(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	workDir, menuFile, denyDelete := config.C.General.WorkDir, config.C.General.MenuFile, config.C.General.DenyDeleteMenu
+	defer func() {
+		config.C.General.WorkDir, config.C.General.MenuFile, config.C.General.DenyDeleteMenu = workDir, menuFile, denyDelete
+	}()
+	config.C.General.WorkDir = t.TempDir()
+	config.C.General.MenuFile = "menu.json"
+	config.C.General.DenyDeleteMenu = false
+
+	// The menus of the database are kept as they are matched by ID
+	var existing schema.Menus
+	e.GET(baseAPI+"/menus").WithQuery("includeResources", true).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &existing})
+	writeMenus := func(menus schema.Menus) {
+		b, err := json.Marshal(append(existing, menus...))
+		as.Nil(err)
+		as.Nil(os.WriteFile(filepath.Join(config.C.General.WorkDir, "menu.json"), b, 0644))
+	}
+	reconcile := func(dryRun bool) []string {
+		var diff schema.MenuDiff
+		e.POST(baseAPI+"/menus/reconcile").WithQuery("dryRun", dryRun).
+			Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &diff})
+		as.Equal(dryRun, diff.DryRun)
+		var changes []string
+		for _, change := range diff.Changes {
+			if strings.HasPrefix(change.CodePath, "reconcile") {
+				changes = append(changes, change.String())
+			}
+		}
+		return changes
+	}
+	getMenu := func(code string) *schema.Menu {
+		var menus schema.Menus
+		e.GET(baseAPI+"/menus").WithQuery("name", "Reconcile").WithQuery("includeResources", true).
+			Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menus})
+		var find func(schema.Menus) *schema.Menu
+		find = func(menus schema.Menus) *schema.Menu {
+			for _, menu := range menus {
+				if menu.Code == code {
+					return menu
+				} else if menu.Children != nil {
+					if child := find(*menu.Children); child != nil {
+						return child
+					}
+				}
+			}
+			return nil
+		}
+		return find(menus)
+	}
+
+	writeMenus(schema.Menus{
+		{
+			Code:      "reconcile",
+			Name:      "Reconcile",
+			Type:      "page",
+			Resources: schema.MenuResources{{Method: "GET", Path: "/api/v1/reconcile"}},
+			Children: &schema.Menus{
+				{
+					Code:      "reconcile-items",
+					Name:      "Reconcile items",
+					Type:      "button",
+					Resources: schema.MenuResources{{Method: "GET", Path: "/api/v1/reconcile/items"}},
+				},
+				{
+					Code:      "old",
+					Name:      "Reconcile old",
+					Type:      "button",
+					Resources: schema.MenuResources{{Method: "DELETE", Path: "/api/v1/reconcile/:id"}},
+				},
+			},
+		},
+		{Code: "reconcile-target", Name: "Reconcile target", Type: "page"},
+	})
+	changes := reconcile(false)
+	as.Len(changes, 7)
+	for _, change := range changes {
+		as.True(strings.HasPrefix(change, schema.MenuChangeCreate), change)
+	}
+	as.Empty(reconcile(true))
+
+	parent, items, target := getMenu("reconcile"), getMenu("reconcile-items"), getMenu("reconcile-target")
+	if !as.NotNil(parent) || !as.NotNil(items) || !as.NotNil(target) {
+		return
+	}
+	as.Equal(parent.ID, items.ParentID)
+
+	var role schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{
+		Code:   "reconcile",
+		Name:   "Reconcile",
+		Status: schema.RoleStatusEnabled,
+		Menus:  schema.RoleMenus{{MenuID: items.ID}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+	enforce := func(path, method string) bool {
+		ok, err := casbinx.GetEnforcer().Enforce(strconv.FormatInt(role.ID, 10), "0", path, method)
+		as.Nil(err)
+		return ok
+	}
+	as.True(enforce("/api/v1/reconcile", "GET"))
+	as.True(enforce("/api/v1/reconcile/items", "GET"))
+
+	// Rename the page, replace its resource, move the items to the target and remove the old button
+	writeMenus(schema.Menus{
+		{
+			Code:      "reconcile",
+			Name:      "Reconcile v2",
+			Type:      "page",
+			Resources: schema.MenuResources{{Method: "GET", Path: "/api/v1/reconcile/list"}},
+		},
+		{
+			Code: "reconcile-target",
+			Name: "Reconcile target",
+			Type: "page",
+			Children: &schema.Menus{
+				{
+					Code:      "reconcile-items",
+					Name:      "Reconcile items",
+					Type:      "button",
+					Resources: schema.MenuResources{{Method: "GET", Path: "/api/v1/reconcile/items"}},
+				},
+			},
+		},
+	})
+	expected := []string{
+		fmt.Sprintf("update menu reconcile #%d [name]", parent.ID),
+		fmt.Sprintf("create resource GET /api/v1/reconcile/list (allow) of menu reconcile #%d", parent.ID),
+		fmt.Sprintf("delete resource GET /api/v1/reconcile (allow) of menu reconcile #%d", parent.ID),
+		fmt.Sprintf("update menu reconcile-target.reconcile-items #%d [sequence]", items.ID),
+		fmt.Sprintf("move menu reconcile-target.reconcile-items #%d from reconcile", items.ID),
+	}
+	changes = reconcile(true)
+	if as.Len(changes, 6) {
+		as.Equal(expected, changes[:5])
+		as.True(strings.HasPrefix(changes[5], "delete menu reconcile.old #"), changes[5])
+	}
+	as.Equal("Reconcile", getMenu("reconcile").Name)
+
+	changes = reconcile(false)
+	as.Len(changes, 6)
+	as.Empty(reconcile(true))
+	parent, items = getMenu("reconcile"), getMenu("reconcile-items")
+	as.Equal("Reconcile v2", parent.Name)
+	if as.Len(parent.Resources, 1) {
+		as.Equal("/api/v1/reconcile/list", parent.Resources[0].Path)
+	}
+	as.Equal(target.ID, items.ParentID)
+	as.Equal(fmt.Sprintf("%d%s", target.ID, util.TreePathDelimiter), items.ParentPath)
+	as.Nil(getMenu("old"))
+
+	// The role granted the items is granted the resources of its new parent
+	as.False(enforce("/api/v1/reconcile", "GET"))
+	as.True(enforce("/api/v1/reconcile/items", "GET"))
+
+	// The deletions are skipped if the deletion of menus is denied
+	writeMenus(nil)
+	config.C.General.DenyDeleteMenu = true
+	changes = reconcile(false)
+	if as.Len(changes, 3) {
+		as.True(strings.HasSuffix(changes[0], "(skipped)"), changes[0])
+	}
+	as.NotNil(getMenu("reconcile"))
+	e.DELETE(fmt.Sprintf("%s/menus/%d", baseAPI, parent.ID)).Expect().Status(http.StatusBadRequest)
+
+	config.C.General.DenyDeleteMenu = false
+	as.Len(reconcile(false), 3)
+	as.Nil(getMenu("reconcile"))
+	as.Nil(getMenu("reconcile-target"))
+	as.False(enforce("/api/v1/reconcile/items", "GET"))
+
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).Expect().Status(http.StatusOK)
+}
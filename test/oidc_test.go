@@ -0,0 +1,172 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/oidc/oidctest"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// ssoLogin Start the login at the API, sign in at the mock provider and return the code and state passed back to
+// the redirect URL along with the state cookie.
+func ssoLogin(t *testing.T, e *httpexpect.Expect, provider string) (url.Values, string) {
+	resp := e.GET(baseAPI + "/login/oidc/" + provider).Expect().Status(http.StatusFound)
+	state := resp.Cookie("oidc_state").Value().Raw()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	providerResp, err := client.Get(resp.Header("Location").Raw())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	providerResp.Body.Close()
+	assert.Equal(t, http.StatusFound, providerResp.StatusCode)
+
+	redirectURL, err := url.Parse(providerResp.Header.Get("Location"))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return redirectURL.Query(), state
+}
+
+func ssoCallback(e *httpexpect.Expect, provider string, query url.Values, state string) *httpexpect.Response {
+	req := e.GET(baseAPI+"/login/oidc/"+provider+"/callback").WithHeader("User-Agent", testUserAgent)
+	for k := range query {
+		req = req.WithQuery(k, query.Get(k))
+	}
+	if state != "" {
+		req = req.WithCookie("oidc_state", state)
+	}
+	return req.Expect()
+}
+
+func TestOIDC(t *testing.T) {
+	e := httpexpect.WithConfig(httpexpect.Config{
+		Client: &http.Client{
+			Transport:     httpexpect.NewBinder(app),
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		Reporter: httpexpect.NewAssertReporter(t),
+		Printers: []httpexpect.Printer{
+			httpexpect.NewDebugPrinter(t, true),
+		},
+	})
+	as := assert.New(t)
+
+	server := oidctest.NewServer("admin", "secret")
+	defer server.Close()
+
+	oidcCfg := config.C.Util.OIDC
+	defer func() { config.C.Util.OIDC = oidcCfg }()
+	config.C.Util.OIDC.Providers = []config.OIDCProvider{{
+		Name:         "test",
+		Issuer:       server.URL,
+		ClientID:     "admin",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:8040/sso/callback",
+		RoleMapping:  map[string]string{"admins": "sso-admin"},
+		DefaultRoles: []string{"sso-user"},
+		AutoCreate:   true,
+		SyncRoles:    true,
+	}}
+
+	var adminRole, userRole schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{Code: "sso-admin", Name: "SSO admin", Status: schema.RoleStatusEnabled}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &adminRole})
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{Code: "sso-user", Name: "SSO user", Status: schema.RoleStatusEnabled}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &userRole})
+
+	e.GET(baseAPI + "/login/oidc/unknown").Expect().Status(http.StatusNotFound)
+
+	// First login provisions the user with the mapped and default roles
+	server.SetUser(map[string]interface{}{
+		"sub":                "alice-1",
+		"preferred_username": "alice",
+		"name":               "Alice",
+		"email":              "alice@example.com",
+		"groups":             []string{"admins", "staff"},
+	})
+	query, state := ssoLogin(t, e, "test")
+	as.Equal(state, query.Get("state"))
+	as.NotEmpty(query.Get("code"))
+
+	var token schema.LoginToken
+	ssoCallback(e, "test", query, state).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	as.NotEmpty(token.AccessToken)
+
+	var user schema.User
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", "Bearer "+token.AccessToken).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+	as.Equal("alice", user.Username)
+	as.Equal("Alice", user.Name)
+	as.Equal("alice@example.com", user.Email)
+	as.ElementsMatch([]int64{adminRole.ID, userRole.ID}, user.Roles.ToRoleIDs())
+
+	// The state can only be used once
+	ssoCallback(e, "test", query, state).Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Object().Value("id").IsEqual(config.ErrInvalidSSOStateID)
+
+	// The state must be the one of the browser
+	query, _ = ssoLogin(t, e, "test")
+	ssoCallback(e, "test", query, "").Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Object().Value("id").IsEqual(config.ErrInvalidSSOStateID)
+
+	// Next login links the same user and synchronizes the roles, tokens of the old roles are revoked
+	server.SetUser(map[string]interface{}{
+		"sub":                "alice-1",
+		"preferred_username": "alice-renamed",
+		"groups":             []string{"staff"},
+	})
+	query, state = ssoLogin(t, e, "test")
+	var token2 schema.LoginToken
+	ssoCallback(e, "test", query, state).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token2})
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", "Bearer "+token.AccessToken).
+		Expect().Status(http.StatusUnauthorized)
+
+	user = schema.User{}
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", "Bearer "+token2.AccessToken).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+	as.Equal("alice", user.Username)
+	as.Equal([]int64{userRole.ID}, user.Roles.ToRoleIDs())
+
+	// Existing accounts are not taken over by the username
+	password := hash.MD5String("bob")
+	e.POST(baseAPI + "/users").WithJSON(schema.UserForm{
+		Username: "bob",
+		Name:     "Bob",
+		Password: password,
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{},
+	}).Expect().Status(http.StatusOK)
+	server.SetUser(map[string]interface{}{"sub": "bob-1", "preferred_username": "bob"})
+	query, state = ssoLogin(t, e, "test")
+	ssoCallback(e, "test", query, state).Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Object().Value("id").IsEqual(config.ErrSSOLoginFailedID)
+
+	// Unknown users are rejected without provisioning
+	config.C.Util.OIDC.Providers[0].AutoCreate = false
+	server.SetUser(map[string]interface{}{"sub": "carol-1", "preferred_username": "carol"})
+	query, state = ssoLogin(t, e, "test")
+	ssoCallback(e, "test", query, state).Status(http.StatusForbidden)
+
+	// Denied at the provider
+	server.SetUser(nil)
+	query, state = ssoLogin(t, e, "test")
+	as.Equal("access_denied", query.Get("error"))
+	ssoCallback(e, "test", query, state).Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Object().Value("id").IsEqual(config.ErrSSOLoginFailedID)
+
+	// Deleting the user removes the linked identity
+	e.DELETE(fmt.Sprintf("%s%s%d", baseAPI, "/users/", user.ID)).Expect().Status(http.StatusOK)
+	server.SetUser(map[string]interface{}{"sub": "alice-1", "preferred_username": "alice"})
+	query, state = ssoLogin(t, e, "test")
+	ssoCallback(e, "test", query, state).Status(http.StatusForbidden)
+}
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+var resetTokenRegexp = regexp.MustCompile(`token=([0-9A-Za-z]+)`)
+
+func forgotPassword(e *httpexpect.Expect, email string) *httpexpect.Response {
+	var captchaItem schema.Captcha
+	e.GET(baseAPI + "/captcha/id").Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &captchaItem})
+
+	return e.POST(baseAPI + "/password/forgot").WithJSON(schema.PasswordForgotForm{
+		Email:       email,
+		CaptchaID:   captchaItem.CaptchaID,
+		CaptchaCode: captchaCode(captchaItem.CaptchaID),
+	}).Expect()
+}
+
+func TestPasswordReset(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	smtp := newFakeSMTP(t)
+	mailCfg, resetCfg, policy := config.C.Util.Mail, config.C.Util.PasswordReset, config.C.General.PasswordPolicy
+	defer func() {
+		config.C.Util.Mail, config.C.Util.PasswordReset, config.C.General.PasswordPolicy = mailCfg, resetCfg, policy
+	}()
+	config.C.Util.Mail.SmtpHost = "127.0.0.1"
+	config.C.Util.Mail.Port = smtp.Port()
+	config.C.Util.Mail.FromMail = "noreply@example.com"
+	config.C.Util.PasswordReset.URL = "https://admin.example.com/#/reset-password"
+
+	password := hash.MD5String("reset")
+	e.POST(baseAPI + "/users").WithJSON(schema.UserForm{
+		Username: "reset",
+		Name:     "Password reset",
+		Password: password,
+		Email:    "Reset@Example.com",
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{},
+	}).Expect().Status(http.StatusOK)
+
+	var token schema.LoginToken
+	login(e, "reset", password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+
+	// Unknown emails get the same response without an email
+	forgotPassword(e, "nobody@example.com").Status(http.StatusOK)
+	as.True(smtp.Empty())
+
+	e.POST(baseAPI + "/password/forgot").WithJSON(schema.PasswordForgotForm{
+		Email: "reset@example.com", CaptchaID: "invalid", CaptchaCode: "0000",
+	}).Expect().Status(http.StatusBadRequest)
+
+	forgotPassword(e, "reset@example.com").Status(http.StatusOK)
+	to, body := smtp.Receive(t)
+	as.Contains(to, "Reset@Example.com")
+	as.Contains(body, "https://admin.example.com/#/reset-password?token=")
+	match := resetTokenRegexp.FindStringSubmatch(body)
+	as.Len(match, 2)
+	resetToken := match[1]
+
+	e.POST(baseAPI + "/password/reset").WithJSON(schema.PasswordResetForm{Token: "invalid", Password: "new"}).
+		Expect().Status(http.StatusBadRequest).
+		JSON().Object().Value("error").Object().Value("id").IsEqual(config.ErrInvalidResetTokenID)
+
+	newPassword := hash.MD5String("reset-new")
+	e.POST(baseAPI + "/password/reset").WithJSON(schema.PasswordResetForm{Token: resetToken, Password: newPassword}).
+		Expect().Status(http.StatusOK)
+
+	// The token can only be used once and the issued tokens are revoked
+	e.POST(baseAPI + "/password/reset").WithJSON(schema.PasswordResetForm{Token: resetToken, Password: password}).
+		Expect().Status(http.StatusBadRequest)
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", "Bearer "+token.AccessToken).
+		Expect().Status(http.StatusUnauthorized)
+	login(e, "reset", password).Status(http.StatusBadRequest)
+	login(e, "reset", newPassword).Status(http.StatusOK)
+
+	// A rejected password keeps the token, a password change invalidates it
+	config.C.General.PasswordPolicy.MinLength = 64
+	forgotPassword(e, "reset@example.com").Status(http.StatusOK)
+	_, body = smtp.Receive(t)
+	resetToken = resetTokenRegexp.FindStringSubmatch(body)[1]
+	e.POST(baseAPI + "/password/reset").WithJSON(schema.PasswordResetForm{Token: resetToken, Password: "short"}).
+		Expect().Status(http.StatusBadRequest)
+	config.C.General.PasswordPolicy = policy
+
+	login(e, "reset", newPassword).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	e.PUT(baseAPI+"/current/password").WithHeader("Authorization", "Bearer "+token.AccessToken).
+		WithJSON(schema.UpdateLoginPassword{OldPassword: newPassword, NewPassword: password}).
+		Expect().Status(http.StatusOK)
+	e.POST(baseAPI + "/password/reset").WithJSON(schema.PasswordResetForm{Token: resetToken, Password: newPassword}).
+		Expect().Status(http.StatusBadRequest)
+
+	// Custom template
+	tmplFile := filepath.Join(t.TempDir(), "reset.html")
+	as.Nil(os.WriteFile(tmplFile, []byte(`<p>{{.Username}}: {{.Token}}</p>`), 0644))
+	config.C.Util.PasswordReset.TemplateFile = tmplFile
+	forgotPassword(e, "reset@example.com").Status(http.StatusOK)
+	_, body = smtp.Receive(t)
+	as.Regexp(`^<p>reset: [0-9A-Za-z]+</p>$`, body)
+
+	// A broken template fails the same way whether the email exists or not
+	as.Nil(os.WriteFile(tmplFile, []byte(`<p>{{.Username</p>`), 0644))
+	forgotPassword(e, "nobody@example.com").Status(http.StatusInternalServerError)
+	forgotPassword(e, "reset@example.com").Status(http.StatusInternalServerError)
+	as.True(smtp.Empty())
+}
@@ -0,0 +1,117 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/errors"
+	"github.com/supermicah/go-framework-admin/pkg/password"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func fieldCodes(fields []*errors.FieldError) []string {
+	codes := make([]string, len(fields))
+	for i, f := range fields {
+		codes[i] = f.Field + ":" + f.Code
+	}
+	return codes
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	policy := config.C.General.PasswordPolicy
+	defer func() { config.C.General.PasswordPolicy = policy }()
+	config.C.General.PasswordPolicy.MinLength = 8
+	config.C.General.PasswordPolicy.RequireUpper = true
+	config.C.General.PasswordPolicy.RequireLower = true
+	config.C.General.PasswordPolicy.RequireDigit = true
+	config.C.General.PasswordPolicy.DenyCommon = true
+	config.C.General.PasswordPolicy.HistorySize = 2
+
+	userForm := schema.UserForm{
+		Username: "policy",
+		Name:     "Password policy",
+		Password: "abc",
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{},
+	}
+	var errResult errors.Error
+	e.POST(baseAPI + "/users").WithJSON(userForm).Expect().Status(http.StatusBadRequest).
+		JSON().Decode(&util.ResponseResult{Error: &errResult})
+	as.Equal(config.ErrInvalidPasswordID, errResult.ID)
+	as.Equal([]string{"password:" + password.CodeTooShort, "password:" + password.CodeMissingUpper,
+		"password:" + password.CodeMissingDigit}, fieldCodes(errResult.Fields))
+
+	userForm.Password = "Password1"
+	e.POST(baseAPI + "/users").WithJSON(userForm).Expect().Status(http.StatusBadRequest)
+
+	var user schema.User
+	userForm.Password = "Strong-Pass1"
+	e.POST(baseAPI + "/users").WithJSON(userForm).Expect().Status(http.StatusOK).
+		JSON().Decode(&util.ResponseResult{Data: &user})
+
+	var token schema.LoginToken
+	login(e, "policy", "Strong-Pass1").Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	as.False(token.PasswordExpired)
+	auth := "Bearer " + token.AccessToken
+
+	// The current and the recent passwords can not be reused
+	errResult = errors.Error{}
+	e.PUT(baseAPI+"/current/password").WithHeader("Authorization", auth).
+		WithJSON(schema.UpdateLoginPassword{OldPassword: "Strong-Pass1", NewPassword: "Strong-Pass1"}).
+		Expect().Status(http.StatusBadRequest).JSON().Decode(&util.ResponseResult{Error: &errResult})
+	as.Equal([]string{"new_password:reused"}, fieldCodes(errResult.Fields))
+	e.PUT(baseAPI+"/current/password").WithHeader("Authorization", auth).
+		WithJSON(schema.UpdateLoginPassword{OldPassword: "Strong-Pass1", NewPassword: "Strong-Pass2"}).
+		Expect().Status(http.StatusOK)
+
+	login(e, "policy", "Strong-Pass2").Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	auth = "Bearer " + token.AccessToken
+	e.PUT(baseAPI+"/current/password").WithHeader("Authorization", auth).
+		WithJSON(schema.UpdateLoginPassword{OldPassword: "Strong-Pass2", NewPassword: "Strong-Pass1"}).
+		Expect().Status(http.StatusBadRequest)
+	e.PUT(baseAPI+"/current/password").WithHeader("Authorization", auth).
+		WithJSON(schema.UpdateLoginPassword{OldPassword: "Strong-Pass2", NewPassword: "weak"}).
+		Expect().Status(http.StatusBadRequest)
+
+	// A reset password must be changed at next login
+	resetURL := fmt.Sprintf("%s/users/%d/reset-pwd", baseAPI, user.ID)
+	e.PATCH(resetURL).WithJSON(schema.ResetPasswordForm{Password: "Strong-Pass2"}).Expect().Status(http.StatusBadRequest)
+	e.PATCH(resetURL).WithJSON(schema.ResetPasswordForm{Password: "Strong-Pass3"}).Expect().Status(http.StatusOK)
+
+	login(e, "policy", "Strong-Pass3").Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	as.True(token.PasswordExpired)
+	auth = "Bearer " + token.AccessToken
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", auth).Expect().Status(http.StatusOK)
+	e.GET(baseAPI+"/current/menus").WithHeader("Authorization", auth).Expect().Status(http.StatusForbidden).
+		JSON().Object().Value("error").Object().Value("id").IsEqual(config.ErrPasswordExpiredID)
+	e.PUT(baseAPI+"/current/password").WithHeader("Authorization", auth).
+		WithJSON(schema.UpdateLoginPassword{OldPassword: "Strong-Pass3", NewPassword: "Strong-Pass4"}).
+		Expect().Status(http.StatusOK)
+
+	token = schema.LoginToken{}
+	login(e, "policy", "Strong-Pass4").Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	as.False(token.PasswordExpired)
+	e.GET(baseAPI+"/current/menus").WithHeader("Authorization", "Bearer "+token.AccessToken).Expect().Status(http.StatusOK)
+
+	// Reset to the default password without a body
+	e.PATCH(resetURL).Expect().Status(http.StatusOK)
+	login(e, "policy", config.C.General.DefaultLoginPwd).Status(http.StatusOK).
+		JSON().Decode(&util.ResponseResult{Data: &token})
+	as.True(token.PasswordExpired)
+
+	// Passwords older than the maximum age expire
+	config.C.General.PasswordPolicy.MaxAge = 1
+	expired := &schema.User{CreatedAt: user.CreatedAt.AddDate(0, 0, -2)}
+	as.True(expired.IsPasswordExpired())
+	as.False((&schema.User{CreatedAt: user.CreatedAt}).IsPasswordExpired())
+
+	e.DELETE(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).Expect().Status(http.StatusOK)
+}
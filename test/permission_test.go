@@ -0,0 +1,112 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestPermission(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	var menu schema.Menu
+	e.POST(baseAPI + "/menus").WithJSON(schema.MenuForm{
+		Code:   "permission",
+		Name:   "Permission",
+		Type:   "page",
+		Status: schema.MenuStatusEnabled,
+		Resources: schema.MenuResources{
+			{Method: "GET", Path: "/api/v1/permission-items"},
+			{Method: "GET", Path: "/api/v1/permission-items/:id"},
+			{Method: "DELETE", Path: "/api/v1/permission-items/:id"},
+		},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menu})
+
+	var parent schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{
+		Code:   "permission-parent",
+		Name:   "Permission parent",
+		Status: schema.RoleStatusEnabled,
+		Menus:  schema.RoleMenus{{MenuID: menu.ID}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &parent})
+	var role schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{
+		Code:      "permission-child",
+		Name:      "Permission child",
+		Status:    schema.RoleStatusEnabled,
+		ParentIDs: []int64{parent.ID},
+		Resources: schema.RoleResources{
+			{Method: "DELETE", Path: "/api/v1/permission-items/:id", Effect: schema.ResourceEffectDeny},
+		},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+
+	var user schema.User
+	e.POST(baseAPI + "/users").WithJSON(schema.UserForm{
+		Username: "permission",
+		Name:     "Permission",
+		Password: "test123456",
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{{RoleID: role.ID}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+
+	check := func(form schema.PermissionCheckForm) *schema.PermissionCheck {
+		result := new(schema.PermissionCheck)
+		e.POST(baseAPI + "/permissions/check").WithJSON(form).
+			Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: result})
+		return result
+	}
+
+	// Allowed by a menu of the parent role
+	result := check(schema.PermissionCheckForm{UserID: user.ID, Method: "get", Path: "/api/v1/permission-items/1"})
+	as.True(result.Allowed)
+	as.Equal(schema.ResourceEffectAllow, result.Effect)
+	as.Equal(role.ID, result.SubjectID)
+	if as.NotNil(result.Role) {
+		as.Equal(parent.ID, result.Role.ID)
+	}
+	if as.NotNil(result.Menu) && as.NotNil(result.MenuResource) {
+		as.Equal(menu.ID, result.Menu.ID)
+		as.Equal("/api/v1/permission-items/:id", result.MenuResource.Path)
+	}
+
+	// Denied by a resource of the role
+	result = check(schema.PermissionCheckForm{UserID: user.ID, Method: "DELETE", Path: "/api/v1/permission-items/1"})
+	as.False(result.Allowed)
+	as.Equal(schema.ResourceEffectDeny, result.Effect)
+	if as.NotNil(result.RoleResource) {
+		as.Equal(role.ID, result.RoleResource.RoleID)
+	}
+	as.Nil(result.MenuResource)
+
+	// Not granted at all
+	result = check(schema.PermissionCheckForm{RoleIDs: []int64{parent.ID}, Method: "POST", Path: "/api/v1/permission-items"})
+	as.False(result.Allowed)
+	as.Empty(result.Policy)
+
+	result = check(schema.PermissionCheckForm{RoleIDs: []int64{parent.ID}, Method: "DELETE", Path: "/api/v1/permission-items/1"})
+	as.True(result.Allowed)
+
+	e.POST(baseAPI + "/permissions/check").WithJSON(schema.PermissionCheckForm{Method: "GET", Path: "/"}).
+		Expect().Status(http.StatusBadRequest)
+
+	var permissions schema.Permissions
+	e.GET(fmt.Sprintf("%s/users/%d/permissions", baseAPI, user.ID)).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &permissions})
+	if as.Len(permissions, 2) {
+		as.Equal("GET", permissions[0].Method)
+		as.Equal("/api/v1/permission-items", permissions[0].Path)
+		as.Equal("/api/v1/permission-items/:id", permissions[1].Path)
+		as.Equal([]int64{role.ID}, permissions[1].RoleIDs)
+	}
+
+	e.DELETE(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, parent.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/menus/%d", baseAPI, menu.ID)).Expect().Status(http.StatusOK)
+}
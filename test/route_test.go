@@ -0,0 +1,75 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestRoute(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	var routes schema.Routes
+	e.GET(baseAPI+"/menus/resources/routes").WithQuery("path", "/api/v1/menus/").
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &routes})
+	found := false
+	for _, route := range routes {
+		as.Contains(route.Path, "/api/v1/menus/")
+		if route.Method == "GET" && route.Path == "/api/v1/menus/:id" {
+			found = true
+			as.Equal("api.(*Menu).Get", route.Handler)
+			as.True(route.Protected)
+		}
+	}
+	as.True(found)
+
+	report := func() *schema.RouteReport {
+		result := new(schema.RouteReport)
+		e.GET(baseAPI + "/menus/resources/report").
+			Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: result})
+		return result
+	}
+	uncovered := func(result *schema.RouteReport, method, path string) bool {
+		for _, route := range result.UncoveredRoutes {
+			if route.Method == method && route.Path == path {
+				return true
+			}
+		}
+		return false
+	}
+	result := report()
+	as.True(uncovered(result, "GET", "/api/v1/menus/resources/report"))
+	as.True(uncovered(result, "DELETE", "/api/v1/menus/:id"))
+
+	var menu schema.Menu
+	e.POST(baseAPI + "/menus").WithJSON(schema.MenuForm{
+		Code:   "route",
+		Name:   "Route",
+		Type:   "page",
+		Status: schema.MenuStatusEnabled,
+		Resources: schema.MenuResources{
+			{Method: "GET", Path: "/api/v1/menus/resources/report"},
+			{Method: "DELETE", Path: "/api/v1/menus/{id}", Effect: schema.ResourceEffectDeny},
+			{Method: "GET", Path: "/api/v1/route-items"},
+		},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menu})
+
+	// The denied routes are still uncovered
+	result = report()
+	as.False(result.Valid())
+	as.False(uncovered(result, "GET", "/api/v1/menus/resources/report"))
+	as.True(uncovered(result, "DELETE", "/api/v1/menus/:id"))
+	if as.Len(result.StaleResources, 1) {
+		as.Equal(menu.ID, result.StaleResources[0].MenuID)
+		as.Equal("route", result.StaleResources[0].CodePath)
+		as.Equal("/api/v1/route-items", result.StaleResources[0].Path)
+	}
+
+	e.DELETE(fmt.Sprintf("%s/menus/%d", baseAPI, menu.ID)).Expect().Status(http.StatusOK)
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestSession(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	var role schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{
+		Code:   "session",
+		Name:   "Session",
+		Status: schema.RoleStatusEnabled,
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+	as.NotEmpty(role.ID)
+
+	userFormItem := schema.UserForm{
+		Username: "session",
+		Name:     "Session",
+		Password: hash.MD5String("session"),
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{{RoleID: role.ID}},
+	}
+
+	var user schema.User
+	e.POST(baseAPI + "/users").WithJSON(userFormItem).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+	as.NotEmpty(user.ID)
+
+	var token1, token2 schema.LoginToken
+	login(e, userFormItem.Username, userFormItem.Password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token1})
+	login(e, userFormItem.Username, userFormItem.Password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token2})
+
+	sessionsAPI := fmt.Sprintf("%s/users/%d/sessions", baseAPI, user.ID)
+	var sessions schema.Sessions
+	e.GET(sessionsAPI).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &sessions})
+	as.Len(sessions, 2)
+	for _, item := range sessions {
+		as.NotEmpty(item.ID)
+		as.NotEmpty(item.TokenID)
+		as.Equal(user.ID, item.UserID)
+		as.Equal(testUserAgent, item.UserAgent)
+	}
+
+	// Refreshing keeps the session and updates its token
+	var refreshed schema.LoginToken
+	e.POST(baseAPI + "/current/refresh-token").WithJSON(schema.RefreshTokenForm{RefreshToken: token1.RefreshToken}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &refreshed})
+
+	var refreshedSessions schema.Sessions
+	e.GET(sessionsAPI).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &refreshedSessions})
+	as.Len(refreshedSessions, 2)
+
+	// Revoke every session, the refresh tokens become invalid
+	for _, item := range refreshedSessions {
+		e.DELETE(fmt.Sprintf("%s/%s", sessionsAPI, item.ID)).Expect().Status(http.StatusOK)
+	}
+	e.DELETE(fmt.Sprintf("%s/%s", sessionsAPI, sessions[0].ID)).Expect().Status(http.StatusNotFound)
+
+	e.GET(sessionsAPI).Expect().Status(http.StatusOK).JSON().Object().Value("data").Array().IsEmpty()
+	e.POST(baseAPI + "/current/refresh-token").WithJSON(schema.RefreshTokenForm{RefreshToken: refreshed.RefreshToken}).
+		Expect().Status(http.StatusUnauthorized)
+	e.POST(baseAPI + "/current/refresh-token").WithJSON(schema.RefreshTokenForm{RefreshToken: token2.RefreshToken}).
+		Expect().Status(http.StatusUnauthorized)
+
+	e.DELETE(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).Expect().Status(http.StatusOK)
+
+	// The sessions of an unknown user are not found
+	e.GET(sessionsAPI).Expect().Status(http.StatusNotFound)
+	e.DELETE(fmt.Sprintf("%s/%s", sessionsAPI, sessions[0].ID)).Expect().Status(http.StatusNotFound)
+}
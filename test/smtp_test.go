@@ -0,0 +1,117 @@
+package test
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTP A local SMTP server accepting every message without authentication
+type fakeSMTP struct {
+	listener net.Listener
+	messages chan *mail.Message
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &fakeSMTP{listener: l, messages: make(chan *mail.Message, 16)}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { _ = l.Close() })
+	return s
+}
+
+func (s *fakeSMTP) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, _ = io.WriteString(conn, line+"\r\n")
+	}
+
+	reply("220 localhost fake SMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			if msg, err := mail.ReadMessage(strings.NewReader(data.String())); err == nil {
+				s.messages <- msg
+			}
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// Receive Wait for the next message and return its recipient and decoded body.
+func (s *fakeSMTP) Receive(t *testing.T) (string, string) {
+	select {
+	case msg := <-s.messages:
+		body, err := io.ReadAll(msg.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.Header.Get("Content-Transfer-Encoding") == "base64" {
+			body, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(body)), ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return msg.Header.Get("To"), string(body)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no email received")
+	}
+	return "", ""
+}
+
+// Empty Reports whether no message arrives for a while.
+func (s *fakeSMTP) Empty() bool {
+	select {
+	case <-s.messages:
+		return false
+	case <-time.After(300 * time.Millisecond):
+		return true
+	}
+}
@@ -0,0 +1,133 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestTenant(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	tenantForm := schema.TenantForm{
+		Code:   "acme",
+		Name:   "ACME",
+		Status: schema.TenantStatusEnabled,
+	}
+	var tenant schema.Tenant
+	e.POST(baseAPI + "/tenants").WithJSON(tenantForm).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &tenant})
+	as.NotEmpty(tenant.ID)
+	e.POST(baseAPI + "/tenants").WithJSON(tenantForm).Expect().Status(http.StatusBadRequest)
+	e.POST(baseAPI + "/tenants").WithJSON(schema.TenantForm{Code: "Bad Code", Name: "Bad", Status: schema.TenantStatusEnabled}).
+		Expect().Status(http.StatusBadRequest)
+
+	menuForm := schema.MenuForm{
+		Code:      "tenant",
+		Name:      "Tenant",
+		Type:      "page",
+		Status:    schema.MenuStatusEnabled,
+		Resources: schema.MenuResources{{Method: "GET", Path: "/api/v1/tenant-items"}},
+	}
+	var menu schema.Menu
+	e.POST(baseAPI + "/menus").WithJSON(menuForm).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &menu})
+
+	// The data created with the tenant header belongs to the tenant
+	var role schema.Role
+	e.POST(baseAPI+"/roles").WithHeader(tenantHeaderKey, tenant.Code).WithJSON(schema.RoleForm{
+		Code:   "tenant",
+		Name:   "Tenant",
+		Status: schema.RoleStatusEnabled,
+		Menus:  schema.RoleMenus{{MenuID: menu.ID}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+	as.Equal(tenant.ID, role.TenantID)
+
+	password := hash.MD5String("tenant")
+	var user schema.User
+	e.POST(baseAPI+"/users").WithHeader(tenantHeaderKey, tenant.Code).WithJSON(schema.UserForm{
+		Username: "tenant",
+		Name:     "Tenant",
+		Password: password,
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{{RoleID: role.ID}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+	as.Equal(tenant.ID, user.TenantID)
+
+	// The policies of the role are in the domain of its tenant
+	enforce := func(dom string) bool {
+		ok, err := casbinx.GetEnforcer().Enforce(strconv.FormatInt(role.ID, 10), dom, "/api/v1/tenant-items", "GET")
+		as.Nil(err)
+		return ok
+	}
+	as.True(enforce(strconv.FormatInt(tenant.ID, 10)))
+	as.False(enforce("0"))
+
+	// The roles of the tenant are only visible in the tenant
+	var roles schema.Roles
+	e.GET(baseAPI+"/roles").WithHeader(tenantHeaderKey, tenant.Code).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &roles})
+	if as.Len(roles, 1) {
+		as.Equal(role.ID, roles[0].ID)
+	}
+	e.GET(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).Expect().Status(http.StatusOK)
+	e.GET(baseAPI+"/roles").WithHeader(tenantHeaderKey, "unknown").Expect().Status(http.StatusBadRequest)
+
+	// The users sign in to their tenant and are scoped to it whatever the header
+	login(e, "tenant", password).Status(http.StatusBadRequest)
+	var token schema.LoginToken
+	loginRequest(e, "tenant", password).WithHeader(tenantHeaderKey, tenant.Code).Expect().
+		Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	auth := "Bearer " + token.AccessToken
+
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", auth).Expect().Status(http.StatusOK)
+
+	// The sessions of the user are only managed in its tenant
+	var otherTenant schema.Tenant
+	e.POST(baseAPI + "/tenants").WithJSON(schema.TenantForm{Code: "globex", Name: "Globex", Status: schema.TenantStatusEnabled}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &otherTenant})
+	sessionsAPI := fmt.Sprintf("%s/users/%d/sessions", baseAPI, user.ID)
+	e.GET(sessionsAPI).WithHeader(tenantHeaderKey, otherTenant.Code).Expect().Status(http.StatusNotFound)
+	var sessions schema.Sessions
+	e.GET(sessionsAPI).WithHeader(tenantHeaderKey, tenant.Code).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &sessions})
+	if as.Len(sessions, 1) {
+		e.DELETE(fmt.Sprintf("%s/%s", sessionsAPI, sessions[0].ID)).WithHeader(tenantHeaderKey, otherTenant.Code).
+			Expect().Status(http.StatusNotFound)
+	}
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", auth).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/tenants/%d", baseAPI, otherTenant.ID)).Expect().Status(http.StatusOK)
+
+	e.GET(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).WithHeader("Authorization", auth).Expect().Status(http.StatusOK)
+	var other schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{Code: "tenant-other", Name: "Other", Status: schema.RoleStatusEnabled}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &other})
+	as.Zero(other.TenantID)
+	e.GET(fmt.Sprintf("%s/roles/%d", baseAPI, other.ID)).WithHeader("Authorization", auth).
+		Expect().Status(http.StatusNotFound)
+
+	// Only the users across tenants manage the tenants
+	e.GET(baseAPI+"/tenants").WithHeader("Authorization", auth).Expect().Status(http.StatusForbidden)
+	e.DELETE(fmt.Sprintf("%s/tenants/%d", baseAPI, tenant.ID)).Expect().Status(http.StatusBadRequest)
+
+	// The users of a disabled tenant are signed out
+	tenantForm.Status = schema.TenantStatusDisabled
+	e.PUT(fmt.Sprintf("%s/tenants/%d", baseAPI, tenant.ID)).WithJSON(tenantForm).Expect().Status(http.StatusOK)
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", auth).Expect().Status(http.StatusUnauthorized)
+	e.GET(baseAPI+"/roles").WithHeader(tenantHeaderKey, tenant.Code).Expect().Status(http.StatusBadRequest)
+
+	e.DELETE(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, other.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/menus/%d", baseAPI, menu.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/tenants/%d", baseAPI, tenant.ID)).Expect().Status(http.StatusOK)
+	e.GET(fmt.Sprintf("%s/tenants/%d", baseAPI, tenant.ID)).Expect().Status(http.StatusNotFound)
+}
@@ -2,27 +2,49 @@ package test
 
 import (
 	"context"
+	"math"
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/LyricTian/captcha"
+	"github.com/LyricTian/captcha/store"
 	"github.com/gavv/httpexpect/v2"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 
 	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/biz"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
 	"github.com/supermicah/go-framework-admin/internal/wirex"
+	"github.com/supermicah/go-framework-admin/pkg/cachex"
+	"github.com/supermicah/go-framework-admin/pkg/middleware"
+	"github.com/supermicah/go-framework-admin/pkg/util"
 )
 
 const (
-	baseAPI = "/api/v1"
+	baseAPI         = "/api/v1"
+	testUserAgent   = "go-framework-admin/test"
+	tenantHeaderKey = "X-Tenant"
 )
 
 var (
-	app *gin.Engine
+	app          *gin.Engine
+	db           *gorm.DB
+	cache        cachex.Cacher
+	casbinx      *biz.Casbinx
+	events       *biz.Event
+	userRoles    *biz.UserRoleSweeper
+	captchaStore = store.NewMemoryStore(time.Minute, captcha.Expiration)
 )
 
 func init() {
 	config.MustLoad("")
+	// Keep the root ID out of the range of the auto increment IDs of the test database
+	config.C.General.Root.ID = math.MaxInt64
+	config.C.Middleware.Casbin.ModelFile = "../configs/rbac_model.conf"
+	captcha.SetCustomStore(captchaStore)
 
 	_ = os.RemoveAll(config.C.Storage.DB.DSN)
 	ctx := context.Background()
@@ -34,12 +56,29 @@ func init() {
 	if err := injector.M.Init(ctx); err != nil {
 		panic(err)
 	}
+	db = injector.DB
+	cache = injector.Cache
+	casbinx = injector.M.RBAC.Casbinx
+	events = injector.M.RBAC.Event
+	userRoles = injector.M.RBAC.UserRoleSweeper
 
 	app = gin.New()
+	app.Use(middleware.TenantWithConfig(middleware.TenantConfig{
+		Enable:      true,
+		HeaderKey:   tenantHeaderKey,
+		GetTenantID: injector.M.RBAC.TenantAPI.TenantBIZ.GetTenantID,
+	}))
+	// Requests without credentials are served anonymously, the others are authenticated like in production
+	app.Use(middleware.AuthWithConfig(middleware.AuthConfig{
+		RootID:      config.C.General.Root.ID,
+		Skipper:     func(c *gin.Context) bool { return util.GetToken(c) == "" },
+		ParseUserID: injector.M.RBAC.LoginAPI.LoginBIZ.ParseUserID,
+	}))
 	err = injector.M.RegisterRouters(ctx, app)
 	if err != nil {
 		panic(err)
 	}
+	injector.M.RBAC.RouteAPI.RouteBIZ.SetRoutes(app.Routes(), injector.M.RouterPrefixes())
 }
 
 func tester(t *testing.T) *httpexpect.Expect {
@@ -54,3 +93,30 @@ func tester(t *testing.T) *httpexpect.Expect {
 		},
 	})
 }
+
+// captchaCode Solve the captcha from the store, the digits are kept as raw numbers
+func captchaCode(id string) string {
+	digits := captchaStore.Get(id, false)
+	code := make([]byte, len(digits))
+	for i, d := range digits {
+		code[i] = d + '0'
+	}
+	return string(code)
+}
+
+func login(e *httpexpect.Expect, username, password string) *httpexpect.Response {
+	return loginRequest(e, username, password).Expect()
+}
+
+// loginRequest Build the login request with a solved captcha
+func loginRequest(e *httpexpect.Expect, username, password string) *httpexpect.Request {
+	var captchaItem schema.Captcha
+	e.GET(baseAPI + "/captcha/id").Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &captchaItem})
+
+	return e.POST(baseAPI+"/login").WithHeader("User-Agent", testUserAgent).WithJSON(schema.LoginForm{
+		Username:    username,
+		Password:    password,
+		CaptchaID:   captchaItem.CaptchaID,
+		CaptchaCode: captchaCode(captchaItem.CaptchaID),
+	})
+}
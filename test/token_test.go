@@ -0,0 +1,79 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestTokenRevoke(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	var role, otherRole schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{Code: "token", Name: "Token", Status: schema.RoleStatusEnabled}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{Code: "token2", Name: "Token 2", Status: schema.RoleStatusEnabled}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &otherRole})
+
+	password := hash.MD5String("token")
+	userFormItem := schema.UserForm{
+		Username: "token",
+		Name:     "Token",
+		Password: password,
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{{RoleID: role.ID}},
+	}
+
+	var user schema.User
+	e.POST(baseAPI + "/users").WithJSON(userFormItem).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+	as.NotEmpty(user.ID)
+	userAPI := fmt.Sprintf("%s/users/%d", baseAPI, user.ID)
+
+	loginToken := func() schema.LoginToken {
+		var token schema.LoginToken
+		login(e, userFormItem.Username, password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+		return token
+	}
+	refresh := func(token schema.LoginToken) *httpexpect.Response {
+		return e.POST(baseAPI + "/current/refresh-token").WithJSON(schema.RefreshTokenForm{RefreshToken: token.RefreshToken}).Expect()
+	}
+
+	// Updating the profile only keeps the tokens
+	token := loginToken()
+	userFormItem.Name = "Token 1"
+	userFormItem.Password = ""
+	e.PUT(userAPI).WithJSON(userFormItem).Expect().Status(http.StatusOK)
+	var refreshed schema.LoginToken
+	refresh(token).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &refreshed})
+
+	// Changing roles
+	userFormItem.Roles = schema.UserRoles{{RoleID: otherRole.ID}}
+	e.PUT(userAPI).WithJSON(userFormItem).Expect().Status(http.StatusOK)
+	refresh(refreshed).Status(http.StatusUnauthorized)
+
+	// Freezing the user
+	token = loginToken()
+	userFormItem.Status = schema.UserStatusFreezed
+	e.PUT(userAPI).WithJSON(userFormItem).Expect().Status(http.StatusOK)
+	refresh(token).Status(http.StatusUnauthorized)
+	userFormItem.Status = schema.UserStatusActivated
+	e.PUT(userAPI).WithJSON(userFormItem).Expect().Status(http.StatusOK)
+
+	// Resetting the password
+	token = loginToken()
+	e.PATCH(userAPI + "/reset-pwd").Expect().Status(http.StatusOK)
+	refresh(token).Status(http.StatusUnauthorized)
+	e.GET(userAPI + "/sessions").Expect().Status(http.StatusOK).JSON().Object().Value("data").Array().IsEmpty()
+
+	e.DELETE(userAPI).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).Expect().Status(http.StatusOK)
+	e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, otherRole.ID)).Expect().Status(http.StatusOK)
+}
@@ -0,0 +1,166 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/crypto/hash"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+// totpCode Generate the code of the period at the offset from now, a code can only be accepted once
+func totpCode(t *testing.T, secret string, offset int) string {
+	code, err := totp.GenerateCode(secret, time.Now().Add(time.Duration(offset)*30*time.Second))
+	assert.Nil(t, err)
+	return code
+}
+
+func loginTwoFactor(e *httpexpect.Expect, challengeToken, code string) *httpexpect.Response {
+	return e.POST(baseAPI+"/login/2fa").WithHeader("User-Agent", testUserAgent).
+		WithJSON(schema.TwoFactorChallengeForm{ChallengeToken: challengeToken, Code: code}).Expect()
+}
+
+func TestTwoFactor(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	var role schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{Code: "twofactor", Name: "Two factor", Status: schema.RoleStatusEnabled}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+
+	password := hash.MD5String("twofactor")
+	var user schema.User
+	e.POST(baseAPI + "/users").WithJSON(schema.UserForm{
+		Username: "twofactor",
+		Name:     "Two factor",
+		Password: password,
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{{RoleID: role.ID}},
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+
+	var token schema.LoginToken
+	login(e, "twofactor", password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	as.NotEmpty(token.AccessToken)
+	as.Empty(token.ChallengeToken)
+	auth := "Bearer " + token.AccessToken
+
+	// Enroll
+	var setup schema.TwoFactorSetup
+	e.POST(baseAPI+"/current/2fa/setup").WithHeader("Authorization", auth).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &setup})
+	as.NotEmpty(setup.Secret)
+	as.True(strings.HasPrefix(setup.URI, "otpauth://totp/"))
+	as.True(strings.HasPrefix(setup.QRCode, "data:image/png;base64,"))
+
+	e.POST(baseAPI+"/current/2fa/enable").WithHeader("Authorization", auth).
+		WithJSON(schema.TwoFactorCodeForm{Code: "000000x"}).Expect().Status(http.StatusBadRequest)
+
+	var recovery schema.TwoFactorRecoveryCodes
+	e.POST(baseAPI+"/current/2fa/enable").WithHeader("Authorization", auth).
+		WithJSON(schema.TwoFactorCodeForm{Code: totpCode(t, setup.Secret, 0)}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &recovery})
+	as.Len(recovery.Codes, 10)
+
+	var current schema.User
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", auth).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &current})
+	as.True(current.TwoFactorEnabled)
+
+	// Login requires the second step
+	var challenge schema.LoginToken
+	login(e, "twofactor", password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &challenge})
+	as.Empty(challenge.AccessToken)
+	as.NotEmpty(challenge.ChallengeToken)
+	as.False(challenge.TwoFactorSetup)
+
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", "Bearer "+challenge.ChallengeToken).
+		Expect().Status(http.StatusUnauthorized)
+	loginTwoFactor(e, challenge.ChallengeToken, "").Status(http.StatusBadRequest)
+	// The code accepted by enabling can not be replayed
+	loginTwoFactor(e, challenge.ChallengeToken, totpCode(t, setup.Secret, 0)).Status(http.StatusBadRequest)
+
+	login(e, "twofactor", password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &challenge})
+	loginTwoFactor(e, challenge.ChallengeToken, totpCode(t, setup.Secret, 1)).
+		Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	as.NotEmpty(token.AccessToken)
+	as.Empty(token.RecoveryCodes)
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", "Bearer "+token.AccessToken).Expect().Status(http.StatusOK)
+
+	// The challenge can only be used once
+	loginTwoFactor(e, challenge.ChallengeToken, recovery.Codes[1]).Status(http.StatusUnauthorized)
+
+	// Recovery codes can be used once
+	login(e, "twofactor", password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &challenge})
+	loginTwoFactor(e, challenge.ChallengeToken, strings.ToUpper(recovery.Codes[0])).
+		Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	auth = "Bearer " + token.AccessToken
+
+	login(e, "twofactor", password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &challenge})
+	loginTwoFactor(e, challenge.ChallengeToken, recovery.Codes[0]).Status(http.StatusBadRequest)
+
+	// Too many incorrect codes drop the challenge and lock the login
+	for i := 1; i < 5; i++ {
+		loginTwoFactor(e, challenge.ChallengeToken, "000000").Status(http.StatusBadRequest)
+	}
+	loginTwoFactor(e, challenge.ChallengeToken, recovery.Codes[1]).Status(http.StatusUnauthorized)
+	login(e, "twofactor", password).Status(http.StatusTooManyRequests)
+	e.PATCH(fmt.Sprintf("%s/users/%d/unlock", baseAPI, user.ID)).Expect().Status(http.StatusOK)
+
+	// Disable
+	e.POST(baseAPI+"/current/2fa/disable").WithHeader("Authorization", auth).
+		WithJSON(schema.TwoFactorCodeForm{Code: recovery.Codes[1]}).Expect().Status(http.StatusOK)
+	login(e, "twofactor", password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	as.NotEmpty(token.AccessToken)
+
+	// Required by role, the user sets up two-factor during login
+	var requiredRole schema.Role
+	e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{
+		Code:             "twofactor2",
+		Name:             "Two factor required",
+		Status:           schema.RoleStatusEnabled,
+		RequireTwoFactor: true,
+	}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &requiredRole})
+	as.True(requiredRole.RequireTwoFactor)
+
+	password = hash.MD5String("twofactor2")
+	e.POST(baseAPI + "/users").WithJSON(schema.UserForm{
+		Username: "twofactor2",
+		Name:     "Two factor required",
+		Password: password,
+		Status:   schema.UserStatusActivated,
+		Roles:    schema.UserRoles{{RoleID: requiredRole.ID}},
+	}).Expect().Status(http.StatusOK)
+
+	login(e, "twofactor2", password).Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &challenge})
+	as.Empty(challenge.AccessToken)
+	as.True(challenge.TwoFactorSetup)
+
+	loginTwoFactor(e, challenge.ChallengeToken, "123456").Status(http.StatusBadRequest)
+	e.POST(baseAPI + "/login/2fa/setup").WithJSON(schema.TwoFactorChallengeForm{ChallengeToken: "invalid"}).
+		Expect().Status(http.StatusUnauthorized)
+	e.POST(baseAPI + "/login/2fa/setup").WithJSON(schema.TwoFactorChallengeForm{ChallengeToken: challenge.ChallengeToken}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &setup})
+
+	loginTwoFactor(e, challenge.ChallengeToken, totpCode(t, setup.Secret, 0)).
+		Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	as.NotEmpty(token.AccessToken)
+	as.Len(token.RecoveryCodes, 10)
+	auth = "Bearer " + token.AccessToken
+
+	e.POST(baseAPI+"/current/2fa/disable").WithHeader("Authorization", auth).
+		WithJSON(schema.TwoFactorCodeForm{Code: token.RecoveryCodes[0]}).Expect().Status(http.StatusBadRequest)
+
+	e.POST(baseAPI+"/current/2fa/recovery-codes").WithHeader("Authorization", auth).
+		WithJSON(schema.TwoFactorCodeForm{Code: totpCode(t, setup.Secret, 1)}).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &recovery})
+	as.Len(recovery.Codes, 10)
+	as.NotEqual(token.RecoveryCodes[0], recovery.Codes[0])
+}
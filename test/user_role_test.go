@@ -0,0 +1,117 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/supermicah/go-framework-admin/internal/config"
+	"github.com/supermicah/go-framework-admin/internal/mods/rbac/schema"
+	"github.com/supermicah/go-framework-admin/pkg/util"
+)
+
+func TestUserRoleWindow(t *testing.T) {
+	e := tester(t)
+	as := assert.New(t)
+
+	createRole := func(code string) schema.Role {
+		var role schema.Role
+		e.POST(baseAPI + "/roles").WithJSON(schema.RoleForm{
+			Code:   code,
+			Name:   code,
+			Status: schema.RoleStatusEnabled,
+		}).Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &role})
+		return role
+	}
+	permanent := createRole("window-permanent")
+	expired := createRole("window-expired")
+	upcoming := createRole("window-upcoming")
+
+	now := time.Now()
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	userForm := schema.UserForm{
+		Username: "window",
+		Name:     "Window",
+		Password: "test123456",
+		Status:   schema.UserStatusActivated,
+		Roles: schema.UserRoles{
+			{RoleID: permanent.ID},
+			{RoleID: expired.ID, StartAt: at(-2 * time.Hour), ExpireAt: at(-time.Hour)},
+			{RoleID: upcoming.ID, StartAt: at(time.Hour), ExpireAt: at(2 * time.Hour)},
+		},
+	}
+	var user schema.User
+	e.POST(baseAPI + "/users").WithJSON(userForm).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &user})
+
+	invalidForm := userForm
+	invalidForm.Username = "window-invalid"
+	invalidForm.Roles = schema.UserRoles{{RoleID: permanent.ID, StartAt: at(time.Hour), ExpireAt: at(time.Hour)}}
+	e.POST(baseAPI + "/users").WithJSON(invalidForm).Expect().Status(http.StatusBadRequest)
+
+	var token schema.LoginToken
+	login(e, "window", "test123456").Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	var current schema.User
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", "Bearer "+token.AccessToken).
+		Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &current})
+	if as.Len(current.Roles, 1) {
+		as.Equal(permanent.ID, current.Roles[0].RoleID)
+	}
+
+	getRoles := func() schema.UserRoles {
+		var item schema.User
+		e.GET(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).
+			Expect().Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &item})
+		return item.Roles
+	}
+	roles := getRoles()
+	as.Len(roles, 3)
+	for _, item := range roles {
+		if item.RoleID == upcoming.ID && as.NotNil(item.StartAt) && as.NotNil(item.ExpireAt) {
+			as.WithinDuration(now.Add(time.Hour), *item.StartAt, time.Second)
+			as.WithinDuration(now.Add(2*time.Hour), *item.ExpireAt, time.Second)
+		}
+	}
+
+	// The expired assignment is purged, the others are kept until they expire
+	as.Nil(userRoles.Sweep(context.Background(), now.Add(-time.Minute), now))
+	roles = getRoles()
+	if as.Len(roles, 2) {
+		as.NotContains(roles.ToRoleIDs(), expired.ID)
+	}
+	as.Nil(userRoles.Sweep(context.Background(), now, now.Add(3*time.Hour)))
+	roles = getRoles()
+	if as.Len(roles, 1) {
+		as.Equal(permanent.ID, roles[0].RoleID)
+	}
+
+	// The cached roles of a signed in user do not outlive the assignment, even if no sweep runs
+	userForm.Roles = schema.UserRoles{
+		{RoleID: permanent.ID},
+		{RoleID: expired.ID, ExpireAt: at(time.Since(now) + time.Second)},
+	}
+	e.PUT(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).WithJSON(userForm).Expect().Status(http.StatusOK)
+	login(e, "window", "test123456").Status(http.StatusOK).JSON().Decode(&util.ResponseResult{Data: &token})
+	e.GET(baseAPI+"/current/user").WithHeader("Authorization", "Bearer "+token.AccessToken).Expect().Status(http.StatusOK)
+	cacheKey := fmt.Sprintf("%d", user.ID)
+	exists, err := cache.Exists(context.Background(), config.CacheNSForUser, cacheKey)
+	as.Nil(err)
+	as.True(exists)
+	time.Sleep(time.Until(*userForm.Roles[1].ExpireAt) + 100*time.Millisecond)
+	exists, err = cache.Exists(context.Background(), config.CacheNSForUser, cacheKey)
+	as.Nil(err)
+	as.False(exists)
+
+	e.DELETE(fmt.Sprintf("%s/users/%d", baseAPI, user.ID)).Expect().Status(http.StatusOK)
+	for _, role := range []schema.Role{permanent, expired, upcoming} {
+		e.DELETE(fmt.Sprintf("%s/roles/%d", baseAPI, role.ID)).Expect().Status(http.StatusOK)
+	}
+}